@@ -0,0 +1,623 @@
+package main
+
+import (
+	"apart_score/pkg/codec"
+	"apart_score/pkg/expr"
+	"apart_score/pkg/scoring"
+	"apart_score/pkg/server"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// formatText is the human-readable output; the other -format values are codec formats.
+const formatText = "text"
+
+// options holds the flags shared by the scoring subcommands.
+type options struct {
+	input       string
+	inputFormat string
+	weights     string
+	profile     string
+	profiles    string
+	scenario    string
+	strategy    string
+	format      string
+	limit       int
+	allowCSV    bool       // CSV 출력 지원 여부 (rank 전용)
+	derive      stringList // 파생 요소 정의 '요소=식'
+}
+
+func newFlagSet(name string, out io.Writer) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(out)
+	return fs
+}
+
+func (o *options) register(fs *flag.FlagSet) {
+	fs.StringVar(&o.input, "input", "", "아파트 데이터 파일 (JSON, YAML, CSV; -는 표준 입력)")
+	fs.StringVar(&o.inputFormat, "input-format", "", "입력 형식 (생략하면 확장자로 판단, 표준 입력은 json)")
+	fs.Var(&o.derive, "derive", "식으로 계산할 요소 '요소=식' (반복 가능, 순서대로 계산, 예: '교통 접근성=max(역까지거리, 주변편의시설)')")
+	o.registerProfile(fs)
+	formats := "text, json, yaml"
+	if o.allowCSV {
+		formats += ", csv"
+	}
+	fs.StringVar(&o.format, "format", formatText, "출력 형식 ("+formats+")")
+}
+
+// registerProfile registers the flags choosing the weights and strategy to score with.
+func (o *options) registerProfile(fs *flag.FlagSet) {
+	fs.StringVar(&o.weights, "weights", "", "가중치 파일 (JSON, YAML, CSV; 지정하면 -scenario 대신 사용)")
+	fs.StringVar(&o.profile, "profile", "", "저장된 프로필 이름 (가중치, 전략, 제약 조건; -weights, -scenario 대신 사용)")
+	registerProfilesDir(fs, &o.profiles)
+	fs.StringVar(&o.scenario, "scenario", "", "가중치 시나리오 (기본값: balanced)")
+	fs.StringVar(&o.strategy, "strategy", "", "계산 전략 (기본값: 프로필의 전략 또는 weighted_sum)")
+}
+
+// resolve validates the flags and returns the profile to score with; without -profile the
+// flags describe an unnamed profile.
+func (o *options) resolve() (scoring.ScoringProfile, error) {
+	if err := checkFormat(o.format, o.allowCSV); err != nil {
+		return scoring.ScoringProfile{}, err
+	}
+	return o.resolveProfile()
+}
+
+// resolveProfile returns the profile chosen by the flags registered with registerProfile.
+func (o *options) resolveProfile() (scoring.ScoringProfile, error) {
+	var p scoring.ScoringProfile
+	switch {
+	case o.profile != "":
+		if o.weights != "" || o.scenario != "" {
+			return p, fmt.Errorf("-profile은 -weights, -scenario와 함께 사용할 수 없습니다")
+		}
+		store, err := openStore(o.profiles)
+		if err != nil {
+			return p, err
+		}
+		if p, err = store.Load(o.profile); err != nil {
+			return p, err
+		}
+	case o.weights != "":
+		weights, err := loadWeights(o.weights)
+		if err != nil {
+			return p, err
+		}
+		p.Weights = weights
+	case o.scenario != "":
+		p.Scenario = scoring.ScoringScenario(o.scenario)
+		if _, exists := scoring.ScenarioDefinitions[p.Scenario]; !exists {
+			return p, fmt.Errorf("알 수 없는 시나리오: %s (사용 가능: %v)", o.scenario, scoring.GetAllScenarios())
+		}
+	}
+	if o.strategy != "" {
+		p.Method = scoring.StrategyType(o.strategy)
+	}
+	if _, exists := scoring.LookupStrategy(p.Strategy()); !exists {
+		return p, fmt.Errorf("알 수 없는 전략: %s (사용 가능: %v)", p.Method, scoring.GetAvailableStrategies())
+	}
+	return p, nil
+}
+
+// loadApartments reads the -input apartments and computes the -derive factors.
+func (o *options) loadApartments() ([]scoring.ApartmentData, error) {
+	derived, err := codec.ParseDerivedFactors(o.derive)
+	if err != nil {
+		return nil, fmt.Errorf("-derive: %w", err)
+	}
+	apartments, err := loadApartments(o.input, o.inputFormat)
+	if err != nil {
+		return nil, err
+	}
+	return derived.ApplyAll(apartments)
+}
+
+func checkFormat(format string, allowCSV bool) error {
+	if format == formatText || (format == string(codec.FormatCSV) && allowCSV) {
+		return nil
+	}
+	if f, err := codec.ParseFormat(format); err == nil && f != codec.FormatCSV {
+		return nil
+	}
+	supported := "text, json, yaml"
+	if allowCSV {
+		supported += ", csv"
+	}
+	return fmt.Errorf("알 수 없는 출력 형식: %s (%s)", format, supported)
+}
+
+// write encodes v in a structured output format.
+func write(out io.Writer, format string, v interface{}) error {
+	f, err := codec.ParseFormat(format)
+	if err != nil {
+		return err
+	}
+	return codec.Encode(out, f, v)
+}
+
+func runScore(args []string, out io.Writer) error {
+	var opts options
+	var id, pipelinePath string
+	fs := newFlagSet("score", out)
+	opts.register(fs)
+	fs.StringVar(&id, "id", "", "점수를 계산할 아파트 ID (생략하면 전체)")
+	fs.StringVar(&pipelinePath, "pipeline", "", "선언형 계산 파이프라인 파일 (JSON, YAML; 지정하면 전략 대신 사용)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	profile, err := opts.resolve()
+	if err != nil {
+		return err
+	}
+	var pipeline *scoring.CalculationPipeline
+	if pipelinePath != "" {
+		loaded, err := loadPipeline(pipelinePath)
+		if err != nil {
+			return err
+		}
+		pipeline = &loaded
+	}
+	apartments, err := opts.loadApartments()
+	if err != nil {
+		return err
+	}
+	if id != "" {
+		apt, err := findApartment(apartments, id)
+		if err != nil {
+			return err
+		}
+		apartments = []scoring.ApartmentData{apt}
+	}
+
+	outputs := make([]codec.ScoredApartmentRecord, 0, len(apartments))
+	for _, apt := range apartments {
+		var result scoring.ScoreResult
+		var trace *scoring.PipelineTrace
+		if pipeline != nil {
+			result, trace, err = scoring.CalculateWithPipelineTrace(profile.ValueFunctions.Apply(apt.Scores), profile.EffectiveWeights(), *pipeline)
+		} else {
+			result, err = scoring.CalculateWithProfile(apt.Scores, profile)
+		}
+		if err != nil {
+			return fmt.Errorf("아파트 %s: %w", apt.ID, err)
+		}
+		if opts.format != formatText {
+			output := codec.NewScoredApartmentRecord(apt, result)
+			output.Trace = trace
+			outputs = append(outputs, output)
+			continue
+		}
+		fmt.Fprintf(out, "[%s] %s\n", apt.ID, apt.Name)
+		if trace != nil {
+			fmt.Fprintln(out, scoring.FormatPipelineTrace(trace))
+			continue
+		}
+		fmt.Fprintln(out, scoring.FormatScoreResult(result))
+	}
+	if opts.format != formatText {
+		return write(out, opts.format, outputs)
+	}
+	return nil
+}
+
+func runRank(args []string, out io.Writer) error {
+	opts := options{allowCSV: true}
+	var pareto paretoFlags
+	var rules stringList
+	fs := newFlagSet("rank", out)
+	opts.register(fs)
+	fs.IntVar(&opts.limit, "limit", 10, "표시할 순위 수 (0이면 전체)")
+	fs.Var(&rules, "rule", "순위 계산 전에 만족해야 하는 조건 식 (반복 가능, 예: '학군 >= 80 or 교통접근성 >= 90')")
+	fs.BoolVar(&pareto.enabled, "pareto", false, "다른 아파트에 지배되는 아파트를 순위 계산 전에 제외")
+	fs.Var(&pareto.factors, "pareto-factor", "지배 관계를 비교할 요소 (반복 가능, 지정하면 -pareto 적용, 기본값: 모든 요소)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	profile, err := opts.resolve()
+	if err != nil {
+		return err
+	}
+	for _, source := range rules {
+		rule, err := expr.Parse(source)
+		if err != nil {
+			return fmt.Errorf("-rule: %w", err)
+		}
+		profile.Constraints.Rules = append(profile.Constraints.Rules, rule)
+	}
+	if err := profile.Constraints.Validate(); err != nil {
+		return fmt.Errorf("-rule: %w", err)
+	}
+	apartments, err := opts.loadApartments()
+	if err != nil {
+		return err
+	}
+	var rankingOpts scoring.RankingOptions
+	if rankingOpts.Pareto, err = pareto.options(); err != nil {
+		return err
+	}
+	summary, err := scoring.CalculateRankingsWithProfile(apartments, profile, rankingOpts)
+	if err != nil {
+		return err
+	}
+	if opts.format != formatText {
+		if opts.limit > 0 && opts.limit < len(summary.TopRanked) {
+			summary.TopRanked = summary.TopRanked[:opts.limit]
+		}
+		f, err := codec.ParseFormat(opts.format)
+		if err != nil {
+			return err
+		}
+		return codec.EncodeRankings(out, f, summary)
+	}
+	fmt.Fprintln(out, scoring.FormatRankings(summary, opts.limit))
+	return nil
+}
+
+// paretoFlags are the flags of the Pareto prefilter.
+type paretoFlags struct {
+	enabled bool
+	factors stringList
+}
+
+// options returns the Pareto options, or nil when the filter is off.
+func (p *paretoFlags) options() (*scoring.ParetoOptions, error) {
+	if !p.enabled && len(p.factors) == 0 {
+		return nil, nil
+	}
+	factors, err := codec.ParseFactors(p.factors)
+	if err != nil {
+		return nil, err
+	}
+	return &scoring.ParetoOptions{Factors: factors}, nil
+}
+
+func runPareto(args []string, out io.Writer) error {
+	var input, inputFormat, format string
+	var factorNames stringList
+	fs := newFlagSet("pareto", out)
+	fs.StringVar(&input, "input", "", "아파트 데이터 파일 (JSON, YAML, CSV; -는 표준 입력)")
+	fs.StringVar(&inputFormat, "input-format", "", "입력 형식 (생략하면 확장자로 판단, 표준 입력은 json)")
+	fs.Var(&factorNames, "factor", "비교할 요소 (반복 가능, 기본값: 모든 요소)")
+	fs.StringVar(&format, "format", formatText, "출력 형식 (text, json, yaml)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkFormat(format, false); err != nil {
+		return err
+	}
+	factors, err := codec.ParseFactors(factorNames)
+	if err != nil {
+		return err
+	}
+	apartments, err := loadApartments(input, inputFormat)
+	if err != nil {
+		return err
+	}
+	analysis, err := scoring.AnalyzePareto(apartments, scoring.ParetoOptions{Factors: factors})
+	if err != nil {
+		return err
+	}
+	if format != formatText {
+		return write(out, format, codec.NewParetoRecord(analysis))
+	}
+	fmt.Fprint(out, scoring.FormatParetoAnalysis(analysis))
+	return nil
+}
+
+func runCompare(args []string, out io.Writer) error {
+	var opts options
+	var idA, idB string
+	fs := newFlagSet("compare", out)
+	opts.register(fs)
+	fs.StringVar(&idA, "a", "", "첫 번째 아파트 ID")
+	fs.StringVar(&idB, "b", "", "두 번째 아파트 ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if idA == "" || idB == "" {
+		return fmt.Errorf("비교할 두 아파트 ID가 필요합니다 (-a, -b)")
+	}
+	profile, err := opts.resolve()
+	if err != nil {
+		return err
+	}
+	apartments, err := opts.loadApartments()
+	if err != nil {
+		return err
+	}
+	aptA, err := findApartment(apartments, idA)
+	if err != nil {
+		return err
+	}
+	aptB, err := findApartment(apartments, idB)
+	if err != nil {
+		return err
+	}
+	comparison, err := scoring.CompareApartments(aptA, aptB, profile)
+	if err != nil {
+		return err
+	}
+	if opts.format != formatText {
+		return write(out, opts.format, codec.NewComparisonRecord(comparison))
+	}
+	fmt.Fprintf(out, "%s vs %s\n", aptA.Name, aptB.Name)
+	fmt.Fprint(out, scoring.FormatComparison(comparison))
+	return nil
+}
+
+// goalFlags are the flags describing a counterfactual goal.
+type goalFlags struct {
+	target  float64
+	beat    string
+	changes stringList
+}
+
+func (g *goalFlags) register(fs *flag.FlagSet) {
+	fs.Float64Var(&g.target, "target", 0, "도달할 목표 총점 (0-100)")
+	fs.StringVar(&g.beat, "beat", "", "순위에서 앞지를 아파트 ID (-target 대신 사용)")
+	fs.Var(&g.changes, "change", "바꿀 수 있는 내부 요인 (반복 가능, 기본값: 모든 내부 요인)")
+}
+
+func (g *goalFlags) isSet() bool {
+	return g.target != 0 || g.beat != ""
+}
+
+// goal builds the counterfactual goal for apt; the other apartments form the cohort.
+func (g *goalFlags) goal(apartments []scoring.ApartmentData, apt scoring.ApartmentData) (*scoring.CounterfactualGoal, error) {
+	if g.target != 0 && g.beat != "" {
+		return nil, fmt.Errorf("-target과 -beat는 함께 사용할 수 없습니다")
+	}
+	goal := &scoring.CounterfactualGoal{TargetScore: g.target}
+	if g.beat != "" {
+		if g.beat == apt.ID {
+			return nil, &scoring.ValidationError{Field: "rival", Message: fmt.Sprintf("경쟁 아파트가 대상 아파트와 같습니다 (%s)", apt.ID)}
+		}
+		rival, err := findApartment(apartments, g.beat)
+		if err != nil {
+			return nil, err
+		}
+		goal.Rival = &rival
+	}
+	for _, other := range apartments {
+		if other.ID != apt.ID {
+			goal.Cohort = append(goal.Cohort, other)
+		}
+	}
+	changeable, err := codec.ParseFactors(g.changes)
+	if err != nil {
+		return nil, err
+	}
+	if len(changeable) > 0 {
+		goal.Changeable = changeable
+	}
+	return goal, nil
+}
+
+func runWhatIf(args []string, out io.Writer) error {
+	var opts options
+	var goalOpts goalFlags
+	var id string
+	fs := newFlagSet("whatif", out)
+	opts.register(fs)
+	goalOpts.register(fs)
+	fs.StringVar(&id, "id", "", "대상 아파트 ID")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if id == "" || !goalOpts.isSet() {
+		return fmt.Errorf("대상 아파트(-id)와 목표(-target 또는 -beat)가 필요합니다")
+	}
+	profile, err := opts.resolve()
+	if err != nil {
+		return err
+	}
+	apartments, err := opts.loadApartments()
+	if err != nil {
+		return err
+	}
+	apt, err := findApartment(apartments, id)
+	if err != nil {
+		return err
+	}
+	goal, err := goalOpts.goal(apartments, apt)
+	if err != nil {
+		return err
+	}
+	counterfactual, err := scoring.ExplainCounterfactual(apt, profile, *goal)
+	if err != nil {
+		return err
+	}
+	if opts.format != formatText {
+		return write(out, opts.format, codec.NewCounterfactualRecord(counterfactual))
+	}
+	fmt.Fprintf(out, "[%s] %s (%s)\n", apt.ID, apt.Name, profile.Strategy())
+	fmt.Fprint(out, scoring.FormatCounterfactual(counterfactual))
+	return nil
+}
+
+// parsePreferences turns "-prefer A>B>C" rankings into pairwise preferences.
+func parsePreferences(rankings []string) ([]scoring.Preference, error) {
+	var preferences []scoring.Preference
+	for _, ranking := range rankings {
+		ids := strings.Split(ranking, ">")
+		for i, id := range ids {
+			if ids[i] = strings.TrimSpace(id); ids[i] == "" || len(ids) < 2 {
+				return nil, fmt.Errorf("-prefer는 'A>B[>C...]' 형식이어야 합니다: %q", ranking)
+			}
+		}
+		preferences = append(preferences, scoring.PreferencesFromRanking(ids...)...)
+	}
+	return preferences, nil
+}
+
+func runLearn(args []string, out io.Writer) error {
+	var opts options
+	var rankings stringList
+	var margin float64
+	var save string
+	fs := newFlagSet("learn", out)
+	opts.register(fs)
+	fs.Var(&rankings, "prefer", "선호 순서 'A>B>C' (아파트 ID, 반복 가능)")
+	fs.Float64Var(&margin, "margin", scoring.DefaultPreferenceMargin, "선호하는 아파트가 앞서야 할 최소 점수 차이")
+	fs.StringVar(&save, "save", "", "학습된 가중치를 이 이름의 프로필로 저장")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if len(rankings) == 0 {
+		return fmt.Errorf("선호 순서가 필요합니다 (-prefer)")
+	}
+	preferences, err := parsePreferences(rankings)
+	if err != nil {
+		return err
+	}
+	profile, err := opts.resolve()
+	if err != nil {
+		return err
+	}
+	apartments, err := opts.loadApartments()
+	if err != nil {
+		return err
+	}
+	learned, err := scoring.LearnWeights(apartments, preferences, profile, scoring.PreferenceLearningOptions{Margin: margin})
+	if err != nil {
+		return err
+	}
+	if save != "" {
+		store, err := openStore(opts.profiles)
+		if err != nil {
+			return err
+		}
+		learned.Profile.Name = save
+		if err := store.Save(learned.Profile); err != nil {
+			return err
+		}
+	}
+	if opts.format != formatText {
+		return write(out, opts.format, codec.NewLearnedWeightsRecord(learned))
+	}
+	fmt.Fprint(out, scoring.FormatLearnedWeights(learned))
+	if save != "" {
+		fmt.Fprintf(out, "\n프로필 저장: %s\n", save)
+	}
+	return nil
+}
+
+func runDashboard(args []string, out io.Writer) error {
+	var opts options
+	var goalOpts goalFlags
+	var id, pipelinePath string
+	var seed int64
+	fs := newFlagSet("dashboard", out)
+	opts.register(fs)
+	goalOpts.register(fs)
+	fs.StringVar(&id, "id", "", "대시보드를 생성할 아파트 ID (생략하면 첫 번째 아파트)")
+	fs.Int64Var(&seed, "seed", 1, "시뮬레이션 난수 시드")
+	fs.StringVar(&pipelinePath, "pipeline", "", "선언형 계산 파이프라인 파일 (JSON, YAML; 지정하면 전략 대신 사용하고 단계별 기록 표시)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	profile, err := opts.resolve()
+	if err != nil {
+		return err
+	}
+	var pipeline *scoring.CalculationPipeline
+	if pipelinePath != "" {
+		if goalOpts.isSet() {
+			return fmt.Errorf("-pipeline은 목표 옵션과 함께 사용할 수 없습니다")
+		}
+		loaded, err := loadPipeline(pipelinePath)
+		if err != nil {
+			return err
+		}
+		pipeline = &loaded
+	}
+	apartments, err := opts.loadApartments()
+	if err != nil {
+		return err
+	}
+	if len(apartments) == 0 {
+		return fmt.Errorf("아파트 데이터가 비어 있습니다")
+	}
+	apt := apartments[0]
+	if id != "" {
+		if apt, err = findApartment(apartments, id); err != nil {
+			return err
+		}
+	}
+	var result scoring.ScoreResult
+	if pipeline != nil {
+		result, err = scoring.CalculateWithPipeline(profile.ValueFunctions.Apply(apt.Scores), profile.EffectiveWeights(), *pipeline)
+	} else {
+		result, err = scoring.CalculateWithProfile(apt.Scores, profile)
+	}
+	if err != nil {
+		return fmt.Errorf("아파트 %s: %w", apt.ID, err)
+	}
+
+	// 파일의 모든 아파트를 비교 대상으로 사용
+	dashboardOpts := scoring.DashboardOptions{Cohort: apartments, Seed: seed, ValueFunctions: profile.ValueFunctions, Pipeline: pipeline}
+	if goalOpts.isSet() {
+		if dashboardOpts.Goal, err = goalOpts.goal(apartments, apt); err != nil {
+			return err
+		}
+	}
+	dashboard, err := scoring.GenerateTransparencyDashboardWithOptions(result, apt.Scores, profile.EffectiveWeights(), profile.Strategy(),
+		dashboardOpts)
+	if err != nil {
+		return err
+	}
+	if opts.format != formatText {
+		return write(out, opts.format, dashboard)
+	}
+	fmt.Fprintf(out, "[%s] %s\n", apt.ID, apt.Name)
+	fmt.Fprintln(out, scoring.FormatTransparencyDashboard(dashboard))
+	return nil
+}
+
+func runScenarios(args []string, out io.Writer) error {
+	var format string
+	fs := newFlagSet("scenarios", out)
+	fs.StringVar(&format, "format", formatText, "출력 형식 (text, json, yaml)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkFormat(format, false); err != nil {
+		return err
+	}
+
+	outputs := server.Scenarios()
+	if format != formatText {
+		return write(out, format, outputs)
+	}
+	for _, s := range outputs {
+		fmt.Fprintf(out, "%-16s %s - %s\n", s.ID, s.Name, s.Description)
+		fmt.Fprintf(out, "%-16s 대상: %s / 사례: %s\n", "", s.TargetUser, s.UseCase)
+	}
+	return nil
+}
+
+func runStrategies(args []string, out io.Writer) error {
+	var format string
+	fs := newFlagSet("strategies", out)
+	fs.StringVar(&format, "format", formatText, "출력 형식 (text, json, yaml)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if err := checkFormat(format, false); err != nil {
+		return err
+	}
+
+	outputs := server.Strategies()
+	if format != formatText {
+		return write(out, format, outputs)
+	}
+	for _, s := range outputs {
+		fmt.Fprintf(out, "%-16s %s\n", s.ID, s.Description)
+		if s.WhenToUse != "" {
+			fmt.Fprintf(out, "%-16s 사용 시점: %s\n", "", s.WhenToUse)
+		}
+	}
+	return nil
+}
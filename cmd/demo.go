@@ -0,0 +1,140 @@
+package main
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/scoring"
+	"apart_score/pkg/shared"
+	"fmt"
+	"io"
+)
+
+// runDemo prints the original walkthrough of scoring, custom weights, the dashboard,
+// factor types and calculation pipelines.
+func runDemo(out io.Writer) error {
+	fmt.Fprintln(out, "아파트 스코어링 시스템 시작")
+	for _, mt := range metadata.All() {
+		fmt.Fprintf(out, "%d: %s (%s)\n", mt.Index(), mt.String(), mt.KoreanName())
+	}
+	fmt.Fprintln(out, "\n=== 아파트 스코어링 예제 ===")
+	apartmentScores := map[metadata.MetadataType]shared.ScoreValue{
+		metadata.FloorLevel:           shared.ScoreValueFromFloat(85.0),
+		metadata.DistanceToStation:    shared.ScoreValueFromFloat(95.0),
+		metadata.ElevatorPresence:     shared.ScoreValueFromFloat(100.0),
+		metadata.ConstructionYear:     shared.ScoreValueFromFloat(90.0),
+		metadata.ConstructionCompany:  shared.ScoreValueFromFloat(85.0),
+		metadata.ApartmentSize:        shared.ScoreValueFromFloat(75.0),
+		metadata.NearbyAmenities:      shared.ScoreValueFromFloat(80.0),
+		metadata.TransportationAccess: shared.ScoreValueFromFloat(90.0),
+		metadata.SchoolDistrict:       shared.ScoreValueFromFloat(70.0),
+		metadata.CrimeRate:            shared.ScoreValueFromFloat(65.0),
+		metadata.GreenSpaceRatio:      shared.ScoreValueFromFloat(60.0),
+		metadata.Parking:              shared.ScoreValueFromFloat(80.0),
+		metadata.MaintenanceFee:       shared.ScoreValueFromFloat(75.0),
+		metadata.HeatingSystem:        shared.ScoreValueFromFloat(70.0),
+	}
+	weights := scoring.GetScenarioWeights(scoring.ScenarioBalanced)
+	result, err := scoring.CalculateWithStrategy(apartmentScores, weights, scoring.StrategyWeightedSum)
+	if err != nil {
+		return fmt.Errorf("스코어링 실패: %w", err)
+	}
+	fmt.Fprintln(out, "🏠 아파트 스코어 결과")
+	fmt.Fprintln(out, "━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Fprintf(out, "총점: %.1f점\n", result.TotalScore)
+	fmt.Fprintf(out, "방법: %s\n", result.Method)
+	fmt.Fprintf(out, "시나리오: %s\n", result.Scenario)
+	fmt.Fprintln(out, "\n📊 상세 점수:")
+	for _, mt := range metadata.All() {
+		idx := int(mt)
+		rawScore := result.RawScores[idx]
+		weight := result.Weights[idx]
+		weighted := result.WeightedScores[idx]
+		if rawScore != 0 {
+			fmt.Fprintf(out, "  %-20s: %.1f점 (가중치: %.1f%%) → %.1f점\n",
+				mt.KoreanName(), rawScore.ToFloat(), weight.ToFloat()*100, weighted)
+		}
+	}
+	fmt.Fprintln(out, "\n=== 사용자 정의 스코어링 테이블 예제 ===")
+	customWeights := map[metadata.MetadataType]shared.Weight{
+		metadata.FloorLevel:           shared.WeightFromFloat(0.10),
+		metadata.DistanceToStation:    shared.WeightFromFloat(0.30),
+		metadata.ElevatorPresence:     shared.WeightFromFloat(0.08),
+		metadata.ConstructionYear:     shared.WeightFromFloat(0.05),
+		metadata.ConstructionCompany:  shared.WeightFromFloat(0.02),
+		metadata.ApartmentSize:        shared.WeightFromFloat(0.02),
+		metadata.NearbyAmenities:      shared.WeightFromFloat(0.15),
+		metadata.TransportationAccess: shared.WeightFromFloat(0.25),
+		metadata.SchoolDistrict:       shared.WeightFromFloat(0.00),
+		metadata.CrimeRate:            shared.WeightFromFloat(0.00),
+		metadata.GreenSpaceRatio:      shared.WeightFromFloat(0.00),
+		metadata.Parking:              shared.WeightFromFloat(0.05),
+		metadata.MaintenanceFee:       shared.WeightFromFloat(0.03),
+		metadata.HeatingSystem:        shared.WeightFromFloat(0.00),
+	}
+	customWeights = shared.NormalizeWeights(customWeights)
+	customResult, err := scoring.CalculateWithStrategy(apartmentScores, customWeights, scoring.StrategyWeightedSum)
+	if err != nil {
+		return fmt.Errorf("사용자 정의 스코어링 실패: %w", err)
+	}
+	fmt.Fprintln(out, "🎯 교통 최우선 스코어링 테이블 결과:")
+	fmt.Fprintf(out, "총점: %.1f점 (기존: %.1f점, 차이: %.1f점)\n",
+		customResult.TotalScore, result.TotalScore,
+		customResult.TotalScore-result.TotalScore)
+
+	// === 투명성 대시보드 ===
+	fmt.Fprintln(out, "\n🔍 투명성 평가 대시보드")
+	fmt.Fprintln(out, "═══════════════════════════════════════════════")
+
+	dashboard := scoring.GenerateTransparencyDashboard(result, apartmentScores, weights, scoring.StrategyWeightedSum)
+	fmt.Fprintln(out, scoring.FormatTransparencyDashboard(dashboard))
+	fmt.Fprintln(out, "\n=== 메타데이터 팩터 타입 예제 ===")
+	fmt.Fprintln(out, "디폴트 팩터 타입 설정:")
+	for _, mt := range metadata.All() {
+		fmt.Fprintf(out, "  %s: %s\n", mt.KoreanName(), mt.FactorType())
+	}
+	fmt.Fprintln(out, "\n내부 요인 (아파트 자체 속성):")
+	internalFactors := metadata.ByFactorType(metadata.FactorInternal)
+	for _, mt := range internalFactors {
+		fmt.Fprintf(out, "  - %s\n", mt.KoreanName())
+	}
+	fmt.Fprintln(out, "\n외부 요인 (주변 환경):")
+	externalFactors := metadata.ByFactorType(metadata.FactorExternal)
+	for _, mt := range externalFactors {
+		fmt.Fprintf(out, "  - %s\n", mt.KoreanName())
+	}
+	fmt.Fprintln(out, "\n팩터 타입 변경 예제:")
+	fmt.Fprintf(out, "변경 전 - 층수: %s\n", metadata.FloorLevel.FactorType())
+	if err := metadata.SetFactorType(metadata.FloorLevel, metadata.FactorExternal); err != nil {
+		fmt.Fprintf(out, "팩터 타입 변경 실패: %v\n", err)
+	} else {
+		fmt.Fprintf(out, "변경 후 - 층수: %s\n", metadata.FloorLevel.FactorType())
+		if err := metadata.SetFactorType(metadata.FloorLevel, metadata.FactorInternal); err != nil {
+			fmt.Fprintf(out, "팩터 타입 복원 실패: %v\n", err)
+		}
+		fmt.Fprintf(out, "복원 후 - 층수: %s\n", metadata.FloorLevel.FactorType())
+	}
+
+	// === 연산 순서 조정 파이프라인 예제 ===
+	fmt.Fprintln(out, "\n=== 연산 순서 조정 파이프라인 예제 ===")
+	familyPipeline := scoring.CreateFamilyPipeline()
+	fmt.Fprintf(out, "파이프라인: %s\n", familyPipeline.Name)
+	fmt.Fprintf(out, "설명: %s\n", familyPipeline.Description)
+
+	pipelineResult, trace, err := scoring.CalculateWithPipelineTrace(apartmentScores, weights, familyPipeline)
+	if err != nil {
+		return fmt.Errorf("파이프라인 계산 실패: %w", err)
+	}
+
+	fmt.Fprintln(out, "계산 단계:")
+	for i, step := range familyPipeline.Steps {
+		fmt.Fprintf(out, "  %d. %s (%d순위)\n", i+1, step.Name, step.Priority)
+		fmt.Fprintf(out, "     %s\n", step.Description)
+	}
+	fmt.Fprint(out, scoring.FormatPipelineTrace(trace))
+
+	// 기존 방식과 비교
+	fmt.Fprintf(out, "\n비교:\n")
+	fmt.Fprintf(out, "  기존 Weighted Sum: %.1f점\n", result.TotalScore)
+	fmt.Fprintf(out, "  파이프라인 방식: %.1f점\n", pipelineResult.TotalScore)
+	fmt.Fprintf(out, "  차이: %.1f점\n", pipelineResult.TotalScore-result.TotalScore)
+	return nil
+}
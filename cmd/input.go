@@ -0,0 +1,107 @@
+package main
+
+import (
+	"apart_score/pkg/codec"
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/scoring"
+	"apart_score/pkg/shared"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// openInput opens a file, or standard input when path is "-", and resolves its format.
+// The format comes from override when set, otherwise from the file extension; standard input defaults to JSON.
+func openInput(path, override string) (io.ReadCloser, codec.Format, error) {
+	format := codec.FormatJSON
+	var err error
+	switch {
+	case override != "":
+		format, err = codec.ParseFormat(override)
+	case path != "-":
+		format, err = codec.FormatFromPath(path)
+	}
+	if err != nil {
+		return nil, "", err
+	}
+	if path == "-" {
+		return io.NopCloser(os.Stdin), format, nil
+	}
+	file, err := os.Open(path)
+	return file, format, err
+}
+
+// loadApartments reads apartments from a JSON, YAML or CSV file.
+func loadApartments(path, format string) ([]scoring.ApartmentData, error) {
+	if path == "" {
+		return nil, fmt.Errorf("아파트 데이터 파일이 필요합니다 (-input)")
+	}
+	r, f, err := openInput(path, format)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	apartments, err := codec.DecodeApartments(r, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return apartments, nil
+}
+
+// loadWeights reads a weight profile of factor name to relative weight, normalized to sum to 1000.
+func loadWeights(path string) (map[metadata.MetadataType]shared.Weight, error) {
+	r, f, err := openInput(path, "")
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	weights, err := codec.DecodeWeights(r, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return weights, nil
+}
+
+// loadValueFunctions reads per-factor value functions keyed by factor name.
+func loadValueFunctions(path string) (scoring.ValueFunctions, error) {
+	r, f, err := openInput(path, "")
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	functions, err := codec.DecodeValueFunctions(r, f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return functions, nil
+}
+
+// loadPipeline reads a declarative pipeline and compiles it.
+func loadPipeline(path string) (scoring.CalculationPipeline, error) {
+	r, f, err := openInput(path, "")
+	if err != nil {
+		return scoring.CalculationPipeline{}, err
+	}
+	defer r.Close()
+	pipeline, err := codec.LoadPipeline(r, f)
+	if err != nil {
+		return scoring.CalculationPipeline{}, fmt.Errorf("%s: %w", path, err)
+	}
+	return pipeline, nil
+}
+
+// findApartment returns the apartment with the given ID.
+func findApartment(apartments []scoring.ApartmentData, id string) (scoring.ApartmentData, error) {
+	for _, apt := range apartments {
+		if apt.ID == id {
+			return apt, nil
+		}
+	}
+	ids := make([]string, len(apartments))
+	for i, apt := range apartments {
+		ids[i] = apt.ID
+	}
+	sort.Strings(ids)
+	return scoring.ApartmentData{}, fmt.Errorf("아파트 %q를 찾을 수 없습니다 (사용 가능: %v)", id, ids)
+}
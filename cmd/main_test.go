@@ -0,0 +1,276 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"apart_score/pkg/codec"
+)
+
+const exampleApartments = "../examples/apartments.json"
+
+func TestRunRank(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"rank", "-input", exampleApartments, "-strategy", "topsis", "-limit", "2"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("rank exit code = %d, stderr: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "1위") {
+		t.Errorf("rank output should list rankings, got:\n%s", stdout.String())
+	}
+}
+
+func TestRunRankExpressions(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	args := []string{"rank", "-input", exampleApartments, "-format", "json",
+		"-derive", "학군 = min(학군, 70)", "-derive", "transportation_access = max(TransportationAccess, 0)",
+		"-rule", "학군 >= 70 or 교통접근성 >= 90"}
+	if code := run(args, &stdout, &stderr); code != 0 {
+		t.Fatalf("rank exit code = %d, stderr: %s", code, stderr.String())
+	}
+	var plain bytes.Buffer
+	if code := run([]string{"rank", "-input", exampleApartments, "-format", "json"}, &plain, &stderr); code != 0 {
+		t.Fatalf("rank exit code = %d, stderr: %s", code, stderr.String())
+	}
+	if stdout.String() == plain.String() {
+		t.Error("derived factors and rules do not change the rankings")
+	}
+
+	stderr.Reset()
+	if code := run([]string{"rank", "-input", exampleApartments, "-rule", "학군 >= 70 or"}, &stdout, &stderr); code != 1 ||
+		!strings.Contains(stderr.String(), "-rule") || !strings.Contains(stderr.String(), "^") {
+		t.Errorf("invalid rule: exit code %d, stderr: %s", code, stderr.String())
+	}
+}
+
+func TestRunScoreJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"score", "-input", exampleApartments, "-id", "villa-04", "-format", "json"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("score exit code = %d, stderr: %s", code, stderr.String())
+	}
+	var outputs []codec.ScoredApartmentRecord
+	if err := json.Unmarshal(stdout.Bytes(), &outputs); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if len(outputs) != 1 || outputs[0].ID != "villa-04" {
+		t.Fatalf("expected only villa-04, got %+v", outputs)
+	}
+	if outputs[0].TotalScore <= 0 || outputs[0].TotalScore > 100 {
+		t.Errorf("total score out of range: %f", outputs[0].TotalScore)
+	}
+}
+
+func TestRunScorePipeline(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"score", "-input", exampleApartments, "-pipeline", "../examples/pipeline.yaml", "-format", "json"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("score exit code = %d, stderr: %s", code, stderr.String())
+	}
+	var outputs []codec.ScoredApartmentRecord
+	if err := json.Unmarshal(stdout.Bytes(), &outputs); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if len(outputs) != 4 {
+		t.Fatalf("expected 4 results, got %+v", outputs)
+	}
+	for _, output := range outputs {
+		if output.TotalScore < 0 || output.TotalScore > 100 {
+			t.Errorf("%s: pipeline total out of range: %f", output.ID, output.TotalScore)
+		}
+		if output.Trace == nil || len(output.Trace.Steps) != 6 || output.Trace.TotalScore != output.TotalScore {
+			t.Errorf("%s: trace %+v", output.ID, output.Trace)
+		}
+	}
+
+	stdout.Reset()
+	code = run([]string{"dashboard", "-input", exampleApartments, "-pipeline", "../examples/pipeline.yaml"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("dashboard exit code = %d, stderr: %s", code, stderr.String())
+	}
+	if !strings.Contains(stdout.String(), "조건 불충족으로 건너뜀") {
+		t.Errorf("dashboard does not show the pipeline trace:\n%s", stdout.String())
+	}
+}
+
+func TestRunProfile(t *testing.T) {
+	dir := t.TempDir()
+	steps := [][]string{
+		{"profile", "save", "-profiles", dir, "-scenario", "transportation", "-strategy", "min_max",
+			"-exclude", "경기도", "-min-score", "역까지 거리=80", "commuter"},
+		{"rank", "-profiles", dir, "-profile", "commuter", "-input", exampleApartments, "-format", "json"},
+	}
+	var stdout, stderr bytes.Buffer
+	for _, args := range steps {
+		stdout.Reset()
+		if code := run(args, &stdout, &stderr); code != 0 {
+			t.Fatalf("%v: exit code %d, stderr: %s", args, code, stderr.String())
+		}
+	}
+	var rankings struct {
+		Strategy   string            `json:"strategy"`
+		Eliminated []json.RawMessage `json:"eliminated"`
+	}
+	if err := json.Unmarshal(stdout.Bytes(), &rankings); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	// 일산(경기도)은 제외 지역, 역까지 거리 80점 미만은 최소 점수 조건으로 제외
+	if rankings.Strategy != "min_max" || len(rankings.Eliminated) == 0 {
+		t.Errorf("profile not applied: %s", stdout.String())
+	}
+
+	stdout.Reset()
+	if code := run([]string{"profile", "list", "-profiles", dir}, &stdout, &stderr); code != 0 || !strings.Contains(stdout.String(), "commuter") {
+		t.Errorf("profile list: exit %d, output %s", code, stdout.String())
+	}
+	if code := run([]string{"score", "-profiles", dir, "-profile", "missing", "-input", exampleApartments}, &stdout, &stderr); code != 1 {
+		t.Errorf("unknown profile: exit code %d, want 1", code)
+	}
+}
+
+func TestRunCompareJSON(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"compare", "-input", exampleApartments, "-a", "mapo-01", "-b", "villa-04", "-format", "json"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("compare exit code = %d, stderr: %s", code, stderr.String())
+	}
+	var record codec.ComparisonRecord
+	if err := json.Unmarshal(stdout.Bytes(), &record); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if record.Winner != "mapo-01" || len(record.Factors) == 0 || len(record.Decisive) == 0 || len(record.Alternatives) == 0 {
+		t.Errorf("unexpected comparison: %+v", record)
+	}
+}
+
+func TestRunWhatIf(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"whatif", "-input", exampleApartments, "-id", "villa-04", "-beat", "mapo-01", "-format", "json"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("whatif exit code = %d, stderr: %s", code, stderr.String())
+	}
+	var record codec.CounterfactualRecord
+	if err := json.Unmarshal(stdout.Bytes(), &record); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if !record.Achievable || record.RivalID != "mapo-01" || len(record.Changes) == 0 || record.NewScore <= record.TargetScore {
+		t.Errorf("unexpected counterfactual: %+v", record)
+	}
+}
+
+func TestRunLearn(t *testing.T) {
+	dir := t.TempDir()
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"learn", "-input", exampleApartments, "-prefer", "villa-04 > mapo-01", "-profiles", dir, "-save", "learned",
+		"-format", "json"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("learn exit code = %d, stderr: %s", code, stderr.String())
+	}
+	var record codec.LearnedWeightsRecord
+	if err := json.Unmarshal(stdout.Bytes(), &record); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if len(record.Violated) != 0 || len(record.Preferences) != 1 || record.Preferences[0].PriorDifference >= 0 {
+		t.Errorf("unexpected learned weights: %+v", record)
+	}
+
+	stdout.Reset()
+	code = run([]string{"compare", "-input", exampleApartments, "-profiles", dir, "-profile", "learned", "-a", "villa-04", "-b", "mapo-01",
+		"-format", "json"}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("compare exit code = %d, stderr: %s", code, stderr.String())
+	}
+	var comparison codec.ComparisonRecord
+	if err := json.Unmarshal(stdout.Bytes(), &comparison); err != nil || comparison.Winner != "villa-04" {
+		t.Errorf("saved profile does not prefer villa-04: %+v, %v", comparison, err)
+	}
+}
+
+func TestRunPareto(t *testing.T) {
+	factors := []string{"-pareto-factor", "Elevator Presence", "-pareto-factor", "Construction Year", "-pareto-factor", "School District"}
+	var stdout, stderr bytes.Buffer
+	code := run(append([]string{"rank", "-input", exampleApartments, "-format", "json"}, factors...), &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("rank exit code = %d, stderr: %s", code, stderr.String())
+	}
+	var rankings codec.RankingsRecord
+	if err := json.Unmarshal(stdout.Bytes(), &rankings); err != nil {
+		t.Fatalf("invalid JSON output: %v", err)
+	}
+	if len(rankings.Rankings) != 3 || len(rankings.Eliminated) != 1 || rankings.Eliminated[0].Apartment.ID != "villa-04" ||
+		len(rankings.Eliminated[0].Violations[0].DominatedBy) != 3 {
+		t.Errorf("pareto prefilter: %+v", rankings)
+	}
+
+	stdout.Reset()
+	if code := run([]string{"pareto", "-input", exampleApartments}, &stdout, &stderr); code != 0 || !strings.Contains(stdout.String(), "파레토 최적 4개") {
+		t.Errorf("pareto: exit %d, output %s", code, stdout.String())
+	}
+}
+
+func TestRunStream(t *testing.T) {
+	stats := filepath.Join(t.TempDir(), "stats.json")
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"stream", "-input", "../examples/apartments.ndjson", "-scenario", "family_friendly", "-stats", stats}, &stdout, &stderr)
+	if code != 0 {
+		t.Fatalf("stream exit code = %d, stderr: %s", code, stderr.String())
+	}
+	lines := strings.Split(strings.TrimSpace(stdout.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 4 NDJSON lines, got:\n%s", stdout.String())
+	}
+	var first codec.ScoredApartmentRecord
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil || first.ID != "mapo-01" || first.TotalScore <= 0 {
+		t.Errorf("first line: %+v, %v", first, err)
+	}
+	data, err := os.ReadFile(stats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var summary codec.StreamSummary
+	if err := json.Unmarshal(data, &summary); err != nil || summary.Records != 4 || summary.Stats.Count != 4 || summary.Stats.Max < first.TotalScore {
+		t.Errorf("stats: %s, %v", data, err)
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		code int
+	}{
+		{"no command", nil, 2},
+		{"unknown command", []string{"bogus"}, 2},
+		{"missing input", []string{"rank"}, 1},
+		{"unknown strategy", []string{"rank", "-input", exampleApartments, "-strategy", "bogus"}, 1},
+		{"unknown apartment", []string{"score", "-input", exampleApartments, "-id", "bogus"}, 1},
+		{"whatif without goal", []string{"whatif", "-input", exampleApartments, "-id", "villa-04"}, 1},
+		{"whatif beat itself", []string{"whatif", "-input", exampleApartments, "-id", "villa-04", "-beat", "villa-04"}, 1},
+		{"dashboard beat itself", []string{"dashboard", "-input", exampleApartments, "-id", "villa-04", "-beat", "villa-04"}, 1},
+		{"whatif external factor", []string{"whatif", "-input", exampleApartments, "-id", "villa-04", "-target", "90", "-change", "School District"}, 1},
+		{"learn without preferences", []string{"learn", "-input", exampleApartments}, 1},
+		{"learn bad preference", []string{"learn", "-input", exampleApartments, "-prefer", "villa-04"}, 1},
+		{"pareto unknown factor", []string{"pareto", "-input", exampleApartments, "-factor", "bogus"}, 1},
+		{"invalid pipeline", []string{"score", "-input", exampleApartments, "-pipeline", "../examples/weights.json"}, 1},
+		{"dashboard pipeline with goal", []string{"dashboard", "-input", exampleApartments, "-pipeline", "../examples/pipeline.yaml", "-target", "90"}, 1},
+		{"stream non-NDJSON input", []string{"stream", "-input", exampleApartments}, 1},
+		{"stream CSV stats", []string{"stream", "-input", "../examples/apartments.ndjson", "-stats", "stats.csv"}, 1},
+		{"rank number rule", []string{"rank", "-input", exampleApartments, "-rule", "학군 + 1"}, 1},
+		{"derive without expression", []string{"rank", "-input", exampleApartments, "-derive", "학군"}, 1},
+		{"derive unknown factor", []string{"score", "-input", exampleApartments, "-derive", "Ocean View=학군"}, 1},
+		{"help", []string{"help"}, 0},
+		{"openapi", []string{"serve", "-openapi"}, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+			if code := run(tt.args, &stdout, &stderr); code != tt.code {
+				t.Errorf("exit code = %d, want %d (stderr: %s)", code, tt.code, stderr.String())
+			}
+		})
+	}
+}
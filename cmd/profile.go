@@ -0,0 +1,210 @@
+package main
+
+import (
+	"apart_score/pkg/codec"
+	"apart_score/pkg/profile"
+	"apart_score/pkg/scoring"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// stringList is a repeatable string flag.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ",") }
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+func registerProfilesDir(fs *flag.FlagSet, dir *string) {
+	fs.StringVar(dir, "profiles", "", "프로필 디렉터리 (기본값: $"+profile.DirEnv+" 또는 사용자 설정 디렉터리)")
+}
+
+// openStore opens the profile store in dir, or in the default directory when dir is empty.
+func openStore(dir string) (*profile.Store, error) {
+	if dir == "" {
+		var err error
+		if dir, err = profile.DefaultDir(); err != nil {
+			return nil, err
+		}
+	}
+	return profile.NewStore(dir), nil
+}
+
+// profileCommands are the subcommands of "profile".
+var profileCommands = []struct {
+	name, usage string
+	run         func(args []string, out io.Writer) error
+}{
+	{"list", "저장된 프로필 목록", runProfileList},
+	{"show", "<이름> 프로필 출력", runProfileShow},
+	{"save", "[옵션] <이름> 프로필 저장", runProfileSave},
+	{"delete", "<이름> 프로필 삭제", runProfileDelete},
+	{"migrate", "이전 스키마 버전의 프로필을 현재 버전으로 다시 저장", runProfileMigrate},
+}
+
+func runProfile(args []string, out io.Writer) error {
+	if len(args) > 0 {
+		for _, cmd := range profileCommands {
+			if cmd.name == args[0] {
+				return cmd.run(args[1:], out)
+			}
+		}
+	}
+	fmt.Fprintln(out, "사용법: apart_score profile <명령> [-profiles 디렉터리] ...")
+	for _, cmd := range profileCommands {
+		fmt.Fprintf(out, "  %-8s %s\n", cmd.name, cmd.usage)
+	}
+	switch {
+	case len(args) == 0:
+		return fmt.Errorf("profile 명령이 필요합니다")
+	case args[0] == "-h" || args[0] == "help":
+		return flag.ErrHelp
+	}
+	return fmt.Errorf("알 수 없는 profile 명령: %s", args[0])
+}
+
+// parseProfileFlags parses a profile subcommand's flags and opens the store. With wantName it
+// requires exactly one positional profile name.
+func parseProfileFlags(fs *flag.FlagSet, args []string, wantName bool) (*profile.Store, string, error) {
+	var dir string
+	registerProfilesDir(fs, &dir)
+	if err := fs.Parse(args); err != nil {
+		return nil, "", err
+	}
+	var name string
+	if wantName {
+		if fs.NArg() != 1 {
+			return nil, "", fmt.Errorf("프로필 이름 하나가 필요합니다")
+		}
+		name = fs.Arg(0)
+	}
+	store, err := openStore(dir)
+	return store, name, err
+}
+
+func runProfileList(args []string, out io.Writer) error {
+	store, _, err := parseProfileFlags(newFlagSet("profile list", out), args, false)
+	if err != nil {
+		return err
+	}
+	names, err := store.List()
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Fprintf(out, "저장된 프로필이 없습니다 (%s)\n", store.Dir())
+		return nil
+	}
+	for _, name := range names {
+		p, err := store.Load(name)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "%-20s %-16s %s\n", p.Name, p.Strategy(), p.Description)
+	}
+	return nil
+}
+
+func runProfileShow(args []string, out io.Writer) error {
+	store, name, err := parseProfileFlags(newFlagSet("profile show", out), args, true)
+	if err != nil {
+		return err
+	}
+	p, err := store.Load(name)
+	if err != nil {
+		return err
+	}
+	return profile.Encode(out, p)
+}
+
+func runProfileDelete(args []string, out io.Writer) error {
+	store, name, err := parseProfileFlags(newFlagSet("profile delete", out), args, true)
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(name); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "프로필 삭제: %s\n", name)
+	return nil
+}
+
+func runProfileMigrate(args []string, out io.Writer) error {
+	store, _, err := parseProfileFlags(newFlagSet("profile migrate", out), args, false)
+	if err != nil {
+		return err
+	}
+	migrated, err := store.Migrate()
+	for _, name := range migrated {
+		fmt.Fprintf(out, "변환: %s (스키마 버전 %d)\n", name, profile.SchemaVersion)
+	}
+	if err != nil {
+		return err
+	}
+	if len(migrated) == 0 {
+		fmt.Fprintln(out, "변환할 프로필이 없습니다")
+	}
+	return nil
+}
+
+func runProfileSave(args []string, out io.Writer) error {
+	var description, weights, scenario, strategy, values string
+	var minScores, required, excluded, rules stringList
+	fs := newFlagSet("profile save", out)
+	fs.StringVar(&description, "description", "", "프로필 설명")
+	fs.StringVar(&weights, "weights", "", "가중치 파일 (JSON, YAML, CSV)")
+	fs.StringVar(&scenario, "scenario", "", "기준 시나리오 (가중치 파일이 없으면 이 시나리오의 가중치 사용)")
+	fs.StringVar(&strategy, "strategy", "", "계산 전략 (기본값: weighted_sum)")
+	fs.StringVar(&values, "values", "", "요소별 가치 함수 파일 (JSON, YAML)")
+	fs.Var(&minScores, "min-score", "요소별 최소 점수 '요소=점수' (반복 가능)")
+	fs.Var(&required, "require", "반드시 있어야 하는 요소 (반복 가능)")
+	fs.Var(&excluded, "exclude", "제외할 지역 (반복 가능)")
+	fs.Var(&rules, "rule", "만족해야 하는 조건 식 (반복 가능, 예: '학군 >= 80 or 교통접근성 >= 90')")
+	store, name, err := parseProfileFlags(fs, args, true)
+	if err != nil {
+		return err
+	}
+
+	p := scoring.ScoringProfile{
+		Name:        name,
+		Description: description,
+		Method:      scoring.StrategyType(strategy),
+		Scenario:    scoring.ScoringScenario(scenario),
+	}
+	if weights != "" {
+		if p.Weights, err = loadWeights(weights); err != nil {
+			return err
+		}
+	}
+	if values != "" {
+		if p.ValueFunctions, err = loadValueFunctions(values); err != nil {
+			return err
+		}
+	}
+	record := codec.ConstraintsRecord{RequiredFeatures: required, ExcludedLocations: excluded, Rules: rules}
+	for _, entry := range minScores {
+		factor, value, ok := strings.Cut(entry, "=")
+		points, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if !ok || err != nil {
+			return fmt.Errorf("-min-score는 '요소=점수' 형식이어야 합니다: %q", entry)
+		}
+		if record.MinScores == nil {
+			record.MinScores = make(map[string]float64)
+		}
+		record.MinScores[factor] = points
+	}
+	if p.Constraints, err = record.Constraints(); err != nil {
+		return err
+	}
+	if err := store.Save(p); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "프로필 저장: %s (%s)\n", name, store.Dir())
+	return nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"apart_score/pkg/server"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// shutdownTimeout bounds how long in-flight requests may run after an interrupt.
+const shutdownTimeout = 10 * time.Second
+
+func runServe(args []string, out io.Writer) error {
+	fs := newFlagSet("serve", out)
+	addr := fs.String("addr", ":8080", "수신 주소")
+	maxBody := fs.Int64("max-body", server.DefaultMaxBodyBytes, "요청 본문 최대 크기 (바이트)")
+	printSpec := fs.Bool("openapi", false, "서버를 시작하지 않고 OpenAPI 문서만 출력")
+	var profiles string
+	registerProfilesDir(fs, &profiles)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *printSpec {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(server.OpenAPI())
+	}
+
+	store, err := openStore(profiles)
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := &http.Server{
+		Addr:              *addr,
+		Handler:           server.New(server.Options{MaxBodyBytes: *maxBody, Profiles: store}),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.ListenAndServe()
+	}()
+	fmt.Fprintf(out, "API 서버 시작: %s (문서: /openapi.json, 프로필: %s)\n", *addr, store.Dir())
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+	}
+	fmt.Fprintln(out, "종료 중...")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("서버 종료 실패: %w", err)
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
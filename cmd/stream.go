@@ -0,0 +1,74 @@
+package main
+
+import (
+	"apart_score/pkg/codec"
+	"apart_score/pkg/scoring"
+	"fmt"
+	"io"
+	"os"
+)
+
+// runStream scores NDJSON apartments line by line, writing NDJSON score results to out.
+// Statistics and skipped records go to standard error so that out stays NDJSON.
+func runStream(args []string, out io.Writer) error {
+	var opts options
+	var input, stats string
+	var skipInvalid bool
+	fs := newFlagSet("stream", out)
+	opts.registerProfile(fs)
+	fs.StringVar(&input, "input", "-", "NDJSON 아파트 파일 (한 줄에 아파트 하나; -는 표준 입력)")
+	fs.StringVar(&stats, "stats", "", "점수 통계를 저장할 파일 (JSON, YAML; 생략하면 표준 오류에 텍스트로 출력)")
+	fs.BoolVar(&skipInvalid, "skip-invalid", false, "읽거나 점수를 계산할 수 없는 줄을 건너뛰고 표준 오류에 알림")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	var statsFormat codec.Format
+	if stats != "" {
+		format, err := codec.FormatFromPath(stats)
+		if err != nil {
+			return err
+		}
+		if format == codec.FormatCSV {
+			return fmt.Errorf("점수 통계는 CSV로 저장할 수 없습니다: %s", stats)
+		}
+		statsFormat = format
+	}
+	profile, err := opts.resolveProfile()
+	if err != nil {
+		return err
+	}
+
+	r := io.Reader(os.Stdin)
+	if input != "-" {
+		file, err := os.Open(input)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		r = file
+	}
+	streamOpts := codec.StreamOptions{SkipInvalid: skipInvalid, OnSkip: func(err *codec.LineError) {
+		fmt.Fprintf(os.Stderr, "건너뜀: %v\n", err)
+	}}
+	summary, err := codec.ScoreStream(r, out, profile, streamOpts)
+	if err != nil {
+		return fmt.Errorf("%s: %w", input, err)
+	}
+
+	if stats == "" {
+		fmt.Fprint(os.Stderr, scoring.FormatRunningStats(summary.Stats))
+		if summary.Skipped > 0 {
+			fmt.Fprintf(os.Stderr, "레코드 %d개 중 %d개 건너뜀\n", summary.Records, summary.Skipped)
+		}
+		return nil
+	}
+	file, err := os.Create(stats)
+	if err != nil {
+		return err
+	}
+	if err := codec.Encode(file, statsFormat, summary); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,7 @@
+// Package metadatatest gives tests in this module access to the metadata registry.
+// Being internal, it cannot be imported by library users.
+package metadatatest
+
+// Reset removes every registered custom metadata type, leaving only the built-in types.
+// The metadata package installs it; tests that register types call it from t.Cleanup.
+var Reset func()
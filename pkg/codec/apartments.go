@@ -0,0 +1,222 @@
+package codec
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/scoring"
+	"apart_score/pkg/shared"
+	"bytes"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+)
+
+// FactorScores maps factor names to points (0-100); nil or an absent factor marks missing data.
+type FactorScores map[string]*float64
+
+// FactorWeights maps factor names to weights.
+type FactorWeights map[string]float64
+
+// ApartmentRecord is the file representation of scoring.ApartmentData.
+type ApartmentRecord struct {
+	ID       string       `json:"id"`
+	Name     string       `json:"name"`
+	Location string       `json:"location,omitempty"`
+	Scores   FactorScores `json:"scores"`
+}
+
+// NewApartmentRecord converts an apartment to its file representation; missing factors are left out.
+func NewApartmentRecord(apt scoring.ApartmentData) ApartmentRecord {
+	record := ApartmentRecord{ID: apt.ID, Name: apt.Name, Location: apt.Location, Scores: FactorScores{}}
+	for mt, score := range apt.Scores {
+		if score.IsMissing() {
+			continue
+		}
+		value := score.ToFloat()
+		record.Scores[FactorName(mt)] = &value
+	}
+	return record
+}
+
+// Apartment converts the record back to scoring.ApartmentData.
+func (r ApartmentRecord) Apartment() (scoring.ApartmentData, error) {
+	scores, err := r.Scores.resolve()
+	if err != nil {
+		return scoring.ApartmentData{}, fmt.Errorf("아파트 %s: %w", r.ID, err)
+	}
+	return scoring.ApartmentData{ID: r.ID, Name: r.Name, Location: r.Location, Scores: scores}, nil
+}
+
+func (s FactorScores) resolve() (map[metadata.MetadataType]shared.ScoreValue, error) {
+	scores := make(map[metadata.MetadataType]shared.ScoreValue, len(s))
+	for name, points := range s {
+		mt, err := ParseFactor(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, duplicate := scores[mt]; duplicate {
+			return nil, fmt.Errorf("요소가 중복되었습니다: %s", FactorName(mt))
+		}
+		if points == nil {
+			scores[mt] = shared.MissingScore
+			continue
+		}
+		if scores[mt], err = scoreFromFloat(*points); err != nil {
+			return nil, fmt.Errorf("%s: %w", FactorName(mt), err)
+		}
+	}
+	// 적지 않은 요소는 CSV의 빈 칸과 같이 데이터 없음 (0점이 아님)
+	for _, mt := range metadata.All() {
+		if _, ok := scores[mt]; !ok {
+			scores[mt] = shared.MissingScore
+		}
+	}
+	return scores, nil
+}
+
+// EncodeApartments writes apartments in the given format.
+// CSV has one row per apartment with a column per factor; missing data is an empty cell.
+func EncodeApartments(w io.Writer, format Format, apartments []scoring.ApartmentData) error {
+	if format == FormatCSV {
+		return writeApartmentsCSV(w, apartments)
+	}
+	records := make([]ApartmentRecord, len(apartments))
+	for i, apt := range apartments {
+		records[i] = NewApartmentRecord(apt)
+	}
+	return Encode(w, format, records)
+}
+
+// DecodeApartments reads apartments in the given format. An empty ID defaults to the
+// 1-based position and an empty name to the ID.
+func DecodeApartments(r io.Reader, format Format) ([]scoring.ApartmentData, error) {
+	var records []ApartmentRecord
+	var err error
+	if format == FormatCSV {
+		records, err = readApartmentsCSV(r)
+	} else {
+		err = unmarshal(r, format, &records)
+	}
+	if err != nil {
+		return nil, err
+	}
+	apartments := make([]scoring.ApartmentData, 0, len(records))
+	for i, record := range records {
+		if record.ID == "" {
+			record.ID = fmt.Sprintf("%d", i+1)
+		}
+		if record.Name == "" {
+			record.Name = record.ID
+		}
+		apt, err := record.Apartment()
+		if err != nil {
+			return nil, err
+		}
+		apartments = append(apartments, apt)
+	}
+	return apartments, nil
+}
+
+// NewFactorWeights converts weights to their file representation (fractions summing to 1).
+func NewFactorWeights(weights map[metadata.MetadataType]shared.Weight) FactorWeights {
+	result := make(FactorWeights, len(weights))
+	for mt, w := range weights {
+		if w != 0 {
+			result[FactorName(mt)] = w.ToFloat()
+		}
+	}
+	return result
+}
+
+// Weights resolves factor names. Fractions that already sum to 1 within the scoring tolerance
+// are kept as written; anything else is treated as relative weights and normalized to sum to
+// exactly shared.WeightScale, distributing rounding by largest remainder.
+func (f FactorWeights) Weights() (map[metadata.MetadataType]shared.Weight, error) {
+	relative := make(map[metadata.MetadataType]float64, len(f))
+	total := 0.0
+	for name, w := range f {
+		mt, err := ParseFactor(name)
+		if err != nil {
+			return nil, err
+		}
+		if _, duplicate := relative[mt]; duplicate {
+			return nil, fmt.Errorf("요소가 중복되었습니다: %s", FactorName(mt))
+		}
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return nil, fmt.Errorf("%s 가중치는 0 이상의 유한한 값이어야 합니다", FactorName(mt))
+		}
+		relative[mt] = w
+		total += w
+	}
+	if total == 0 {
+		return nil, fmt.Errorf("가중치 합계가 0입니다")
+	}
+	asWritten := make(map[metadata.MetadataType]shared.Weight, len(relative))
+	sum := shared.Weight(0)
+	for mt, w := range relative {
+		asWritten[mt] = weightFromFloat(w)
+		sum += asWritten[mt]
+	}
+	if sum >= shared.WeightScale-1 && sum <= shared.WeightScale+1 {
+		return asWritten, nil
+	}
+
+	type share struct {
+		mt        metadata.MetadataType
+		remainder float64
+	}
+	weights := make(map[metadata.MetadataType]shared.Weight, len(relative))
+	shares := make([]share, 0, len(relative))
+	assigned := shared.Weight(0)
+	for mt, w := range relative {
+		exact := w / total * shared.WeightScale
+		weights[mt] = shared.Weight(math.Floor(exact))
+		assigned += weights[mt]
+		shares = append(shares, share{mt, exact - math.Floor(exact)})
+	}
+	// 최대 잔여 방식으로 합계를 정확히 1000으로 맞춤
+	sort.Slice(shares, func(i, j int) bool {
+		if shares[i].remainder != shares[j].remainder {
+			return shares[i].remainder > shares[j].remainder
+		}
+		return shares[i].mt < shares[j].mt
+	})
+	for i := 0; assigned < shared.WeightScale; i++ {
+		weights[shares[i%len(shares)].mt]++
+		assigned++
+	}
+	return weights, nil
+}
+
+// EncodeWeights writes a weight profile in the given format.
+// CSV has a factor,weight row per weighted factor.
+func EncodeWeights(w io.Writer, format Format, weights map[metadata.MetadataType]shared.Weight) error {
+	if format == FormatCSV {
+		return writeWeightsCSV(w, weights)
+	}
+	return Encode(w, format, NewFactorWeights(weights))
+}
+
+// DecodeWeights reads a weight profile of relative weights and normalizes it (see FactorWeights.Weights).
+// JSON and YAML documents with a top-level "groups" key are read as a weight tree and flattened.
+func DecodeWeights(r io.Reader, format Format) (map[metadata.MetadataType]shared.Weight, error) {
+	var weights FactorWeights
+	if format == FormatCSV {
+		var err error
+		if weights, err = readWeightsCSV(r); err != nil {
+			return nil, err
+		}
+		return weights.Weights()
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if isWeightTree(data, format) {
+		return decodeTreeWeights(data, format)
+	}
+	if err := unmarshal(bytes.NewReader(data), format, &weights); err != nil {
+		return nil, err
+	}
+	return weights.Weights()
+}
@@ -0,0 +1,157 @@
+// Package codec reads and writes apartments, weight profiles and scoring results as JSON, YAML and CSV.
+// Factors are keyed by their English name; Korean names are accepted when decoding.
+//
+// YAML is read by a built-in parser for the subset these records need:
+//   - block mappings and sequences indented with spaces, including "- key: value" items
+//   - plain, single- and double-quoted scalars and "#" comments
+//   - flow collections written as JSON, or single-level ones of scalars such as {층수: 50} or [a, b]
+//
+// Anchors, aliases, tags, block scalars (| and >), nested non-JSON flow collections, tabs and
+// multiple documents are rejected with an error, as are empty ("-") and null sequence items.
+package codec
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"path/filepath"
+	"strings"
+)
+
+// Format is a file format supported by the codecs.
+type Format string
+
+const (
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+	FormatCSV  Format = "csv"
+)
+
+// ParseFormat resolves a format name; "yml" is accepted as YAML.
+func ParseFormat(name string) (Format, error) {
+	switch strings.ToLower(name) {
+	case "json":
+		return FormatJSON, nil
+	case "yaml", "yml":
+		return FormatYAML, nil
+	case "csv":
+		return FormatCSV, nil
+	}
+	return "", fmt.Errorf("지원하지 않는 형식: %q (json, yaml, csv)", name)
+}
+
+// FormatFromPath infers the format from a file extension.
+func FormatFromPath(path string) (Format, error) {
+	ext := strings.TrimPrefix(filepath.Ext(path), ".")
+	if ext == "" {
+		return "", fmt.Errorf("파일 확장자로 형식을 알 수 없습니다: %s", path)
+	}
+	return ParseFormat(ext)
+}
+
+// UnknownFactorError reports a factor name that matches no registered metadata type.
+type UnknownFactorError struct {
+	Name string
+}
+
+func (e *UnknownFactorError) Error() string {
+	return fmt.Sprintf("알 수 없는 요소 이름: %q (영문 또는 한글 요소 이름을 사용하세요)", e.Name)
+}
+
+// ParseFactor resolves an English or Korean factor name. Like factor names in expressions, a
+// name also matches when case, spaces and underscores are ignored (FloorLevel, 역까지_거리).
+func ParseFactor(name string) (metadata.MetadataType, error) {
+	name = strings.TrimSpace(name)
+	if mt, ok := metadata.GetByEnglishName(name); ok {
+		return mt, nil
+	}
+	if mt, ok := metadata.GetByKoreanName(name); ok {
+		return mt, nil
+	}
+	if mt, ok := metadata.GetByIdentifier(name); ok {
+		return mt, nil
+	}
+	return 0, &UnknownFactorError{Name: name}
+}
+
+// ParseFactors resolves a list of factor names.
+func ParseFactors(names []string) ([]metadata.MetadataType, error) {
+	factors := make([]metadata.MetadataType, 0, len(names))
+	for _, name := range names {
+		mt, err := ParseFactor(name)
+		if err != nil {
+			return nil, err
+		}
+		factors = append(factors, mt)
+	}
+	return factors, nil
+}
+
+// FactorName returns the canonical (English) name a factor is written under.
+func FactorName(mt metadata.MetadataType) string {
+	return mt.String()
+}
+
+// scoreFromFloat converts points to a ScoreValue, rounding rather than truncating.
+func scoreFromFloat(points float64) (shared.ScoreValue, error) {
+	if math.IsNaN(points) || points < 0 || points > 100 {
+		return 0, fmt.Errorf("점수는 0-100 범위여야 합니다 (%g)", points)
+	}
+	return shared.ScoreValue(math.Round(points * shared.ScoreScale)), nil
+}
+
+func weightFromFloat(w float64) shared.Weight {
+	return shared.Weight(math.Round(w * shared.WeightScale))
+}
+
+// Encode writes any JSON-serializable value as indented JSON or as YAML with the same
+// field names and order. CSV is only available through the typed encoders. Characters such as
+// '<' and '>' are written as is rather than HTML-escaped.
+func Encode(w io.Writer, format Format, v interface{}) error {
+	switch format {
+	case FormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		encoder.SetEscapeHTML(false)
+		return encoder.Encode(v)
+	case FormatYAML:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		out, err := jsonToYAML(data)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(out)
+		return err
+	}
+	return fmt.Errorf("지원하지 않는 형식: %q", format)
+}
+
+// unmarshal decodes JSON or YAML into v, rejecting unknown fields.
+func unmarshal(r io.Reader, format Format, v interface{}) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case FormatJSON:
+	case FormatYAML:
+		if data, err = yamlToJSON(data); err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("지원하지 않는 형식: %q", format)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		return fmt.Errorf("%s 파싱 실패: %w", format, err)
+	}
+	return nil
+}
@@ -0,0 +1,435 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"apart_score/pkg/expr"
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/scoring"
+	"apart_score/pkg/shared"
+)
+
+// testApartments returns apartments as decoding yields them: factors not given are missing.
+func testApartments() []scoring.ApartmentData {
+	apartments := []scoring.ApartmentData{
+		{
+			ID: "a1", Name: "래미안: 1단지", Location: "서울시 마포구",
+			Scores: map[metadata.MetadataType]shared.ScoreValue{
+				metadata.FloorLevel:        shared.ScoreValueFromFloat(85.5),
+				metadata.DistanceToStation: shared.ScoreValueFromFloat(90),
+				metadata.ElevatorPresence:  shared.MissingScore,
+			},
+		},
+		{
+			ID: "a2", Name: "true", Location: "",
+			Scores: map[metadata.MetadataType]shared.ScoreValue{
+				metadata.FloorLevel:        shared.ScoreValueFromFloat(60),
+				metadata.DistanceToStation: shared.ScoreValueFromFloat(70.25),
+				metadata.ElevatorPresence:  shared.ScoreValueFromFloat(100),
+			},
+		},
+	}
+	for _, apt := range apartments {
+		for _, mt := range metadata.All() {
+			if _, ok := apt.Scores[mt]; !ok {
+				apt.Scores[mt] = shared.MissingScore
+			}
+		}
+	}
+	return apartments
+}
+
+func TestApartmentsRoundTrip(t *testing.T) {
+	for _, format := range []Format{FormatJSON, FormatYAML, FormatCSV} {
+		t.Run(string(format), func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := EncodeApartments(&buf, format, testApartments()); err != nil {
+				t.Fatalf("encode failed: %v", err)
+			}
+			decoded, err := DecodeApartments(bytes.NewReader(buf.Bytes()), format)
+			if err != nil {
+				t.Fatalf("decode failed: %v\n%s", err, buf.String())
+			}
+			if !reflect.DeepEqual(decoded, testApartments()) {
+				t.Errorf("round trip mismatch:\n got %+v\nwant %+v\n%s", decoded, testApartments(), buf.String())
+			}
+		})
+	}
+}
+
+func TestDecodeApartmentsMissingAcrossFormats(t *testing.T) {
+	inputs := map[string]string{
+		"json absent": `[{"id": "m", "scores": {"Floor Level": 80}}]`,
+		"json null":   `[{"id": "m", "scores": {"Floor Level": 80, "Parking": null}}]`,
+		"yaml absent": "- id: m\n  scores:\n    Floor Level: 80\n",
+		"yaml null":   "- id: m\n  scores: {Floor Level: 80, Parking: ~}\n",
+		"csv absent":  "id,Floor Level\nm,80\n",
+		"csv empty":   "id,Floor Level,Parking\nm,80,\n",
+	}
+	weights := map[metadata.MetadataType]shared.Weight{metadata.FloorLevel: 500, metadata.Parking: 500}
+	for name, input := range inputs {
+		format, _ := ParseFormat(strings.Fields(name)[0])
+		apartments, err := DecodeApartments(strings.NewReader(input), format)
+		if err != nil {
+			t.Fatalf("%s: decode failed: %v", name, err)
+		}
+		if !apartments[0].Scores[metadata.Parking].IsMissing() || !apartments[0].Scores[metadata.HeatingSystem].IsMissing() {
+			t.Errorf("%s: factors without data should be missing: %+v", name, apartments[0].Scores)
+		}
+		// 기본 정책은 남은 가중치로 재정규화하므로 0점으로 끌어내리지 않음
+		result, err := scoring.CalculateWithMissingPolicy(apartments[0].Scores, weights, scoring.StrategyWeightedSum, scoring.MissingRenormalize)
+		if err != nil || result.TotalScore != 80 {
+			t.Errorf("%s: total %v, %v; want 80", name, result.TotalScore, err)
+		}
+	}
+}
+
+func TestDecodeApartmentsFactorNames(t *testing.T) {
+	input := `[{"id": "k1", "scores": {"층수": 80, "Distance to Station": 70}}]`
+	apartments, err := DecodeApartments(strings.NewReader(input), FormatJSON)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	if apartments[0].Name != "k1" || apartments[0].Scores[metadata.FloorLevel] != 80000 {
+		t.Errorf("Korean factor name not resolved: %+v", apartments[0])
+	}
+	input = `[{"id": "k2", "scores": {"FloorLevel": 50, "역까지_거리": 60, "elevator presence": 70}}]`
+	if apartments, err = DecodeApartments(strings.NewReader(input), FormatJSON); err != nil ||
+		apartments[0].Scores[metadata.FloorLevel] != 50000 || apartments[0].Scores[metadata.DistanceToStation] != 60000 ||
+		apartments[0].Scores[metadata.ElevatorPresence] != 70000 {
+		t.Errorf("identifier factor names not resolved: %+v, %v", apartments, err)
+	}
+	if _, err := DecodeApartments(strings.NewReader(`[{"id": "x", "scores": {"FloorLevel": 1, "Floor Level": 2}}]`), FormatJSON); err == nil {
+		t.Error("expected error for duplicate factor written as an identifier")
+	}
+
+	for name, input := range map[string]string{
+		"json": `[{"id": "x", "scores": {"Floorz": 80}}]`,
+		"csv":  "id,name,Floorz\nx,x,80\n",
+		"yaml": "- id: x\n  scores:\n    Floorz: 80\n",
+	} {
+		format, _ := ParseFormat(name)
+		_, err := DecodeApartments(strings.NewReader(input), format)
+		var unknown *UnknownFactorError
+		if !errors.As(err, &unknown) || unknown.Name != "Floorz" {
+			t.Errorf("%s: expected UnknownFactorError for Floorz, got %v", name, err)
+		}
+	}
+
+	if _, err := DecodeApartments(strings.NewReader(`[{"id": "x", "scores": {"Floor Level": 120}}]`), FormatJSON); err == nil {
+		t.Error("expected error for score above 100")
+	}
+	if _, err := DecodeApartments(strings.NewReader(`[{"id": "x", "scors": {}}]`), FormatJSON); err == nil {
+		t.Error("expected error for unknown field")
+	}
+	if _, err := DecodeApartments(strings.NewReader(`[{"id": "x", "scores": {"층수": 1, "Floor Level": 2}}]`), FormatJSON); err == nil {
+		t.Error("expected error for duplicate factor")
+	}
+	for _, input := range []string{"- id: a\n  : x\n", "a: 1\n: 2\n"} {
+		if _, err := DecodeApartments(strings.NewReader(input), FormatYAML); err == nil || !strings.Contains(err.Error(), "YAML 2행: 빈 키") {
+			t.Errorf("%q: expected empty key error, got %v", input, err)
+		}
+	}
+	if apartments, err := DecodeApartments(strings.NewReader("- id: f\n  scores: {층수: 50, 역까지_거리: 60}\n"), FormatYAML); err != nil ||
+		apartments[0].Scores[metadata.FloorLevel] != 50000 || apartments[0].Scores[metadata.DistanceToStation] != 60000 {
+		t.Errorf("plain flow scores not decoded: %+v, %v", apartments, err)
+	}
+	if _, err := DecodeApartments(strings.NewReader("- id: a\n-\n"), FormatYAML); err == nil || !strings.Contains(err.Error(), "YAML 2행") {
+		t.Errorf("bare sequence item: expected error, got %v", err)
+	}
+}
+
+func TestWeightsRoundTripAndNormalization(t *testing.T) {
+	weights := scoring.GetScenarioWeights(scoring.ScenarioTransportation)
+	for _, format := range []Format{FormatJSON, FormatYAML, FormatCSV} {
+		var buf bytes.Buffer
+		if err := EncodeWeights(&buf, format, weights); err != nil {
+			t.Fatalf("%s encode failed: %v", format, err)
+		}
+		decoded, err := DecodeWeights(&buf, format)
+		if err != nil {
+			t.Fatalf("%s decode failed: %v", format, err)
+		}
+		for _, mt := range metadata.All() {
+			if decoded[mt] != weights[mt] {
+				t.Errorf("%s: %s weight = %d, want %d", format, mt, decoded[mt], weights[mt])
+			}
+		}
+	}
+
+	decoded, err := DecodeWeights(strings.NewReader("층수: 1\nParking: 1\nCrime Rate: 1\n"), FormatYAML)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	total := shared.Weight(0)
+	for _, w := range decoded {
+		total += w
+	}
+	if total != shared.WeightScale || decoded[metadata.FloorLevel] != 334 {
+		t.Errorf("relative weights not normalized to %d: %v", shared.WeightScale, decoded)
+	}
+	if _, err := DecodeWeights(strings.NewReader(`{"Parking": -1}`), FormatJSON); err == nil {
+		t.Error("expected error for negative weight")
+	}
+}
+
+func TestScoreResultRoundTrip(t *testing.T) {
+	apt := testApartments()[0]
+	weights := map[metadata.MetadataType]shared.Weight{
+		metadata.FloorLevel: 300, metadata.DistanceToStation: 500, metadata.ElevatorPresence: 200,
+	}
+	result, err := scoring.CalculateWithStrategy(apt.Scores, weights, scoring.StrategyWeightedSum)
+	if err != nil {
+		t.Fatalf("scoring failed: %v", err)
+	}
+	for _, format := range []Format{FormatJSON, FormatYAML, FormatCSV} {
+		var buf bytes.Buffer
+		if err := EncodeScoreResult(&buf, format, result); err != nil {
+			t.Fatalf("%s encode failed: %v", format, err)
+		}
+		decoded, err := DecodeScoreResult(bytes.NewReader(buf.Bytes()), format)
+		if err != nil {
+			t.Fatalf("%s decode failed: %v\n%s", format, err, buf.String())
+		}
+		if math.Abs(decoded.TotalScore-result.TotalScore) > 1e-9 || decoded.Method != result.Method ||
+			decoded.RawScores != result.RawScores || decoded.Weights != result.Weights {
+			t.Errorf("%s round trip mismatch:\n got %+v\nwant %+v", format, decoded, result)
+		}
+	}
+}
+
+func TestRankingsRoundTrip(t *testing.T) {
+	weights := map[metadata.MetadataType]shared.Weight{
+		metadata.FloorLevel: 400, metadata.DistanceToStation: 400, metadata.ElevatorPresence: 200,
+	}
+	summary, err := scoring.CalculateRankings(testApartments(), weights, scoring.StrategyWeightedSum)
+	if err != nil {
+		t.Fatalf("ranking failed: %v", err)
+	}
+	for _, format := range []Format{FormatJSON, FormatYAML, FormatCSV} {
+		var buf bytes.Buffer
+		if err := EncodeRankings(&buf, format, summary); err != nil {
+			t.Fatalf("%s encode failed: %v", format, err)
+		}
+		decoded, err := DecodeRankings(bytes.NewReader(buf.Bytes()), format)
+		if err != nil {
+			t.Fatalf("%s decode failed: %v\n%s", format, err, buf.String())
+		}
+		if decoded.Strategy != summary.Strategy || len(decoded.TopRanked) != len(summary.TopRanked) ||
+			math.Abs(decoded.ScoreRange.Avg-summary.ScoreRange.Avg) > 1e-9 {
+			t.Fatalf("%s summary mismatch:\n got %+v\nwant %+v", format, decoded, summary)
+		}
+		for i, ranking := range decoded.TopRanked {
+			want := summary.TopRanked[i]
+			if ranking.Rank != want.Rank || ranking.Score != want.Score ||
+				!reflect.DeepEqual(ranking.Apartment, want.Apartment) {
+				t.Errorf("%s ranking %d mismatch:\n got %+v\nwant %+v", format, i, ranking, want)
+			}
+			if format != FormatCSV && ranking.Weights != want.Weights {
+				t.Errorf("%s ranking %d weights mismatch", format, i)
+			}
+		}
+	}
+}
+
+func TestClosenessRecords(t *testing.T) {
+	weights := map[metadata.MetadataType]shared.Weight{
+		metadata.FloorLevel: 400, metadata.DistanceToStation: 400, metadata.ElevatorPresence: 200,
+	}
+	apartments := testApartments()
+	for mt := range weights {
+		apartments[0].Scores[mt] = shared.ScoreValueFromFloat(90)
+		apartments[1].Scores[mt] = shared.ScoreValueFromFloat(10)
+	}
+	summary, err := scoring.CalculateRankings(apartments, weights, scoring.StrategyTOPSIS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 최악해에 놓인 아파트의 근접도 0도 기록되어야 함
+	last := summary.TopRanked[len(summary.TopRanked)-1]
+	if last.Closeness == nil || *last.Closeness != 0 {
+		t.Fatalf("anti-ideal closeness: %v", last.Closeness)
+	}
+	for _, format := range []Format{FormatJSON, FormatCSV} {
+		var buf bytes.Buffer
+		if err := EncodeRankings(&buf, format, summary); err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := DecodeRankings(&buf, format)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got := decoded.TopRanked[len(decoded.TopRanked)-1].Closeness; got == nil || *got != 0 {
+			t.Errorf("%s: anti-ideal closeness %v after round trip", format, got)
+		}
+	}
+	record := NewScoreResultRecord(scoring.ScoreResult{Method: scoring.StrategyTOPSIS})
+	if data, _ := json.Marshal(record); !strings.Contains(string(data), `"closeness":0`) {
+		t.Errorf("zero TOPSIS closeness omitted: %s", data)
+	}
+
+	weighted, err := scoring.CalculateRankings(apartments, weights, scoring.StrategyWeightedSum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	if err := EncodeRankings(&buf, FormatJSON, weighted); err != nil || strings.Contains(buf.String(), "closeness") {
+		t.Errorf("weighted sum rankings report a closeness: %v\n%s", err, buf.String())
+	}
+	if data, _ := json.Marshal(NewScoreResultRecord(scoring.ScoreResult{Method: scoring.StrategyWeightedSum})); strings.Contains(string(data), "closeness") {
+		t.Errorf("weighted sum result reports a closeness: %s", data)
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	cases := map[string]Format{"a.json": FormatJSON, "b.YML": FormatYAML, "dir/c.yaml": FormatYAML, "d.csv": FormatCSV}
+	for path, want := range cases {
+		if got, err := FormatFromPath(path); err != nil || got != want {
+			t.Errorf("FormatFromPath(%q) = %q, %v; want %q", path, got, err, want)
+		}
+	}
+	for _, path := range []string{"noext", "e.txt"} {
+		if _, err := FormatFromPath(path); err == nil {
+			t.Errorf("FormatFromPath(%q) should fail", path)
+		}
+	}
+}
+
+func TestDecodeWeightTree(t *testing.T) {
+	file, err := os.Open("../../examples/weight_tree.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	weights, err := DecodeWeights(file, FormatYAML)
+	if err != nil {
+		t.Fatalf("decode failed: %v", err)
+	}
+	// transport 4/10 × 3/5
+	if weights[metadata.DistanceToStation] != 240 || weights[metadata.MaintenanceFee] != 100 {
+		t.Errorf("unexpected flattened weights: %v", weights)
+	}
+
+	tree := scoring.CategoryWeightTree(scoring.ScenarioInvestment)
+	var buf bytes.Buffer
+	if err := EncodeWeightTree(&buf, FormatJSON, tree); err != nil {
+		t.Fatal(err)
+	}
+	decoded, err := DecodeWeightTree(&buf, FormatJSON)
+	if err != nil {
+		t.Fatalf("decode failed: %v\n%s", err, buf.String())
+	}
+	want, _ := tree.Weights()
+	if got, _ := decoded.Weights(); !reflect.DeepEqual(got, want) {
+		t.Errorf("tree round trip changed weights:\n got %v\nwant %v", got, want)
+	}
+
+	bad := `{"groups": [{"name": "a", "weight": 1, "factors": {"Ocean View": 1}}]}`
+	var unknown *UnknownFactorError
+	if _, err := DecodeWeights(strings.NewReader(bad), FormatJSON); !errors.As(err, &unknown) {
+		t.Errorf("unknown factor in tree: %v", err)
+	}
+}
+
+func TestPipelineRoundTrip(t *testing.T) {
+	spec := scoring.FamilyPipelineSpec()
+	for _, format := range []Format{FormatJSON, FormatYAML} {
+		var buf bytes.Buffer
+		if err := EncodePipeline(&buf, format, spec); err != nil {
+			t.Fatal(err)
+		}
+		decoded, err := DecodePipeline(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: decode failed: %v\n%s", format, err, buf.String())
+		}
+		if !reflect.DeepEqual(decoded, spec) {
+			t.Errorf("%s round trip:\n got %+v\nwant %+v", format, decoded, spec)
+		}
+	}
+
+	file, err := os.Open("../../examples/pipeline.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	pipeline, err := LoadPipeline(file, FormatYAML)
+	if err != nil {
+		t.Fatalf("example pipeline: %v", err)
+	}
+	if len(pipeline.Steps) != 6 || pipeline.Steps[5].Name != "점수 범위" {
+		t.Errorf("example steps: %+v", pipeline.Steps)
+	}
+
+	bad := `{"name": "p", "steps": [{"name": "s", "kind": "bonus_if", "points": 1, "when": {"factor": "Ocean View", "op": ">", "value": 1}}]}`
+	var unknown *UnknownFactorError
+	if _, err := DecodePipeline(strings.NewReader(bad), FormatJSON); !errors.As(err, &unknown) {
+		t.Errorf("unknown factor in condition: %v", err)
+	}
+}
+
+func TestExpressionRecords(t *testing.T) {
+	pipeline := `{"name": "p", "steps": [
+		{"name": "base", "kind": "formula", "formula": "0.5*학군 + 0.5*ApartmentSize"},
+		{"name": "bonus", "kind": "bonus_if", "points": 3, "when": {"expr": "total > 60 and Parking >= 50"}}]}`
+	spec, err := DecodePipeline(strings.NewReader(pipeline), FormatJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Steps[0].Formula.String() != "0.5*학군 + 0.5*ApartmentSize" || spec.Steps[1].When.Expr.String() != "total > 60 and Parking >= 50" {
+		t.Fatalf("decoded: %+v", spec.Steps)
+	}
+	var buf bytes.Buffer
+	if err := EncodePipeline(&buf, FormatYAML, spec); err != nil {
+		t.Fatal(err)
+	}
+	if decoded, err := DecodePipeline(&buf, FormatYAML); err != nil || decoded.Steps[0].Formula.String() != spec.Steps[0].Formula.String() {
+		t.Errorf("yaml round trip: %v\n%s", err, buf.String())
+	}
+	var exprErr *expr.Error
+	bad := `{"name": "p", "steps": [{"name": "s", "kind": "formula", "formula": "학군 +"}]}`
+	if _, err := DecodePipeline(strings.NewReader(bad), FormatJSON); !errors.As(err, &exprErr) {
+		t.Errorf("invalid formula: %v", err)
+	}
+
+	constraints, err := ConstraintsRecord{Rules: []string{"학군 >= 80 or 교통접근성 >= 90"}}.Constraints()
+	if err != nil || len(constraints.Rules) != 1 {
+		t.Fatalf("rules: %+v, %v", constraints, err)
+	}
+	if record := NewConstraintsRecord(constraints); !reflect.DeepEqual(record.Rules, []string{"학군 >= 80 or 교통접근성 >= 90"}) {
+		t.Errorf("record rules: %v", record.Rules)
+	}
+	summary, err := scoring.CalculateRankingsWithOptions(testApartments(), scoring.GetScenarioWeights(scoring.ScenarioBalanced),
+		scoring.StrategyWeightedSum, scoring.RankingOptions{Constraints: &scoring.Constraints{Rules: []*expr.Expr{constraints.Rules[0]}}})
+	if err != nil || len(summary.Eliminated) == 0 {
+		t.Fatalf("rule ranking: %+v, %v", summary, err)
+	}
+	buf.Reset()
+	if err := EncodeRankings(&buf, FormatJSON, summary); err != nil || !strings.Contains(buf.String(), `"rule": "학군 >= 80 or 교통접근성 >= 90"`) {
+		t.Fatalf("encoded rankings: %v\n%s", err, buf.String())
+	}
+	decoded, err := DecodeRankings(&buf, FormatJSON)
+	if err != nil || !reflect.DeepEqual(decoded.Eliminated[0].Violations, summary.Eliminated[0].Violations) {
+		t.Errorf("rule violation round trip: %v\n got %+v\nwant %+v", err, decoded, summary.Eliminated)
+	}
+	var validation *scoring.ValidationError
+	if _, err := (ConstraintsRecord{Rules: []string{"학군 * 2"}}).Constraints(); !errors.As(err, &validation) {
+		t.Errorf("number rule: %v", err)
+	}
+
+	derived, err := ParseDerivedFactors([]string{"주차장 = min(Parking, 60)", "Green Space Ratio=0.5 * Parking + 10"})
+	if err != nil || len(derived) != 2 || derived[0].Factor != metadata.Parking || derived[1].Expr.String() != "0.5 * Parking + 10" {
+		t.Fatalf("derived: %+v, %v", derived, err)
+	}
+	for _, definitions := range [][]string{{"Parking"}, {"Ocean View=1"}, {"Parking=1 >"}, {"Parking=1", "주차장=2"}} {
+		if _, err := ParseDerivedFactors(definitions); err == nil {
+			t.Errorf("%q: parsed", definitions)
+		}
+	}
+}
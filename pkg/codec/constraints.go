@@ -0,0 +1,74 @@
+package codec
+
+import (
+	"apart_score/pkg/expr"
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/scoring"
+	"apart_score/pkg/shared"
+)
+
+// ConstraintsRecord is the file representation of scoring.Constraints, keyed by factor name
+// with minimum scores in points (0-100) and rules as expression sources.
+type ConstraintsRecord struct {
+	MinScores         map[string]float64 `json:"min_scores,omitempty"`
+	RequiredFeatures  []string           `json:"required_features,omitempty"`
+	ExcludedLocations []string           `json:"excluded_locations,omitempty"`
+	Rules             []string           `json:"rules,omitempty"`
+}
+
+// NewConstraintsRecord converts constraints to their file representation.
+func NewConstraintsRecord(c scoring.Constraints) ConstraintsRecord {
+	record := ConstraintsRecord{ExcludedLocations: c.ExcludedLocations}
+	if len(c.MinScores) > 0 {
+		record.MinScores = make(map[string]float64, len(c.MinScores))
+		for mt, score := range c.MinScores {
+			record.MinScores[FactorName(mt)] = score.ToFloat()
+		}
+	}
+	for _, mt := range c.RequiredFeatures {
+		record.RequiredFeatures = append(record.RequiredFeatures, FactorName(mt))
+	}
+	for _, rule := range c.Rules {
+		record.Rules = append(record.Rules, rule.String())
+	}
+	return record
+}
+
+// IsEmpty reports whether the record declares no constraint.
+func (r ConstraintsRecord) IsEmpty() bool {
+	return len(r.MinScores) == 0 && len(r.RequiredFeatures) == 0 && len(r.ExcludedLocations) == 0 && len(r.Rules) == 0
+}
+
+// Constraints resolves factor names and validates the result.
+func (r ConstraintsRecord) Constraints() (scoring.Constraints, error) {
+	c := scoring.Constraints{ExcludedLocations: r.ExcludedLocations}
+	if len(r.MinScores) > 0 {
+		c.MinScores = make(map[metadata.MetadataType]shared.ScoreValue, len(r.MinScores))
+		for name, points := range r.MinScores {
+			mt, err := ParseFactor(name)
+			if err != nil {
+				return scoring.Constraints{}, err
+			}
+			score, err := scoreFromFloat(points)
+			if err != nil {
+				return scoring.Constraints{}, &scoring.ValidationError{Field: FactorName(mt), Message: err.Error()}
+			}
+			c.MinScores[mt] = score
+		}
+	}
+	for _, name := range r.RequiredFeatures {
+		mt, err := ParseFactor(name)
+		if err != nil {
+			return scoring.Constraints{}, err
+		}
+		c.RequiredFeatures = append(c.RequiredFeatures, mt)
+	}
+	for _, source := range r.Rules {
+		rule, err := expr.Parse(source)
+		if err != nil {
+			return scoring.Constraints{}, &scoring.ValidationError{Field: "rules", Message: err.Error()}
+		}
+		c.Rules = append(c.Rules, rule)
+	}
+	return c, c.Validate()
+}
@@ -0,0 +1,374 @@
+package codec
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/scoring"
+	"apart_score/pkg/shared"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	csvID         = "id"
+	csvName       = "name"
+	csvLocation   = "location"
+	csvFactor     = "factor"
+	csvWeight     = "weight"
+	csvScore      = "score"
+	csvWeighted   = "weighted_score"
+	csvMethod     = "method"
+	csvTotal      = "total_score"
+	csvRank       = "rank"
+	csvPercentile = "percentile"
+	csvCloseness  = "closeness"
+)
+
+func formatNumber(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// parseNumber parses a CSV cell; an empty cell returns nil.
+func parseNumber(cell string, line int, column string) (*float64, error) {
+	cell = strings.TrimSpace(cell)
+	if cell == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(cell, 64)
+	if err != nil {
+		return nil, fmt.Errorf("CSV %d행 %s: 숫자가 아닙니다: %q", line, column, cell)
+	}
+	return &v, nil
+}
+
+func writeCSV(w io.Writer, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
+
+// readCSV reads a header and rows, returning the column index of each header name.
+// Factor columns are resolved by name and returned separately; other unknown columns are rejected.
+func readCSV(r io.Reader, fixed []string) (columns map[string]int, factors map[int]metadata.MetadataType, rows [][]string, err error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("CSV 파싱 실패: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, nil, errors.New("CSV 헤더가 없습니다")
+	}
+	columns = make(map[string]int)
+	factors = make(map[int]metadata.MetadataType)
+	seen := make(map[metadata.MetadataType]bool)
+	for i, name := range records[0] {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if isFixedColumn(name, fixed) {
+			if _, duplicate := columns[strings.ToLower(name)]; duplicate {
+				return nil, nil, nil, fmt.Errorf("CSV 헤더에 중복된 열: %s", name)
+			}
+			columns[strings.ToLower(name)] = i
+			continue
+		}
+		mt, err := ParseFactor(name)
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("CSV 헤더: %w", err)
+		}
+		if seen[mt] {
+			return nil, nil, nil, fmt.Errorf("CSV 헤더에 중복된 요소: %s", FactorName(mt))
+		}
+		seen[mt] = true
+		factors[i] = mt
+	}
+	return columns, factors, records[1:], nil
+}
+
+func isFixedColumn(name string, fixed []string) bool {
+	for _, f := range fixed {
+		if strings.EqualFold(name, f) {
+			return true
+		}
+	}
+	return false
+}
+
+func cell(row []string, columns map[string]int, name string) string {
+	if i, exists := columns[name]; exists && i < len(row) {
+		return strings.TrimSpace(row[i])
+	}
+	return ""
+}
+
+// factorColumns returns the factors used by any apartment, in registry order.
+func factorColumns(apartments []ApartmentRecord) []metadata.MetadataType {
+	var result []metadata.MetadataType
+	for _, mt := range metadata.All() {
+		for _, apt := range apartments {
+			if _, exists := apt.Scores[FactorName(mt)]; exists {
+				result = append(result, mt)
+				break
+			}
+		}
+	}
+	return result
+}
+
+func scoreCells(scores FactorScores, factors []metadata.MetadataType) []string {
+	cells := make([]string, len(factors))
+	for i, mt := range factors {
+		if v := scores[FactorName(mt)]; v != nil {
+			cells[i] = formatNumber(*v)
+		}
+	}
+	return cells
+}
+
+// readScoreCells reads the factor columns of a row; empty cells become missing data.
+func readScoreCells(row []string, factors map[int]metadata.MetadataType, line int) (FactorScores, error) {
+	scores := make(FactorScores, len(factors))
+	for i, mt := range factors {
+		var value string
+		if i < len(row) {
+			value = row[i]
+		}
+		v, err := parseNumber(value, line, FactorName(mt))
+		if err != nil {
+			return nil, err
+		}
+		scores[FactorName(mt)] = v
+	}
+	return scores, nil
+}
+
+func writeApartmentsCSV(w io.Writer, apartments []scoring.ApartmentData) error {
+	records := make([]ApartmentRecord, len(apartments))
+	for i, apt := range apartments {
+		records[i] = NewApartmentRecord(apt)
+	}
+	factors := factorColumns(records)
+	header := []string{csvID, csvName, csvLocation}
+	for _, mt := range factors {
+		header = append(header, FactorName(mt))
+	}
+	rows := [][]string{header}
+	for _, record := range records {
+		rows = append(rows, append([]string{record.ID, record.Name, record.Location}, scoreCells(record.Scores, factors)...))
+	}
+	return writeCSV(w, rows)
+}
+
+func readApartmentsCSV(r io.Reader) ([]ApartmentRecord, error) {
+	columns, factors, rows, err := readCSV(r, []string{csvID, csvName, csvLocation})
+	if err != nil {
+		return nil, err
+	}
+	records := make([]ApartmentRecord, 0, len(rows))
+	for i, row := range rows {
+		scores, err := readScoreCells(row, factors, i+2)
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, ApartmentRecord{
+			ID:       cell(row, columns, csvID),
+			Name:     cell(row, columns, csvName),
+			Location: cell(row, columns, csvLocation),
+			Scores:   scores,
+		})
+	}
+	return records, nil
+}
+
+func writeWeightsCSV(w io.Writer, weights map[metadata.MetadataType]shared.Weight) error {
+	rows := [][]string{{csvFactor, csvWeight}}
+	for _, mt := range metadata.All() {
+		if weight := weights[mt]; weight != 0 {
+			rows = append(rows, []string{FactorName(mt), formatNumber(weight.ToFloat())})
+		}
+	}
+	return writeCSV(w, rows)
+}
+
+func readWeightsCSV(r io.Reader) (FactorWeights, error) {
+	columns, _, rows, err := readLongCSV(r, []string{csvFactor, csvWeight})
+	if err != nil {
+		return nil, err
+	}
+	weights := make(FactorWeights, len(rows))
+	for i, row := range rows {
+		mt, err := ParseFactor(cell(row, columns, csvFactor))
+		if err != nil {
+			return nil, fmt.Errorf("CSV %d행: %w", i+2, err)
+		}
+		if _, duplicate := weights[FactorName(mt)]; duplicate {
+			return nil, fmt.Errorf("CSV %d행: 요소가 중복되었습니다: %s", i+2, FactorName(mt))
+		}
+		w, err := parseNumber(cell(row, columns, csvWeight), i+2, csvWeight)
+		if err != nil {
+			return nil, err
+		}
+		if w == nil {
+			return nil, fmt.Errorf("CSV %d행: %s 가중치가 비어 있습니다", i+2, FactorName(mt))
+		}
+		weights[FactorName(mt)] = *w
+	}
+	return weights, nil
+}
+
+// readLongCSV reads a CSV whose header must consist of exactly the given columns (in any order).
+func readLongCSV(r io.Reader, required []string) (map[string]int, map[int]metadata.MetadataType, [][]string, error) {
+	columns, factors, rows, err := readCSV(r, required)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	if len(factors) > 0 {
+		return nil, nil, nil, fmt.Errorf("CSV 헤더에 예상하지 못한 열이 있습니다 (필요한 열: %s)", strings.Join(required, ", "))
+	}
+	for _, name := range required {
+		if _, exists := columns[name]; !exists {
+			return nil, nil, nil, fmt.Errorf("CSV 헤더에 %s 열이 없습니다", name)
+		}
+	}
+	return columns, factors, rows, nil
+}
+
+func writeScoreResultCSV(w io.Writer, record ScoreResultRecord) error {
+	rows := [][]string{{csvMethod, csvTotal, csvFactor, csvScore, csvWeight, csvWeighted}}
+	for _, factor := range record.Factors {
+		score := ""
+		if factor.Score != nil {
+			score = formatNumber(*factor.Score)
+		}
+		rows = append(rows, []string{string(record.Method), formatNumber(record.TotalScore), factor.Factor, score,
+			formatNumber(factor.Weight), formatNumber(factor.WeightedScore)})
+	}
+	return writeCSV(w, rows)
+}
+
+func readScoreResultCSV(r io.Reader) (ScoreResultRecord, error) {
+	columns, _, rows, err := readLongCSV(r, []string{csvMethod, csvTotal, csvFactor, csvScore, csvWeight, csvWeighted})
+	if err != nil {
+		return ScoreResultRecord{}, err
+	}
+	record := ScoreResultRecord{Factors: []FactorResult{}}
+	for i, row := range rows {
+		line := i + 2
+		method, total := cell(row, columns, csvMethod), cell(row, columns, csvTotal)
+		if i == 0 {
+			record.Method = scoring.StrategyType(method)
+			v, err := parseNumber(total, line, csvTotal)
+			if err != nil {
+				return ScoreResultRecord{}, err
+			}
+			if v != nil {
+				record.TotalScore = *v
+			}
+		} else if method != string(record.Method) || total != cell(rows[0], columns, csvTotal) {
+			return ScoreResultRecord{}, fmt.Errorf("CSV %d행: 모든 행의 %s, %s 값이 같아야 합니다", line, csvMethod, csvTotal)
+		}
+		factor := FactorResult{Factor: cell(row, columns, csvFactor)}
+		if factor.Score, err = parseNumber(cell(row, columns, csvScore), line, csvScore); err != nil {
+			return ScoreResultRecord{}, err
+		}
+		for name, target := range map[string]*float64{csvWeight: &factor.Weight, csvWeighted: &factor.WeightedScore} {
+			v, err := parseNumber(cell(row, columns, name), line, name)
+			if err != nil {
+				return ScoreResultRecord{}, err
+			}
+			if v != nil {
+				*target = *v
+			}
+		}
+		record.Factors = append(record.Factors, factor)
+	}
+	return record, nil
+}
+
+var rankingColumns = []string{csvRank, csvID, csvName, csvLocation, csvScore, csvPercentile, csvCloseness, csvMethod}
+
+func writeRankingsCSV(w io.Writer, record RankingsRecord) error {
+	apartments := make([]ApartmentRecord, len(record.Rankings))
+	for i, ranking := range record.Rankings {
+		apartments[i] = ranking.Apartment
+	}
+	factors := factorColumns(apartments)
+	header := append([]string{}, rankingColumns...)
+	for _, mt := range factors {
+		header = append(header, FactorName(mt))
+	}
+	rows := [][]string{header}
+	for _, ranking := range record.Rankings {
+		closeness := ""
+		if ranking.Closeness != nil {
+			closeness = formatNumber(*ranking.Closeness)
+		}
+		row := []string{strconv.Itoa(ranking.Rank), ranking.Apartment.ID, ranking.Apartment.Name, ranking.Apartment.Location,
+			formatNumber(ranking.Score), formatNumber(ranking.Percentile), closeness, string(record.Strategy)}
+		rows = append(rows, append(row, scoreCells(ranking.Apartment.Scores, factors)...))
+	}
+	return writeCSV(w, rows)
+}
+
+// readRankingsCSV reads ranking rows; the total count and score range are derived from the rows.
+func readRankingsCSV(r io.Reader) (RankingsRecord, error) {
+	columns, factors, rows, err := readCSV(r, rankingColumns)
+	if err != nil {
+		return RankingsRecord{}, err
+	}
+	for _, name := range []string{csvRank, csvID, csvScore} {
+		if _, exists := columns[name]; !exists {
+			return RankingsRecord{}, fmt.Errorf("CSV 헤더에 %s 열이 없습니다", name)
+		}
+	}
+	record := RankingsRecord{TotalApartments: len(rows), Rankings: make([]RankingRecord, 0, len(rows))}
+	for i, row := range rows {
+		line := i + 2
+		rank, err := strconv.Atoi(cell(row, columns, csvRank))
+		if err != nil {
+			return RankingsRecord{}, fmt.Errorf("CSV %d행 %s: 정수가 아닙니다: %q", line, csvRank, cell(row, columns, csvRank))
+		}
+		method := scoring.StrategyType(cell(row, columns, csvMethod))
+		if i == 0 {
+			record.Strategy = method
+		} else if method != record.Strategy {
+			return RankingsRecord{}, fmt.Errorf("CSV %d행: 모든 행의 %s 값이 같아야 합니다", line, csvMethod)
+		}
+		ranking := RankingRecord{Rank: rank}
+		for name, target := range map[string]*float64{csvScore: &ranking.Score, csvPercentile: &ranking.Percentile} {
+			v, err := parseNumber(cell(row, columns, name), line, name)
+			if err != nil {
+				return RankingsRecord{}, err
+			}
+			if v != nil {
+				*target = *v
+			}
+		}
+		if ranking.Closeness, err = parseNumber(cell(row, columns, csvCloseness), line, csvCloseness); err != nil {
+			return RankingsRecord{}, err
+		}
+		scores, err := readScoreCells(row, factors, line)
+		if err != nil {
+			return RankingsRecord{}, err
+		}
+		ranking.Apartment = ApartmentRecord{
+			ID:       cell(row, columns, csvID),
+			Name:     cell(row, columns, csvName),
+			Location: cell(row, columns, csvLocation),
+			Scores:   scores,
+		}
+		if i == 0 || ranking.Score < record.ScoreRange.Min {
+			record.ScoreRange.Min = ranking.Score
+		}
+		if i == 0 || ranking.Score > record.ScoreRange.Max {
+			record.ScoreRange.Max = ranking.Score
+		}
+		record.ScoreRange.Avg += ranking.Score / float64(len(rows))
+		record.Rankings = append(record.Rankings, ranking)
+	}
+	return record, nil
+}
@@ -0,0 +1,43 @@
+package codec
+
+import (
+	"apart_score/pkg/expr"
+	"apart_score/pkg/scoring"
+	"fmt"
+	"strings"
+)
+
+// ParseDerivedFactor parses a "factor=expression" definition, such as
+// "Transportation Access = max(DistanceToStation, NearbyAmenities)". The factor is an English
+// or Korean factor name and the expression is split off at the first '='.
+func ParseDerivedFactor(definition string) (scoring.DerivedFactor, error) {
+	name, source, ok := strings.Cut(definition, "=")
+	if !ok {
+		return scoring.DerivedFactor{}, fmt.Errorf("파생 요소는 '요소=식' 형식이어야 합니다: %q", definition)
+	}
+	mt, err := ParseFactor(name)
+	if err != nil {
+		return scoring.DerivedFactor{}, err
+	}
+	e, err := expr.Parse(source)
+	if err != nil {
+		return scoring.DerivedFactor{}, fmt.Errorf("파생 요소 %s: %w", FactorName(mt), err)
+	}
+	return scoring.DerivedFactor{Factor: mt, Expr: e}, nil
+}
+
+// ParseDerivedFactors parses definitions in order and validates them together.
+func ParseDerivedFactors(definitions []string) (scoring.DerivedFactors, error) {
+	var derived scoring.DerivedFactors
+	for _, definition := range definitions {
+		d, err := ParseDerivedFactor(definition)
+		if err != nil {
+			return nil, err
+		}
+		derived = append(derived, d)
+	}
+	if err := derived.Validate(); err != nil {
+		return nil, err
+	}
+	return derived, nil
+}
@@ -0,0 +1,193 @@
+package codec
+
+import (
+	"apart_score/pkg/expr"
+	"apart_score/pkg/scoring"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// PipelineConditionRecord is the file representation of scoring.PipelineCondition. An empty
+// factor compares the running total; Expr holds the source of a condition expression.
+type PipelineConditionRecord struct {
+	Factor string                    `json:"factor,omitempty"`
+	Op     scoring.ConditionOperator `json:"op,omitempty"`
+	Value  float64                   `json:"value,omitempty"`
+	All    []PipelineConditionRecord `json:"all,omitempty"`
+	Any    []PipelineConditionRecord `json:"any,omitempty"`
+	Expr   string                    `json:"expr,omitempty"`
+}
+
+// PipelineStepRecord is the file representation of scoring.PipelineStepSpec. Factors map
+// factor names to weights relative to the block's other factors.
+type PipelineStepRecord struct {
+	Name        string                   `json:"name"`
+	Description string                   `json:"description,omitempty"`
+	Kind        scoring.PipelineStepKind `json:"kind"`
+	When        *PipelineConditionRecord `json:"when,omitempty"`
+	Factors     map[string]float64       `json:"factors,omitempty"`
+	Weight      float64                  `json:"weight,omitempty"`
+	Points      float64                  `json:"points,omitempty"`
+	Min         float64                  `json:"min,omitempty"`
+	Max         float64                  `json:"max,omitempty"`
+	Strategy    scoring.StrategyType     `json:"strategy,omitempty"`
+	Formula     string                   `json:"formula,omitempty"`
+}
+
+// PipelineRecord is the file representation of scoring.PipelineSpec.
+type PipelineRecord struct {
+	Name        string               `json:"name"`
+	Description string               `json:"description,omitempty"`
+	Steps       []PipelineStepRecord `json:"steps"`
+}
+
+// NewPipelineRecord converts a pipeline spec to its file representation.
+func NewPipelineRecord(spec scoring.PipelineSpec) PipelineRecord {
+	record := PipelineRecord{Name: spec.Name, Description: spec.Description, Steps: make([]PipelineStepRecord, len(spec.Steps))}
+	for i, step := range spec.Steps {
+		stepRecord := PipelineStepRecord{
+			Name:        step.Name,
+			Description: step.Description,
+			Kind:        step.Kind,
+			Weight:      step.Weight,
+			Points:      step.Points,
+			Min:         step.Min,
+			Max:         step.Max,
+			Strategy:    step.Strategy,
+		}
+		if step.Formula != nil {
+			stepRecord.Formula = step.Formula.String()
+		}
+		if step.When != nil {
+			when := newPipelineConditionRecord(*step.When)
+			stepRecord.When = &when
+		}
+		if len(step.Factors) > 0 {
+			stepRecord.Factors = make(map[string]float64, len(step.Factors))
+			for _, f := range step.Factors {
+				stepRecord.Factors[FactorName(f.Factor)] = f.Weight
+			}
+		}
+		record.Steps[i] = stepRecord
+	}
+	return record
+}
+
+func newPipelineConditionRecord(c scoring.PipelineCondition) PipelineConditionRecord {
+	record := PipelineConditionRecord{Op: c.Op, Value: c.Value}
+	if c.Factor != nil {
+		record.Factor = FactorName(*c.Factor)
+	}
+	if c.Expr != nil {
+		record.Expr = c.Expr.String()
+	}
+	for _, nested := range c.All {
+		record.All = append(record.All, newPipelineConditionRecord(nested))
+	}
+	for _, nested := range c.Any {
+		record.Any = append(record.Any, newPipelineConditionRecord(nested))
+	}
+	return record
+}
+
+// Spec resolves factor names and validates the pipeline. Factors within a block are ordered by
+// metadata type.
+func (r PipelineRecord) Spec() (scoring.PipelineSpec, error) {
+	spec := scoring.PipelineSpec{Name: r.Name, Description: r.Description, Steps: make([]scoring.PipelineStepSpec, len(r.Steps))}
+	for i, record := range r.Steps {
+		step := scoring.PipelineStepSpec{
+			Name:        record.Name,
+			Description: record.Description,
+			Kind:        record.Kind,
+			Weight:      record.Weight,
+			Points:      record.Points,
+			Min:         record.Min,
+			Max:         record.Max,
+			Strategy:    record.Strategy,
+		}
+		if record.Formula != "" {
+			formula, err := expr.Parse(record.Formula)
+			if err != nil {
+				return scoring.PipelineSpec{}, fmt.Errorf("단계 %s formula: %w", record.Name, err)
+			}
+			step.Formula = formula
+		}
+		if record.When != nil {
+			when, err := record.When.condition()
+			if err != nil {
+				return scoring.PipelineSpec{}, fmt.Errorf("단계 %s when: %w", record.Name, err)
+			}
+			step.When = &when
+		}
+		for name, w := range record.Factors {
+			mt, err := ParseFactor(name)
+			if err != nil {
+				return scoring.PipelineSpec{}, err
+			}
+			step.Factors = append(step.Factors, scoring.FactorWeight{Factor: mt, Weight: w})
+		}
+		sort.Slice(step.Factors, func(i, j int) bool { return step.Factors[i].Factor < step.Factors[j].Factor })
+		spec.Steps[i] = step
+	}
+	return spec, spec.Validate()
+}
+
+func (r PipelineConditionRecord) condition() (scoring.PipelineCondition, error) {
+	c := scoring.PipelineCondition{Op: r.Op, Value: r.Value}
+	if r.Factor != "" {
+		mt, err := ParseFactor(r.Factor)
+		if err != nil {
+			return c, err
+		}
+		c.Factor = &mt
+	}
+	if r.Expr != "" {
+		parsed, err := expr.Parse(r.Expr)
+		if err != nil {
+			return c, err
+		}
+		c.Expr = parsed
+	}
+	var err error
+	if c.All, err = pipelineConditions(r.All); err != nil {
+		return c, err
+	}
+	c.Any, err = pipelineConditions(r.Any)
+	return c, err
+}
+
+func pipelineConditions(records []PipelineConditionRecord) ([]scoring.PipelineCondition, error) {
+	var conditions []scoring.PipelineCondition
+	for _, record := range records {
+		c, err := record.condition()
+		if err != nil {
+			return nil, err
+		}
+		conditions = append(conditions, c)
+	}
+	return conditions, nil
+}
+
+// EncodePipeline writes a pipeline spec as JSON or YAML.
+func EncodePipeline(w io.Writer, format Format, spec scoring.PipelineSpec) error {
+	return Encode(w, format, NewPipelineRecord(spec))
+}
+
+// DecodePipeline reads a pipeline spec from JSON or YAML.
+func DecodePipeline(r io.Reader, format Format) (scoring.PipelineSpec, error) {
+	var record PipelineRecord
+	if err := unmarshal(r, format, &record); err != nil {
+		return scoring.PipelineSpec{}, err
+	}
+	return record.Spec()
+}
+
+// LoadPipeline reads a pipeline spec and compiles it for scoring.CalculateWithPipeline.
+func LoadPipeline(r io.Reader, format Format) (scoring.CalculationPipeline, error) {
+	spec, err := DecodePipeline(r, format)
+	if err != nil {
+		return scoring.CalculationPipeline{}, err
+	}
+	return spec.Compile()
+}
@@ -0,0 +1,436 @@
+package codec
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/scoring"
+	"apart_score/pkg/shared"
+	"fmt"
+	"io"
+)
+
+// FactorResult is one factor's line in a score result.
+type FactorResult struct {
+	Factor        string   `json:"factor"`
+	Score         *float64 `json:"score"` // null이면 데이터 없음
+	Weight        float64  `json:"weight"`
+	WeightedScore float64  `json:"weighted_score"`
+}
+
+// ScoreResultRecord is the file representation of scoring.ScoreResult.
+type ScoreResultRecord struct {
+	TotalScore float64                 `json:"total_score"`
+	Method     scoring.StrategyType    `json:"method"`
+	Scenario   scoring.ScoringScenario `json:"scenario,omitempty"`
+	Closeness  *float64                `json:"closeness,omitempty"` // 코호트 전략에서만 기록 (0도 유효한 값)
+	Factors    []FactorResult          `json:"factors"`
+	Imputed    []string                `json:"imputed,omitempty"`
+}
+
+// NewScoreResultRecord converts a score result to its file representation.
+// Factors are listed in registry order, skipping those with neither a score nor a weight.
+func NewScoreResultRecord(result scoring.ScoreResult) ScoreResultRecord {
+	record := ScoreResultRecord{
+		TotalScore: result.TotalScore,
+		Method:     result.Method,
+		Scenario:   result.Scenario,
+		Factors:    []FactorResult{},
+	}
+	if scoring.IsCohortStrategy(result.Method) {
+		closeness := result.Closeness
+		record.Closeness = &closeness
+	}
+	for _, mt := range metadata.All() {
+		score, weight := result.RawScores[mt], result.Weights[mt]
+		if score == 0 && weight == 0 {
+			continue
+		}
+		factor := FactorResult{Factor: FactorName(mt), Weight: weight.ToFloat(), WeightedScore: result.WeightedScores[mt]}
+		if !score.IsMissing() {
+			value := score.ToFloat()
+			factor.Score = &value
+		}
+		record.Factors = append(record.Factors, factor)
+	}
+	for _, mt := range result.Imputed {
+		record.Imputed = append(record.Imputed, FactorName(mt))
+	}
+	return record
+}
+
+// ScoreResult converts the record back to scoring.ScoreResult.
+func (r ScoreResultRecord) ScoreResult() (scoring.ScoreResult, error) {
+	result := scoring.ScoreResult{
+		TotalScore: r.TotalScore,
+		Method:     r.Method,
+		Scenario:   r.Scenario,
+	}
+	if r.Closeness != nil {
+		result.Closeness = *r.Closeness
+	}
+	seen := make(map[metadata.MetadataType]bool, len(r.Factors))
+	for _, factor := range r.Factors {
+		mt, err := ParseFactor(factor.Factor)
+		if err != nil {
+			return scoring.ScoreResult{}, err
+		}
+		if seen[mt] {
+			return scoring.ScoreResult{}, fmt.Errorf("요소가 중복되었습니다: %s", FactorName(mt))
+		}
+		seen[mt] = true
+		result.RawScores[mt] = shared.MissingScore
+		if factor.Score != nil {
+			if result.RawScores[mt], err = scoreFromFloat(*factor.Score); err != nil {
+				return scoring.ScoreResult{}, fmt.Errorf("%s: %w", FactorName(mt), err)
+			}
+		}
+		result.Weights[mt] = weightFromFloat(factor.Weight)
+		result.WeightedScores[mt] = factor.WeightedScore
+	}
+	for _, name := range r.Imputed {
+		mt, err := ParseFactor(name)
+		if err != nil {
+			return scoring.ScoreResult{}, err
+		}
+		result.Imputed = append(result.Imputed, mt)
+	}
+	return result, nil
+}
+
+// EncodeScoreResult writes a score result in the given format.
+// CSV has one row per factor with the method and total repeated on every row.
+func EncodeScoreResult(w io.Writer, format Format, result scoring.ScoreResult) error {
+	record := NewScoreResultRecord(result)
+	if format == FormatCSV {
+		return writeScoreResultCSV(w, record)
+	}
+	return Encode(w, format, record)
+}
+
+// DecodeScoreResult reads a score result in the given format.
+func DecodeScoreResult(r io.Reader, format Format) (scoring.ScoreResult, error) {
+	var record ScoreResultRecord
+	var err error
+	if format == FormatCSV {
+		record, err = readScoreResultCSV(r)
+	} else {
+		err = unmarshal(r, format, &record)
+	}
+	if err != nil {
+		return scoring.ScoreResult{}, err
+	}
+	return record.ScoreResult()
+}
+
+// RankingRecord is the file representation of scoring.RankingResult.
+type RankingRecord struct {
+	Rank       int             `json:"rank"`
+	Score      float64         `json:"score"`
+	Percentile float64         `json:"percentile"`
+	Closeness  *float64        `json:"closeness,omitempty"` // 코호트 전략에서만 기록 (0도 유효한 값)
+	Apartment  ApartmentRecord `json:"apartment"`
+	Weights    FactorWeights   `json:"weights,omitempty"`
+}
+
+// ViolationRecord is the file representation of scoring.ConstraintViolation.
+type ViolationRecord struct {
+	Kind     scoring.ConstraintKind `json:"kind"`
+	Factor   string                 `json:"factor,omitempty"`
+	Location string                 `json:"location,omitempty"`
+	Required float64                `json:"required,omitempty"`
+	Actual   float64                `json:"actual,omitempty"`
+	// DominatedBy lists the dominating apartments of a dominated violation.
+	DominatedBy []string `json:"dominated_by,omitempty"`
+	Rule        string   `json:"rule,omitempty"` // 불충족된 조건 식 (rule)
+	Message     string   `json:"message"`
+}
+
+// EliminationRecord is the file representation of scoring.Elimination.
+type EliminationRecord struct {
+	Apartment  ApartmentRecord   `json:"apartment"`
+	Violations []ViolationRecord `json:"violations"`
+}
+
+// ScoreRangeRecord summarizes the scores of the ranked apartments.
+type ScoreRangeRecord struct {
+	Min float64 `json:"min"`
+	Max float64 `json:"max"`
+	Avg float64 `json:"avg"`
+}
+
+// RankingsRecord is the file representation of scoring.RankingsSummary.
+type RankingsRecord struct {
+	TotalApartments int                  `json:"total_apartments"`
+	Strategy        scoring.StrategyType `json:"strategy"`
+	Rankings        []RankingRecord      `json:"rankings"`
+	Eliminated      []EliminationRecord  `json:"eliminated,omitempty"`
+	ScoreRange      ScoreRangeRecord     `json:"score_range"`
+}
+
+// NewRankingsRecord converts a rankings summary to its file representation.
+func NewRankingsRecord(summary *scoring.RankingsSummary) RankingsRecord {
+	record := RankingsRecord{
+		TotalApartments: summary.TotalApartments,
+		Strategy:        summary.Strategy,
+		Rankings:        make([]RankingRecord, len(summary.TopRanked)),
+		ScoreRange:      ScoreRangeRecord{summary.ScoreRange.Min, summary.ScoreRange.Max, summary.ScoreRange.Avg},
+	}
+	for i, ranking := range summary.TopRanked {
+		weights := FactorWeights{}
+		for _, mt := range metadata.All() {
+			if w := ranking.Weights[mt]; w != 0 {
+				weights[FactorName(mt)] = w.ToFloat()
+			}
+		}
+		record.Rankings[i] = RankingRecord{
+			Rank:       ranking.Rank,
+			Score:      ranking.Score,
+			Percentile: ranking.Percentile,
+			Closeness:  ranking.Closeness,
+			Apartment:  NewApartmentRecord(ranking.Apartment),
+			Weights:    weights,
+		}
+	}
+	for _, elimination := range summary.Eliminated {
+		eliminated := EliminationRecord{Apartment: NewApartmentRecord(elimination.Apartment)}
+		for _, v := range elimination.Violations {
+			violation := ViolationRecord{Kind: v.Kind, Location: v.Location, Required: v.Required, Actual: v.Actual,
+				DominatedBy: v.DominatedBy, Rule: v.Rule, Message: v.Message}
+			if v.Kind == scoring.ConstraintMinScore || v.Kind == scoring.ConstraintRequiredFeature {
+				violation.Factor = FactorName(v.Factor)
+			}
+			eliminated.Violations = append(eliminated.Violations, violation)
+		}
+		record.Eliminated = append(record.Eliminated, eliminated)
+	}
+	return record
+}
+
+// Summary converts the record back to scoring.RankingsSummary. Ranking weights are taken
+// as written (fractions of 1) rather than renormalized.
+func (r RankingsRecord) Summary() (*scoring.RankingsSummary, error) {
+	summary := &scoring.RankingsSummary{
+		TotalApartments: r.TotalApartments,
+		Strategy:        r.Strategy,
+		TopRanked:       make([]scoring.RankingResult, len(r.Rankings)),
+	}
+	summary.ScoreRange.Min, summary.ScoreRange.Max, summary.ScoreRange.Avg = r.ScoreRange.Min, r.ScoreRange.Max, r.ScoreRange.Avg
+	for i, ranking := range r.Rankings {
+		apt, err := ranking.Apartment.Apartment()
+		if err != nil {
+			return nil, err
+		}
+		result := scoring.RankingResult{
+			Apartment:  apt,
+			Score:      ranking.Score,
+			Rank:       ranking.Rank,
+			Percentile: ranking.Percentile,
+			Method:     r.Strategy,
+			Closeness:  ranking.Closeness,
+		}
+		for name, w := range ranking.Weights {
+			mt, err := ParseFactor(name)
+			if err != nil {
+				return nil, fmt.Errorf("아파트 %s: %w", apt.ID, err)
+			}
+			result.Weights[mt] = weightFromFloat(w)
+		}
+		summary.TopRanked[i] = result
+	}
+	for _, eliminated := range r.Eliminated {
+		apt, err := eliminated.Apartment.Apartment()
+		if err != nil {
+			return nil, err
+		}
+		elimination := scoring.Elimination{Apartment: apt}
+		for _, v := range eliminated.Violations {
+			violation := scoring.ConstraintViolation{Kind: v.Kind, Location: v.Location, Required: v.Required, Actual: v.Actual,
+				DominatedBy: v.DominatedBy, Rule: v.Rule, Message: v.Message}
+			if v.Factor != "" {
+				if violation.Factor, err = ParseFactor(v.Factor); err != nil {
+					return nil, fmt.Errorf("아파트 %s: %w", apt.ID, err)
+				}
+			}
+			elimination.Violations = append(elimination.Violations, violation)
+		}
+		summary.Eliminated = append(summary.Eliminated, elimination)
+	}
+	return summary, nil
+}
+
+// EncodeRankings writes a rankings summary in the given format.
+// CSV has one row per ranked apartment with its factor scores; eliminations and weights are omitted.
+func EncodeRankings(w io.Writer, format Format, summary *scoring.RankingsSummary) error {
+	if summary == nil {
+		return fmt.Errorf("순위 데이터가 없습니다")
+	}
+	record := NewRankingsRecord(summary)
+	if format == FormatCSV {
+		return writeRankingsCSV(w, record)
+	}
+	return Encode(w, format, record)
+}
+
+// DecodeRankings reads a rankings summary in the given format.
+func DecodeRankings(r io.Reader, format Format) (*scoring.RankingsSummary, error) {
+	var record RankingsRecord
+	var err error
+	if format == FormatCSV {
+		record, err = readRankingsCSV(r)
+	} else {
+		err = unmarshal(r, format, &record)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return record.Summary()
+}
+
+// FactorChangeRecord is the file representation of scoring.FactorChange.
+type FactorChangeRecord struct {
+	Factor string  `json:"factor"`
+	From   float64 `json:"from"`
+	To     float64 `json:"to"`
+	Delta  float64 `json:"delta"`
+}
+
+// CounterfactualRecord is the file representation of scoring.Counterfactual.
+type CounterfactualRecord struct {
+	ApartmentID  string               `json:"apartment_id"`
+	RivalID      string               `json:"rival_id,omitempty"`
+	CurrentScore float64              `json:"current_score"`
+	TargetScore  float64              `json:"target_score"`
+	Achievable   bool                 `json:"achievable"`
+	NewScore     float64              `json:"new_score"`
+	Changes      []FactorChangeRecord `json:"changes"`
+	TotalChange  float64              `json:"total_change"`
+}
+
+// NewCounterfactualRecord converts a counterfactual to its file representation.
+func NewCounterfactualRecord(c *scoring.Counterfactual) CounterfactualRecord {
+	record := CounterfactualRecord{
+		ApartmentID:  c.ApartmentID,
+		RivalID:      c.RivalID,
+		CurrentScore: c.CurrentScore,
+		TargetScore:  c.TargetScore,
+		Achievable:   c.Achievable,
+		NewScore:     c.NewScore,
+		Changes:      make([]FactorChangeRecord, len(c.Changes)),
+		TotalChange:  c.TotalChange,
+	}
+	for i, change := range c.Changes {
+		record.Changes[i] = FactorChangeRecord{Factor: FactorName(change.Factor), From: change.From, To: change.To, Delta: change.Delta}
+	}
+	return record
+}
+
+// FactorComparisonRecord is the file representation of scoring.FactorComparison.
+type FactorComparisonRecord struct {
+	Factor string   `json:"factor"`
+	ScoreA *float64 `json:"score_a"` // null이면 데이터 없음
+	ScoreB *float64 `json:"score_b"`
+	Delta  float64  `json:"delta"`
+	Favors string   `json:"favors,omitempty"`
+}
+
+// ComparisonRecord is the file representation of scoring.ComparisonResult.
+type ComparisonRecord struct {
+	A            string                      `json:"a"`
+	B            string                      `json:"b"`
+	Strategy     scoring.StrategyType        `json:"strategy"`
+	ScoreA       float64                     `json:"score_a"`
+	ScoreB       float64                     `json:"score_b"`
+	Difference   float64                     `json:"difference"`
+	Winner       string                      `json:"winner,omitempty"`
+	Factors      []FactorComparisonRecord    `json:"factors"`
+	Decisive     []string                    `json:"decisive"`
+	Interaction  float64                     `json:"interaction"`
+	Alternatives []scoring.ComparisonOutcome `json:"alternatives"`
+	Robust       bool                        `json:"robust"`
+}
+
+// NewComparisonRecord converts a comparison result to its file representation.
+func NewComparisonRecord(c *scoring.ComparisonResult) ComparisonRecord {
+	record := ComparisonRecord{
+		A: c.A, B: c.B, Strategy: c.Strategy,
+		ScoreA: c.ScoreA, ScoreB: c.ScoreB, Difference: c.Difference, Winner: c.Winner,
+		Factors:      make([]FactorComparisonRecord, len(c.Factors)),
+		Decisive:     factorNames(c.Decisive),
+		Interaction:  c.Interaction,
+		Alternatives: c.Alternatives,
+		Robust:       c.Robust,
+	}
+	for i, f := range c.Factors {
+		record.Factors[i] = FactorComparisonRecord{Factor: FactorName(f.Factor),
+			ScoreA: optionalScore(f.ScoreA), ScoreB: optionalScore(f.ScoreB), Delta: f.Delta, Favors: f.Favors}
+	}
+	return record
+}
+
+// optionalScore returns the score in points, or nil when it is missing.
+func optionalScore(score shared.ScoreValue) *float64 {
+	if score.IsMissing() {
+		return nil
+	}
+	value := score.ToFloat()
+	return &value
+}
+
+// LearnedWeightsRecord is the file representation of scoring.LearnedWeights; its weights can
+// be used as a weights file.
+type LearnedWeightsRecord struct {
+	Weights      FactorWeights             `json:"weights"`
+	Prior        FactorWeights             `json:"prior"`
+	Margin       float64                   `json:"margin"`
+	Preferences  []scoring.PreferenceCheck `json:"preferences"`
+	Violated     []scoring.PreferenceCheck `json:"violated"`
+	WeightChange float64                   `json:"weight_change"`
+}
+
+// NewLearnedWeightsRecord converts learned weights to their file representation.
+func NewLearnedWeightsRecord(l *scoring.LearnedWeights) LearnedWeightsRecord {
+	return LearnedWeightsRecord{
+		Weights:      NewFactorWeights(l.Profile.Weights),
+		Prior:        NewFactorWeights(l.Prior),
+		Margin:       l.Margin,
+		Preferences:  l.Preferences,
+		Violated:     l.Violated,
+		WeightChange: l.WeightChange,
+	}
+}
+
+// DominanceRecord is the file representation of scoring.Dominance.
+type DominanceRecord struct {
+	Dominant  string   `json:"dominant"`
+	Dominated string   `json:"dominated"`
+	Better    []string `json:"better"`
+}
+
+// ParetoRecord is the file representation of scoring.ParetoAnalysis.
+type ParetoRecord struct {
+	Factors    []string          `json:"factors"`
+	Front      []string          `json:"front"`
+	Dominated  []string          `json:"dominated"`
+	Dominances []DominanceRecord `json:"dominances"`
+}
+
+// NewParetoRecord converts a Pareto analysis to its file representation.
+func NewParetoRecord(a *scoring.ParetoAnalysis) ParetoRecord {
+	record := ParetoRecord{
+		Factors:    factorNames(a.Factors),
+		Front:      a.Front,
+		Dominated:  a.Dominated,
+		Dominances: make([]DominanceRecord, len(a.Dominances)),
+	}
+	for i, d := range a.Dominances {
+		record.Dominances[i] = DominanceRecord{Dominant: d.Dominant, Dominated: d.Dominated, Better: factorNames(d.Better)}
+	}
+	return record
+}
+
+func factorNames(factors []metadata.MetadataType) []string {
+	names := make([]string, len(factors))
+	for i, mt := range factors {
+		names[i] = FactorName(mt)
+	}
+	return names
+}
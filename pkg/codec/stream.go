@@ -0,0 +1,175 @@
+package codec
+
+import (
+	"apart_score/pkg/scoring"
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// maxLineSize is the longest NDJSON line accepted.
+const maxLineSize = 1 << 20
+
+// LineError reports a problem with one line of an NDJSON stream.
+type LineError struct {
+	Line int
+	Err  error
+}
+
+func (e *LineError) Error() string {
+	return fmt.Sprintf("%d번째 줄: %v", e.Line, e.Err)
+}
+
+func (e *LineError) Unwrap() error { return e.Err }
+
+// NDJSONReader reads apartments from newline-delimited JSON, one ApartmentRecord per line.
+// Blank lines are skipped.
+type NDJSONReader struct {
+	scanner *bufio.Scanner
+	line    int
+}
+
+// NewNDJSONReader returns a reader over r.
+func NewNDJSONReader(r io.Reader) *NDJSONReader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxLineSize)
+	return &NDJSONReader{scanner: scanner}
+}
+
+// Read returns the next apartment, or io.EOF at the end of the input. A malformed record is
+// returned as a *LineError and reading can continue with the next line; other errors end the
+// input. As in DecodeApartments, an empty ID defaults to the line number and an empty name to
+// the ID.
+func (r *NDJSONReader) Read() (scoring.ApartmentData, error) {
+	for r.scanner.Scan() {
+		r.line++
+		data := bytes.TrimSpace(r.scanner.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+		var record ApartmentRecord
+		decoder := json.NewDecoder(bytes.NewReader(data))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&record); err != nil {
+			return scoring.ApartmentData{}, &LineError{Line: r.line, Err: fmt.Errorf("json 파싱 실패: %w", err)}
+		}
+		if record.ID == "" {
+			record.ID = fmt.Sprintf("%d", r.line)
+		}
+		if record.Name == "" {
+			record.Name = record.ID
+		}
+		apt, err := record.Apartment()
+		if err != nil {
+			return scoring.ApartmentData{}, &LineError{Line: r.line, Err: err}
+		}
+		return apt, nil
+	}
+	if err := r.scanner.Err(); err != nil {
+		return scoring.ApartmentData{}, fmt.Errorf("%d번째 줄 읽기 실패: %w", r.line+1, err)
+	}
+	return scoring.ApartmentData{}, io.EOF
+}
+
+// Line returns the number of the line last read.
+func (r *NDJSONReader) Line() int {
+	return r.line
+}
+
+// NDJSONWriter writes values as newline-delimited JSON. Output is buffered; call Flush when done.
+type NDJSONWriter struct {
+	buffer  *bufio.Writer
+	encoder *json.Encoder
+}
+
+// NewNDJSONWriter returns a writer to w.
+func NewNDJSONWriter(w io.Writer) *NDJSONWriter {
+	buffer := bufio.NewWriter(w)
+	return &NDJSONWriter{buffer: buffer, encoder: json.NewEncoder(buffer)}
+}
+
+// Write writes v as one line of compact JSON.
+func (w *NDJSONWriter) Write(v interface{}) error {
+	return w.encoder.Encode(v)
+}
+
+// Flush writes any buffered lines to the underlying writer.
+func (w *NDJSONWriter) Flush() error {
+	return w.buffer.Flush()
+}
+
+// ScoredApartmentRecord is a score result labelled with the apartment it belongs to. Trace is
+// set when the result comes from a calculation pipeline.
+type ScoredApartmentRecord struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	ScoreResultRecord
+	Trace *scoring.PipelineTrace `json:"trace,omitempty"`
+}
+
+// NewScoredApartmentRecord converts an apartment's score result to its file representation.
+func NewScoredApartmentRecord(apt scoring.ApartmentData, result scoring.ScoreResult) ScoredApartmentRecord {
+	return ScoredApartmentRecord{ID: apt.ID, Name: apt.Name, ScoreResultRecord: NewScoreResultRecord(result)}
+}
+
+// StreamOptions configures ScoreStream.
+type StreamOptions struct {
+	// SkipInvalid skips records that cannot be read or scored, reporting each one to OnSkip,
+	// instead of stopping at the first one.
+	SkipInvalid bool
+	OnSkip      func(*LineError)
+}
+
+// StreamSummary describes a scored stream.
+type StreamSummary struct {
+	Records int                   `json:"records"` // 읽은 레코드 수 (건너뛴 레코드 포함)
+	Skipped int                   `json:"skipped"`
+	Stats   *scoring.RunningStats `json:"stats"`
+}
+
+// ScoreStream reads apartments as NDJSON from r, scores each one with the profile as it
+// arrives and writes a ScoredApartmentRecord line per apartment to w. Only the running
+// statistics are kept, so the input can be arbitrarily large. As with
+// scoring.CalculateWithProfile, the profile's constraints are not applied.
+func ScoreStream(r io.Reader, w io.Writer, p scoring.ScoringProfile, opts StreamOptions) (*StreamSummary, error) {
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf("프로필 %s 검증 실패: %w", p.Name, err)
+	}
+	reader, writer := NewNDJSONReader(r), NewNDJSONWriter(w)
+	summary := &StreamSummary{Stats: scoring.NewRunningStats()}
+	for {
+		apt, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		var lineErr *LineError
+		if err != nil && !errors.As(err, &lineErr) {
+			writer.Flush()
+			return summary, err
+		}
+		summary.Records++
+		if err == nil {
+			result, err := scoring.CalculateWithProfile(apt.Scores, p)
+			if err == nil {
+				summary.Stats.Add(result.TotalScore)
+				if err := writer.Write(NewScoredApartmentRecord(apt, result)); err != nil {
+					return summary, err
+				}
+				continue
+			}
+			lineErr = &LineError{Line: reader.Line(), Err: fmt.Errorf("아파트 %s: %w", apt.ID, err)}
+		}
+		if !opts.SkipInvalid {
+			writer.Flush()
+			return summary, lineErr
+		}
+		summary.Skipped++
+		if opts.OnSkip != nil {
+			opts.OnSkip(lineErr)
+		}
+	}
+	return summary, writer.Flush()
+}
@@ -0,0 +1,89 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"apart_score/pkg/scoring"
+)
+
+func TestNDJSONReader(t *testing.T) {
+	input := `{"id":"a1","name":"래미안","scores":{"Floor Level":85.5,"역까지 거리":90,"Elevator Presence":null}}
+
+{"scores":{"Floor Level":60}}
+{"id":"bad","scores":{"Floor Level":150}}
+{"id":"broken",
+`
+	reader := NewNDJSONReader(strings.NewReader(input))
+	first, err := reader.Read()
+	if err != nil || first.ID != "a1" || first.Scores[0] != 85500 || !first.Scores[2].IsMissing() || !first.Scores[3].IsMissing() {
+		t.Fatalf("first record: %+v, %v", first, err)
+	}
+	second, err := reader.Read()
+	if err != nil || second.ID != "3" || second.Name != "3" {
+		t.Errorf("second record defaults: %+v, %v", second, err)
+	}
+	for _, line := range []int{4, 5} {
+		var lineErr *LineError
+		if _, err := reader.Read(); !errors.As(err, &lineErr) || lineErr.Line != line {
+			t.Errorf("line %d: %v", line, err)
+		}
+	}
+	if _, err := reader.Read(); err != io.EOF {
+		t.Errorf("end of input: %v", err)
+	}
+}
+
+func TestScoreStream(t *testing.T) {
+	var input bytes.Buffer
+	writer := NewNDJSONWriter(&input)
+	for _, apt := range testApartments() {
+		if err := writer.Write(NewApartmentRecord(apt)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		t.Fatal(err)
+	}
+	input.WriteString("{\"id\":\"bad\",\"scores\":{\"Parking\":-1}}\n")
+	p := scoring.ScoringProfile{Scenario: scoring.ScenarioTransportation}
+
+	var out bytes.Buffer
+	_, err := ScoreStream(bytes.NewReader(input.Bytes()), &out, p, StreamOptions{})
+	var lineErr *LineError
+	if !errors.As(err, &lineErr) || lineErr.Line != 3 {
+		t.Fatalf("invalid record: %v", err)
+	}
+	if lines := strings.Count(out.String(), "\n"); lines != 2 {
+		t.Errorf("%d lines written before the error, want 2", lines)
+	}
+
+	out.Reset()
+	var skipped []int
+	opts := StreamOptions{SkipInvalid: true, OnSkip: func(err *LineError) { skipped = append(skipped, err.Line) }}
+	summary, err := ScoreStream(bytes.NewReader(input.Bytes()), &out, p, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.Records != 3 || summary.Skipped != 1 || len(skipped) != 1 || skipped[0] != 3 || summary.Stats.Count != 2 {
+		t.Fatalf("summary: %+v, skipped lines %v", summary, skipped)
+	}
+	for i, line := range strings.Split(strings.TrimSpace(out.String()), "\n") {
+		var record ScoredApartmentRecord
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("line %d: %v", i+1, err)
+		}
+		apt := testApartments()[i]
+		want, err := scoring.CalculateWithProfile(apt.Scores, p)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if record.ID != apt.ID || record.TotalScore != want.TotalScore {
+			t.Errorf("line %d: %s %.3f, want %s %.3f", i+1, record.ID, record.TotalScore, apt.ID, want.TotalScore)
+		}
+	}
+}
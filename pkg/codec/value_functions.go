@@ -0,0 +1,40 @@
+package codec
+
+import (
+	"apart_score/pkg/scoring"
+	"io"
+)
+
+// ValueFunctionsRecord is the file representation of scoring.ValueFunctions, keyed by factor name.
+type ValueFunctionsRecord map[string]scoring.ValueFunction
+
+// NewValueFunctionsRecord converts value functions to their file representation.
+func NewValueFunctionsRecord(v scoring.ValueFunctions) ValueFunctionsRecord {
+	record := make(ValueFunctionsRecord, len(v))
+	for mt, f := range v {
+		record[FactorName(mt)] = f
+	}
+	return record
+}
+
+// ValueFunctions resolves factor names and validates the functions.
+func (r ValueFunctionsRecord) ValueFunctions() (scoring.ValueFunctions, error) {
+	v := make(scoring.ValueFunctions, len(r))
+	for name, f := range r {
+		mt, err := ParseFactor(name)
+		if err != nil {
+			return nil, err
+		}
+		v[mt] = f
+	}
+	return v, v.Validate()
+}
+
+// DecodeValueFunctions reads value functions keyed by factor name from JSON or YAML.
+func DecodeValueFunctions(r io.Reader, format Format) (scoring.ValueFunctions, error) {
+	var record ValueFunctionsRecord
+	if err := unmarshal(r, format, &record); err != nil {
+		return nil, err
+	}
+	return record.ValueFunctions()
+}
@@ -0,0 +1,112 @@
+package codec
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/scoring"
+	"apart_score/pkg/shared"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// WeightGroupRecord is the file representation of scoring.WeightGroup. Factors map factor
+// names to weights relative to the group's other members.
+type WeightGroupRecord struct {
+	Name    string              `json:"name"`
+	Weight  float64             `json:"weight"`
+	Factors map[string]float64  `json:"factors,omitempty"`
+	Groups  []WeightGroupRecord `json:"groups,omitempty"`
+}
+
+// WeightTreeRecord is the file representation of scoring.WeightTree.
+type WeightTreeRecord struct {
+	Groups []WeightGroupRecord `json:"groups"`
+}
+
+// NewWeightTreeRecord converts a weight tree to its file representation.
+func NewWeightTreeRecord(tree scoring.WeightTree) WeightTreeRecord {
+	return WeightTreeRecord{Groups: newWeightGroupRecords(tree.Groups)}
+}
+
+func newWeightGroupRecords(groups []scoring.WeightGroup) []WeightGroupRecord {
+	var records []WeightGroupRecord
+	for _, g := range groups {
+		record := WeightGroupRecord{Name: g.Name, Weight: g.Weight, Groups: newWeightGroupRecords(g.Groups)}
+		if len(g.Factors) > 0 {
+			record.Factors = make(map[string]float64, len(g.Factors))
+			for _, f := range g.Factors {
+				record.Factors[FactorName(f.Factor)] = f.Weight
+			}
+		}
+		records = append(records, record)
+	}
+	return records
+}
+
+// Tree resolves factor names and validates the tree. Factors within a group are ordered by
+// metadata type.
+func (r WeightTreeRecord) Tree() (scoring.WeightTree, error) {
+	groups, err := weightGroups(r.Groups)
+	if err != nil {
+		return scoring.WeightTree{}, err
+	}
+	tree := scoring.WeightTree{Groups: groups}
+	return tree, tree.Validate()
+}
+
+func weightGroups(records []WeightGroupRecord) ([]scoring.WeightGroup, error) {
+	var groups []scoring.WeightGroup
+	for _, record := range records {
+		subgroups, err := weightGroups(record.Groups)
+		if err != nil {
+			return nil, err
+		}
+		g := scoring.WeightGroup{Name: record.Name, Weight: record.Weight, Groups: subgroups}
+		for name, w := range record.Factors {
+			mt, err := ParseFactor(name)
+			if err != nil {
+				return nil, err
+			}
+			g.Factors = append(g.Factors, scoring.FactorWeight{Factor: mt, Weight: w})
+		}
+		sort.Slice(g.Factors, func(i, j int) bool { return g.Factors[i].Factor < g.Factors[j].Factor })
+		groups = append(groups, g)
+	}
+	return groups, nil
+}
+
+// EncodeWeightTree writes a weight tree as JSON or YAML.
+func EncodeWeightTree(w io.Writer, format Format, tree scoring.WeightTree) error {
+	return Encode(w, format, NewWeightTreeRecord(tree))
+}
+
+// DecodeWeightTree reads a weight tree from JSON or YAML.
+func DecodeWeightTree(r io.Reader, format Format) (scoring.WeightTree, error) {
+	var record WeightTreeRecord
+	if err := unmarshal(r, format, &record); err != nil {
+		return scoring.WeightTree{}, err
+	}
+	return record.Tree()
+}
+
+// isWeightTree reports whether a JSON or YAML weights document is a tree, which is recognised
+// by its top-level "groups" key ("groups" is not a factor name).
+func isWeightTree(data []byte, format Format) bool {
+	var probe map[string]json.RawMessage
+	if err := unmarshal(bytes.NewReader(data), format, &probe); err != nil {
+		return false
+	}
+	_, isTree := probe["groups"]
+	return isTree
+}
+
+// decodeTreeWeights reads a weight tree document and flattens it.
+func decodeTreeWeights(data []byte, format Format) (map[metadata.MetadataType]shared.Weight, error) {
+	tree, err := DecodeWeightTree(bytes.NewReader(data), format)
+	if err != nil {
+		return nil, fmt.Errorf("가중치 트리: %w", err)
+	}
+	return tree.Weights()
+}
@@ -0,0 +1,585 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The supported YAML subset is documented in the package comment (codec.go).
+
+type yamlKind int
+
+const (
+	yamlScalar yamlKind = iota
+	yamlMapping
+	yamlSequence
+)
+
+// yamlNode is an ordered document tree shared by the JSON and YAML sides; scalars hold JSON literals.
+type yamlNode struct {
+	kind   yamlKind
+	scalar string
+	keys   []string
+	values []*yamlNode
+}
+
+// jsonToYAML converts a JSON document to YAML, keeping object key order.
+func jsonToYAML(data []byte) ([]byte, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	node, err := readJSONNode(decoder)
+	if err != nil {
+		return nil, err
+	}
+	var b strings.Builder
+	if node.kind == yamlScalar || len(node.values) == 0 {
+		b.WriteString(flowValue(node))
+		b.WriteByte('\n')
+	} else {
+		writeYAMLBlock(&b, node, 0)
+	}
+	return []byte(b.String()), nil
+}
+
+func readJSONNode(decoder *json.Decoder) (*yamlNode, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := token.(type) {
+	case json.Delim:
+		node := &yamlNode{kind: yamlSequence}
+		if t == '{' {
+			node.kind = yamlMapping
+		}
+		for decoder.More() {
+			if node.kind == yamlMapping {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				node.keys = append(node.keys, key.(string))
+			}
+			value, err := readJSONNode(decoder)
+			if err != nil {
+				return nil, err
+			}
+			node.values = append(node.values, value)
+		}
+		_, err := decoder.Token() // 닫는 괄호
+		return node, err
+	case string:
+		quoted, _ := json.Marshal(t)
+		return &yamlNode{scalar: string(quoted)}, nil
+	case json.Number:
+		return &yamlNode{scalar: t.String()}, nil
+	case bool:
+		return &yamlNode{scalar: strconv.FormatBool(t)}, nil
+	default:
+		return &yamlNode{scalar: "null"}, nil
+	}
+}
+
+// writeYAMLBlock writes a non-empty mapping or sequence at the given indentation.
+func writeYAMLBlock(b *strings.Builder, node *yamlNode, indent int) {
+	pad := strings.Repeat(" ", indent)
+	for i, value := range node.values {
+		b.WriteString(pad)
+		if node.kind == yamlMapping {
+			b.WriteString(yamlString(node.keys[i]))
+			b.WriteByte(':')
+		} else {
+			b.WriteByte('-')
+		}
+		if value.kind == yamlScalar || len(value.values) == 0 {
+			b.WriteByte(' ')
+			b.WriteString(flowValue(value))
+			b.WriteByte('\n')
+			continue
+		}
+		if node.kind == yamlSequence && value.kind == yamlMapping {
+			// 시퀀스 항목의 첫 키는 "- " 뒤에 이어서 출력
+			var item strings.Builder
+			writeYAMLBlock(&item, value, indent+2)
+			b.WriteByte(' ')
+			b.WriteString(strings.TrimPrefix(item.String(), pad+"  "))
+			continue
+		}
+		b.WriteByte('\n')
+		writeYAMLBlock(b, value, indent+2)
+	}
+}
+
+// flowValue renders a scalar or an empty collection on one line.
+func flowValue(node *yamlNode) string {
+	switch node.kind {
+	case yamlMapping:
+		return "{}"
+	case yamlSequence:
+		return "[]"
+	}
+	if strings.HasPrefix(node.scalar, `"`) {
+		var s string
+		_ = json.Unmarshal([]byte(node.scalar), &s)
+		return yamlString(s)
+	}
+	return node.scalar
+}
+
+// yamlString writes s plain when it would read back as the same string, double-quoted otherwise.
+func yamlString(s string) string {
+	if s == "" || s != strings.TrimSpace(s) || strings.ContainsAny(s[:1], "-?:,[]{}#&*!|>'\"%@`~") ||
+		strings.Contains(s, ": ") || strings.Contains(s, " #") || strings.HasSuffix(s, ":") {
+		return jsonQuote(s)
+	}
+	for _, r := range s {
+		if r < ' ' || r == 0x7f {
+			return jsonQuote(s)
+		}
+	}
+	if literal, _ := plainScalar(s); literal != jsonQuote(s) {
+		return jsonQuote(s) // 숫자, 불리언, null로 읽히는 문자열
+	}
+	return s
+}
+
+func jsonQuote(s string) string {
+	var quoted bytes.Buffer
+	encoder := json.NewEncoder(&quoted)
+	encoder.SetEscapeHTML(false)
+	_ = encoder.Encode(s)
+	return strings.TrimSuffix(quoted.String(), "\n")
+}
+
+// yamlLine is a significant line with its indentation and comment removed.
+type yamlLine struct {
+	number int
+	indent int
+	text   string
+}
+
+// yamlToJSON converts a YAML document in the supported subset to JSON.
+func yamlToJSON(data []byte) ([]byte, error) {
+	lines, err := splitYAMLLines(string(data))
+	if err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("YAML 문서가 비어 있습니다")
+	}
+	parser := &yamlParser{lines: lines}
+	node, err := parser.parseBlock(lines[0].indent)
+	if err != nil {
+		return nil, err
+	}
+	if parser.pos < len(lines) {
+		return nil, parser.errorf(lines[parser.pos], "들여쓰기가 올바르지 않습니다")
+	}
+	var b bytes.Buffer
+	writeJSONNode(&b, node)
+	return b.Bytes(), nil
+}
+
+func splitYAMLLines(data string) ([]yamlLine, error) {
+	var lines []yamlLine
+	for i, raw := range strings.Split(strings.TrimPrefix(data, "\ufeff"), "\n") {
+		trimmed := strings.TrimLeft(raw, " ")
+		if strings.HasPrefix(trimmed, "\t") {
+			return nil, fmt.Errorf("YAML %d행: 들여쓰기에 탭을 사용할 수 없습니다", i+1)
+		}
+		text := strings.TrimSpace(stripComment(trimmed))
+		if text == "" || (len(lines) == 0 && text == "---") {
+			continue
+		}
+		if text == "---" || text == "..." {
+			return nil, fmt.Errorf("YAML %d행: 여러 문서는 지원하지 않습니다", i+1)
+		}
+		lines = append(lines, yamlLine{number: i + 1, indent: len(raw) - len(trimmed), text: text})
+	}
+	return lines, nil
+}
+
+// stripComment removes a trailing comment outside of quotes.
+func stripComment(text string) string {
+	var quote byte
+	for i := 0; i < len(text); i++ {
+		c := text[i]
+		switch {
+		case quote == '"' && c == '\\', quote == '\'' && c == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case (c == '"' || c == '\'') && (i == 0 || text[i-1] == ' '):
+			quote = c
+		case c == '#' && (i == 0 || text[i-1] == ' ' || text[i-1] == '\t'):
+			return text[:i]
+		}
+	}
+	return text
+}
+
+type yamlParser struct {
+	lines []yamlLine
+	pos   int
+}
+
+func (p *yamlParser) errorf(line yamlLine, format string, args ...interface{}) error {
+	return fmt.Errorf("YAML %d행: %s", line.number, fmt.Sprintf(format, args...))
+}
+
+func isSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// parseBlock parses the mapping or sequence whose entries start at indent.
+func (p *yamlParser) parseBlock(indent int) (*yamlNode, error) {
+	first := p.lines[p.pos]
+	if isSequenceItem(first.text) {
+		return p.parseSequence(indent)
+	}
+	if _, _, ok := splitMappingEntry(first.text); !ok {
+		if p.pos == len(p.lines)-1 && indent == 0 {
+			p.pos++
+			return parseFlow(first.text, func(msg string) error { return p.errorf(first, "%s", msg) })
+		}
+		return nil, p.errorf(first, "매핑 또는 시퀀스가 필요합니다: %q", first.text)
+	}
+	return p.parseMapping(indent)
+}
+
+func (p *yamlParser) parseSequence(indent int) (*yamlNode, error) {
+	node := &yamlNode{kind: yamlSequence}
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent || (line.indent == indent && !isSequenceItem(line.text)) {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf(line, "들여쓰기가 올바르지 않습니다")
+		}
+		rest := strings.TrimLeft(strings.TrimPrefix(line.text, "-"), " ")
+		var value *yamlNode
+		var err error
+		switch {
+		case rest == "":
+			p.pos++
+			value, err = p.parseChild(indent, line)
+		case isSequenceItem(rest):
+			return nil, p.errorf(line, "중첩된 인라인 시퀀스는 지원하지 않습니다")
+		default:
+			if _, _, ok := splitMappingEntry(rest); ok {
+				// "- key: value"는 항목 위치에서 시작하는 매핑
+				p.lines[p.pos] = yamlLine{number: line.number, indent: line.indent + len(line.text) - len(rest), text: rest}
+				value, err = p.parseMapping(p.lines[p.pos].indent)
+			} else {
+				p.pos++
+				value, err = parseFlow(rest, func(msg string) error { return p.errorf(line, "%s", msg) })
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		// 빈 "-"나 null 항목은 레코드가 아니므로 조용히 0값 레코드로 읽히지 않게 거부
+		if value.kind == yamlScalar && value.scalar == "null" {
+			return nil, p.errorf(line, "빈(null) 시퀀스 항목은 지원하지 않습니다")
+		}
+		node.values = append(node.values, value)
+	}
+	return node, nil
+}
+
+func (p *yamlParser) parseMapping(indent int) (*yamlNode, error) {
+	node := &yamlNode{kind: yamlMapping}
+	seen := make(map[string]bool)
+	for p.pos < len(p.lines) {
+		line := p.lines[p.pos]
+		if line.indent < indent {
+			break
+		}
+		if line.indent > indent {
+			return nil, p.errorf(line, "들여쓰기가 올바르지 않습니다")
+		}
+		rawKey, rest, ok := splitMappingEntry(line.text)
+		if !ok {
+			return nil, p.errorf(line, "\"키: 값\" 형식이 필요합니다: %q", line.text)
+		}
+		key, err := parseKey(rawKey)
+		if err != nil {
+			return nil, p.errorf(line, "%v", err)
+		}
+		if seen[key] {
+			return nil, p.errorf(line, "중복된 키: %q", key)
+		}
+		seen[key] = true
+		p.pos++
+
+		var value *yamlNode
+		if rest == "" {
+			value, err = p.parseChild(indent, line)
+		} else {
+			value, err = parseFlow(rest, func(msg string) error { return p.errorf(line, "%s", msg) })
+		}
+		if err != nil {
+			return nil, err
+		}
+		node.keys = append(node.keys, key)
+		node.values = append(node.values, value)
+	}
+	return node, nil
+}
+
+// parseChild parses the block nested under a key or "-" with no inline value; none means null.
+// A sequence may sit at the same indentation as its parent key.
+func (p *yamlParser) parseChild(indent int, parent yamlLine) (*yamlNode, error) {
+	if p.pos < len(p.lines) {
+		next := p.lines[p.pos]
+		if next.indent > indent || (next.indent == indent && isSequenceItem(next.text) && !isSequenceItem(parent.text)) {
+			return p.parseBlock(next.indent)
+		}
+	}
+	return &yamlNode{scalar: "null"}, nil
+}
+
+// splitMappingEntry splits "key: value" at the first ": " (or trailing ':') outside quotes.
+func splitMappingEntry(text string) (key, rest string, ok bool) {
+	start := 0
+	if text != "" && (text[0] == '"' || text[0] == '\'') {
+		end := closingQuote(text)
+		if end < 0 {
+			return "", "", false
+		}
+		start = end + 1
+	}
+	for i := start; i < len(text); i++ {
+		if text[i] == ':' && (i == len(text)-1 || text[i+1] == ' ') {
+			if start == 0 && strings.ContainsAny(text[:1], "[{") {
+				return "", "", false
+			}
+			return strings.TrimSpace(text[:i]), strings.TrimSpace(text[i+1:]), true
+		}
+	}
+	return "", "", false
+}
+
+// closingQuote returns the index of the quote closing the one at text[0], or -1.
+func closingQuote(text string) int {
+	quote := text[0]
+	for i := 1; i < len(text); i++ {
+		switch {
+		case quote == '"' && text[i] == '\\':
+			i++
+		case text[i] == quote && quote == '\'' && i+1 < len(text) && text[i+1] == '\'':
+			i++
+		case text[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+func parseKey(raw string) (string, error) {
+	if raw == "" {
+		return "", errors.New("빈 키")
+	}
+	node, err := parseScalar(raw)
+	if err != nil {
+		return "", err
+	}
+	if strings.HasPrefix(node.scalar, `"`) {
+		var key string
+		err := json.Unmarshal([]byte(node.scalar), &key)
+		return key, err
+	}
+	return raw, nil
+}
+
+// parseFlow parses an inline value: a scalar, an empty collection or JSON-compatible flow.
+func parseFlow(text string, errorf func(string) error) (*yamlNode, error) {
+	switch {
+	case text == "{}":
+		return &yamlNode{kind: yamlMapping}, nil
+	case text == "[]":
+		return &yamlNode{kind: yamlSequence}, nil
+	case strings.HasPrefix(text, "{") || strings.HasPrefix(text, "["):
+		decoder := json.NewDecoder(strings.NewReader(text))
+		decoder.UseNumber()
+		node, err := readJSONNode(decoder)
+		if err != nil {
+			// JSON이 아니면 {층수: 50}처럼 스칼라만 담은 한 단계 컬렉션으로 읽음
+			node, err = parsePlainFlow(text)
+			if err != nil {
+				return nil, errorf(err.Error())
+			}
+			return node, nil
+		}
+		if _, err := decoder.Token(); err != io.EOF {
+			return nil, errorf("플로우 컬렉션 뒤에 불필요한 내용이 있습니다: " + text)
+		}
+		return node, nil
+	case strings.ContainsAny(text[:1], "&*!|>%@`"):
+		return nil, errorf("지원하지 않는 YAML 구문입니다 (앵커, 태그, 블록 스칼라): " + text)
+	}
+	node, err := parseScalar(text)
+	if err != nil {
+		return nil, errorf(err.Error())
+	}
+	return node, nil
+}
+
+// parsePlainFlow parses a single-level flow collection of scalars such as {층수: 50, 학군: 80} or [a, b].
+func parsePlainFlow(text string) (*yamlNode, error) {
+	closing := byte('}')
+	node := &yamlNode{kind: yamlMapping}
+	if text[0] == '[' {
+		closing, node.kind = ']', yamlSequence
+	}
+	if text[len(text)-1] != closing {
+		return nil, fmt.Errorf("플로우 컬렉션이 닫히지 않았습니다: %s", text)
+	}
+	entries, err := splitFlowEntries(text[1 : len(text)-1])
+	if err != nil {
+		return nil, fmt.Errorf("%v: %s", err, text)
+	}
+	seen := make(map[string]bool)
+	for i, entry := range entries {
+		if entry == "" {
+			if i == len(entries)-1 && i > 0 {
+				break // 마지막 쉼표 허용
+			}
+			return nil, fmt.Errorf("빈 플로우 항목: %s", text)
+		}
+		if strings.ContainsAny(entry[:1], "&*!|>%@`") {
+			return nil, fmt.Errorf("지원하지 않는 YAML 구문입니다 (앵커, 태그, 블록 스칼라): %s", text)
+		}
+		rest := entry
+		if node.kind == yamlMapping {
+			rawKey, value, ok := splitMappingEntry(entry)
+			if !ok || value == "" {
+				return nil, fmt.Errorf("\"키: 값\" 형식이 필요합니다: %q", entry)
+			}
+			key, err := parseKey(rawKey)
+			if err != nil {
+				return nil, err
+			}
+			if seen[key] {
+				return nil, fmt.Errorf("중복된 키: %q", key)
+			}
+			seen[key] = true
+			node.keys = append(node.keys, key)
+			rest = value
+		}
+		value, err := parseScalar(rest)
+		if err != nil {
+			return nil, err
+		}
+		node.values = append(node.values, value)
+	}
+	return node, nil
+}
+
+// splitFlowEntries splits the inside of a flow collection at commas outside quoted scalars.
+func splitFlowEntries(text string) ([]string, error) {
+	var entries []string
+	start := 0
+	tokenStart := true // 따옴표는 항목이나 값의 시작에서만 인용을 엶
+	for i := 0; i < len(text); i++ {
+		switch c := text[i]; {
+		case c == ' ':
+		case tokenStart && (c == '"' || c == '\''):
+			end := closingQuote(text[i:])
+			if end < 0 {
+				return nil, errors.New("따옴표가 올바르게 닫히지 않았습니다")
+			}
+			i += end
+			tokenStart = false
+		case c == '{' || c == '}' || c == '[' || c == ']':
+			return nil, errors.New("중첩된 플로우 컬렉션은 JSON 형식만 지원합니다")
+		case c == ',':
+			entries = append(entries, strings.TrimSpace(text[start:i]))
+			start = i + 1
+			tokenStart = true
+		default:
+			tokenStart = c == ':' && (i+1 == len(text) || text[i+1] == ' ')
+		}
+	}
+	return append(entries, strings.TrimSpace(text[start:])), nil
+}
+
+func parseScalar(text string) (*yamlNode, error) {
+	if text == "" {
+		return nil, errors.New("빈 값")
+	}
+	switch text[0] {
+	case '"':
+		if closingQuote(text) != len(text)-1 {
+			return nil, fmt.Errorf("따옴표가 올바르게 닫히지 않았습니다: %s", text)
+		}
+		var s string
+		if err := json.Unmarshal([]byte(text), &s); err != nil {
+			return nil, fmt.Errorf("지원하지 않는 이스케이프입니다: %s", text)
+		}
+		return &yamlNode{scalar: jsonQuote(s)}, nil
+	case '\'':
+		if closingQuote(text) != len(text)-1 {
+			return nil, fmt.Errorf("따옴표가 올바르게 닫히지 않았습니다: %s", text)
+		}
+		return &yamlNode{scalar: jsonQuote(strings.ReplaceAll(text[1:len(text)-1], "''", "'"))}, nil
+	}
+	literal, _ := plainScalar(text)
+	return &yamlNode{scalar: literal}, nil
+}
+
+// plainScalar resolves an unquoted scalar to a JSON literal (null, bool, number or string).
+func plainScalar(text string) (string, bool) {
+	switch text {
+	case "null", "Null", "NULL", "~":
+		return "null", true
+	case "true", "True", "TRUE":
+		return "true", true
+	case "false", "False", "FALSE":
+		return "false", true
+	}
+	if v, err := strconv.ParseFloat(text, 64); err == nil && !math.IsInf(v, 0) && !math.IsNaN(v) &&
+		!strings.ContainsAny(text, "xXoObB_") {
+		if json.Valid([]byte(text)) {
+			return text, true
+		}
+		return strconv.FormatFloat(v, 'g', -1, 64), true
+	}
+	return jsonQuote(text), false
+}
+
+func writeJSONNode(b *bytes.Buffer, node *yamlNode) {
+	switch node.kind {
+	case yamlScalar:
+		b.WriteString(node.scalar)
+	case yamlMapping:
+		b.WriteByte('{')
+		for i, key := range node.keys {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			b.WriteString(jsonQuote(key))
+			b.WriteByte(':')
+			writeJSONNode(b, node.values[i])
+		}
+		b.WriteByte('}')
+	case yamlSequence:
+		b.WriteByte('[')
+		for i, value := range node.values {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			writeJSONNode(b, value)
+		}
+		b.WriteByte(']')
+	}
+}
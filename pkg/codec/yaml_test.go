@@ -0,0 +1,91 @@
+package codec
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestYAMLToJSON(t *testing.T) {
+	input := `
+# 아파트 목록
+- id: "001"
+  name: 'It''s #1'   # 주석
+  tags:
+  - a
+  - b: 1
+  nested:
+    empty: {}
+    list: []
+    flow: {"x": [1, 2]}
+    plain: {층수: 50, 'a, b': "c: d", e: It's}
+    items: [x, 'y, z', 3,]
+    none:
+- id: plain text: no
+  value: -1.5e2
+  flag: true
+  tilde: ~
+`
+	data, err := yamlToJSON([]byte(input))
+	if err != nil {
+		t.Fatalf("yamlToJSON failed: %v", err)
+	}
+	var got interface{}
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("invalid JSON %s: %v", data, err)
+	}
+	var want interface{}
+	_ = json.Unmarshal([]byte(`[
+		{"id": "001", "name": "It's #1", "tags": ["a", {"b": 1}],
+		 "nested": {"empty": {}, "list": [], "flow": {"x": [1, 2]},
+		 "plain": {"층수": 50, "a, b": "c: d", "e": "It's"}, "items": ["x", "y, z", 3], "none": null}},
+		{"id": "plain text: no", "value": -150, "flag": true, "tilde": null}
+	]`), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got %s", data)
+	}
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	input := `{"s": ["", " pad", "123", "null", "a: b", "#x", "줄\n바꿈", "평범한 문자열"],
+		"n": [0, -2.5, 1e+21], "b": false, "z": null, "m": {"k": {"deep": [[1], {}]}}}`
+	yaml, err := jsonToYAML([]byte(input))
+	if err != nil {
+		t.Fatalf("jsonToYAML failed: %v", err)
+	}
+	data, err := yamlToJSON(yaml)
+	if err != nil {
+		t.Fatalf("yamlToJSON failed: %v\n%s", err, yaml)
+	}
+	var got, want interface{}
+	_ = json.Unmarshal(data, &got)
+	_ = json.Unmarshal([]byte(input), &want)
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch\nyaml:\n%s\njson: %s", yaml, data)
+	}
+	if !strings.Contains(string(yaml), "- 평범한 문자열\n") {
+		t.Errorf("plain strings should not be quoted:\n%s", yaml)
+	}
+}
+
+func TestYAMLRejectsUnsupported(t *testing.T) {
+	for name, input := range map[string]string{
+		"anchor":       "a: &x 1\n",
+		"block scalar": "a: |\n  text\n",
+		"tab":          "a:\n\tb: 1\n",
+		"duplicate":    "a: 1\na: 2\n",
+		"indent":       "a: 1\n   b: 2\n",
+		"documents":    "a: 1\n---\nb: 2\n",
+		"nested flow":  "a: [x, {y: 1}]\n",
+		"open flow":    "a: {x: 1\n",
+		"flow entry":   "a: {x: 1, , y: 2}\n",
+		"unterminated": "a: \"x\n",
+		"empty item":   "- id: a\n-\n",
+		"null item":    "- id: a\n- ~\n",
+	} {
+		if _, err := yamlToJSON([]byte(input)); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
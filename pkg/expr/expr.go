@@ -0,0 +1,214 @@
+// Package expr implements a small sandboxed expression language over apartment factors.
+//
+// Expressions read factor scores in points (0-100) by name and support arithmetic
+// (+ - * / %), comparisons (< <= > >= == !=), logic (and, or, not or && || !), the functions
+// min, max, clamp and abs, and conditionals (if ... then ... else ...). A factor is named by an
+// identifier that matches its English or Korean name when case, spaces and underscores are
+// ignored (ApartmentSize, 아파트_크기), or by its exact name in backticks (`Distance to Station`).
+// total (or 총점) reads the running total of a calculation pipeline.
+//
+// There are no loops, assignments or calls outside the built-in functions, so evaluation
+// always terminates. Expressions are type checked when parsed, and parse and evaluation
+// errors are reported as *Error with the position in the source.
+package expr
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"fmt"
+	"math"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxDepth bounds how deeply parentheses, calls, conditionals and unary operators nest,
+// which keeps the parser's recursion bounded.
+const maxDepth = 64
+
+// Type is the type of an expression's value.
+type Type int
+
+const (
+	Number Type = iota // 숫자
+	Bool               // 참/거짓
+)
+
+func (t Type) String() string {
+	if t == Bool {
+		return "조건"
+	}
+	return "숫자"
+}
+
+// Error is a parse or evaluation error at a position in the expression source.
+type Error struct {
+	Source  string // 식 원문
+	Offset  int    // 바이트 위치 (0부터)
+	Line    int    // 줄 (1부터)
+	Column  int    // 문자 단위 열 (1부터)
+	Message string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("식 %d:%d: %s", e.Line, e.Column, e.Message)
+}
+
+// Context returns the source line of the error with a caret under the error position,
+// counting Hangul and other wide characters as two terminal columns.
+func (e *Error) Context() string {
+	lines := strings.Split(e.Source, "\n")
+	if e.Line < 1 || e.Line > len(lines) {
+		return ""
+	}
+	line := lines[e.Line-1]
+	width := 0
+	for i, r := range []rune(line) {
+		if i >= e.Column-1 {
+			break
+		}
+		width++
+		if isWide(r) {
+			width++
+		}
+	}
+	return line + "\n" + strings.Repeat(" ", width) + "^"
+}
+
+// isWide reports whether a rune takes two terminal columns.
+func isWide(r rune) bool {
+	return (r >= 0x1100 && r <= 0x115F) || (r >= 0x2E80 && r <= 0xA4CF) ||
+		(r >= 0xAC00 && r <= 0xD7A3) || (r >= 0xF900 && r <= 0xFAFF) || (r >= 0xFF00 && r <= 0xFF60)
+}
+
+// newError locates offset in source.
+func newError(source string, offset int, format string, args ...interface{}) *Error {
+	prefix := source[:offset]
+	line := strings.Count(prefix, "\n") + 1
+	column := utf8.RuneCountInString(prefix[strings.LastIndex(prefix, "\n")+1:]) + 1
+	return &Error{Source: source, Offset: offset, Line: line, Column: column, Message: fmt.Sprintf(format, args...)}
+}
+
+// Env supplies the values an expression reads.
+type Env struct {
+	// Score returns a factor's score in points, or false when the factor has no data.
+	Score func(metadata.MetadataType) (float64, bool)
+	Total float64 // total(총점)로 읽는 누적 총점
+}
+
+// MapEnv reads scores from a score map; as elsewhere, a factor absent from the map scores 0.
+func MapEnv(scores map[metadata.MetadataType]shared.ScoreValue) Env {
+	return Env{Score: func(mt metadata.MetadataType) (float64, bool) {
+		score := scores[mt]
+		return score.ToFloat(), !score.IsMissing()
+	}}
+}
+
+// ArrayEnv reads scores from a score array with the given running total.
+func ArrayEnv(scores shared.ScoreArray, total float64) Env {
+	return Env{Total: total, Score: func(mt metadata.MetadataType) (float64, bool) {
+		if !mt.IsValid() {
+			return 0, false
+		}
+		score := scores[mt]
+		return score.ToFloat(), !score.IsMissing()
+	}}
+}
+
+// Expr is a parsed, type-checked expression. It is immutable and safe for concurrent use.
+type Expr struct {
+	source    string
+	root      node
+	factors   []metadata.MetadataType
+	usesTotal bool
+}
+
+// Parse parses and type checks an expression.
+func Parse(source string) (*Expr, error) {
+	p := &parser{lexer: lexer{source: source}, factors: map[metadata.MetadataType]bool{}}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	if p.tok.kind == tokEOF {
+		return nil, newError(source, 0, "식이 비어 있습니다")
+	}
+	root, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if p.tok.kind != tokEOF {
+		return nil, p.errorf("예상하지 못한 %s", p.tok)
+	}
+	e := &Expr{source: source, root: root, usesTotal: p.usesTotal}
+	for _, mt := range metadata.All() {
+		if p.factors[mt] {
+			e.factors = append(e.factors, mt)
+		}
+	}
+	return e, nil
+}
+
+// String returns the expression source.
+func (e *Expr) String() string {
+	return e.source
+}
+
+// Type returns the type of the expression's value.
+func (e *Expr) Type() Type {
+	return e.root.typ()
+}
+
+// Factors returns the factors the expression reads, in metadata order.
+func (e *Expr) Factors() []metadata.MetadataType {
+	return append([]metadata.MetadataType(nil), e.factors...)
+}
+
+// UsesTotal reports whether the expression reads the running total.
+func (e *Expr) UsesTotal() bool {
+	return e.usesTotal
+}
+
+// Eval evaluates a number expression. The result is NaN when it depends on a factor without
+// data; comparisons involving such a factor are false instead.
+func (e *Expr) Eval(env Env) (float64, error) {
+	if e.Type() != Number {
+		return 0, newError(e.source, e.root.pos(), "숫자가 아닌 %s 식입니다", e.Type())
+	}
+	return e.root.eval(e, env)
+}
+
+// Test evaluates a condition expression.
+func (e *Expr) Test(env Env) (bool, error) {
+	if e.Type() != Bool {
+		return false, newError(e.source, e.root.pos(), "조건이 아닌 %s 식입니다", e.Type())
+	}
+	v, err := e.root.eval(e, env)
+	return v != 0, err
+}
+
+// MarshalText writes the expression source, so expressions are stored as strings.
+func (e *Expr) MarshalText() ([]byte, error) {
+	return []byte(e.source), nil
+}
+
+// UnmarshalText parses an expression source.
+func (e *Expr) UnmarshalText(text []byte) error {
+	parsed, err := Parse(string(text))
+	if err != nil {
+		return err
+	}
+	*e = *parsed
+	return nil
+}
+
+// truth converts a condition to the number a Bool node evaluates to.
+func truth(b bool) float64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+// isMissing reports whether a number depends on a factor without data.
+func isMissing(v float64) bool {
+	return math.IsNaN(v)
+}
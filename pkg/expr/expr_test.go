@@ -0,0 +1,160 @@
+package expr
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+)
+
+func testEnv() Env {
+	env := MapEnv(map[metadata.MetadataType]shared.ScoreValue{
+		metadata.ApartmentSize:     shared.ScoreValueFromFloat(80),
+		metadata.MaintenanceFee:    shared.ScoreValueFromFloat(70),
+		metadata.SchoolDistrict:    shared.ScoreValueFromFloat(90),
+		metadata.DistanceToStation: shared.ScoreValueFromFloat(60),
+		metadata.Parking:           shared.MissingScore,
+	})
+	env.Total = 50
+	return env
+}
+
+func TestEval(t *testing.T) {
+	tests := map[string]float64{
+		"0.6*ApartmentSize + 0.4*MaintenanceFee":   76,
+		"if SchoolDistrict > 85 then +3":           3,
+		"if 학군 > 95 then 3":                        0,
+		"if 학군 > 95 then 3 else -2 * 2":            -4,
+		"clamp(`Distance to Station` * 2, 0, 100)": 100,
+		"min(ApartmentSize, 관리비, 75) + max(1, 2)":  72,
+		"abs(아파트_크기 - 학군) % 3":                     1,
+		"-(1 + 2) * 3 - 2 / 4":                     -9.5,
+		"total * 2":                                100,
+		"1e2 + .5":                                 100.5,
+		"Parking + 1":                              math.NaN(),
+		"max(Parking, 50)":                         math.NaN(),
+		"if Parking > 50 then 1 else 2":            2,
+	}
+	for src, want := range tests {
+		e, err := Parse(src)
+		if err != nil {
+			t.Errorf("%s: %v", src, err)
+			continue
+		}
+		got, err := e.Eval(testEnv())
+		if err != nil || !(got == want || math.IsNaN(got) && math.IsNaN(want)) {
+			t.Errorf("%s = %v, %v, want %v", src, got, err, want)
+		}
+	}
+}
+
+func TestTest(t *testing.T) {
+	tests := map[string]bool{
+		"SchoolDistrict >= 90 and ApartmentSize > 75": true,
+		"학군 > 95 || 관리비 == 70":                        true,
+		"not (총점 < 60) && !(1 != 1)":                  false,
+		"Parking > 50 or Parking <= 50":               false, // 결측 요소와의 비교는 항상 거짓
+		"(1 > 0) == (2 > 1)":                          true,
+		"if TOTAL > 40 then 학군 > 85 else 학군 < 50":     true,
+	}
+	for src, want := range tests {
+		e, err := Parse(src)
+		if err != nil {
+			t.Errorf("%s: %v", src, err)
+			continue
+		}
+		if got, err := e.Test(testEnv()); err != nil || got != want {
+			t.Errorf("%s = %v, %v, want %v", src, got, err, want)
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		src     string
+		column  int
+		message string
+	}{
+		{"", 1, "비어"},
+		{"1 +", 4, "값이 필요"},
+		{"(1 + 2", 7, "')'"},
+		{"foo + 1", 1, "알 수 없는 요소"},
+		{"`Foo`", 1, "알 수 없는 요소"},
+		{"`학군", 1, "닫히지 않은"},
+		{"1 < 2 < 3", 7, "이어 쓸 수 없습니다"},
+		{"1 + (2 > 1)", 6, "숫자 식이 필요"},
+		{"if 1 then 2", 4, "조건 식이 필요"},
+		{"if 1 > 0 2", 10, "'then'"},
+		{"if 1 > 0 then 1 > 0", 15, "else 없는 if"},
+		{"if 1 > 0 then 1 else 1 > 0", 22, "타입이 다릅니다"},
+		{"1 == (1 > 0)", 3, "비교할 수 없습니다"},
+		{"a & b", 3, "'&'"},
+		{"sqrt(4)", 1, "알 수 없는 함수"},
+		{"abs(1, 2)", 1, "인자 1개"},
+		{"min(1)", 1, "2개 이상"},
+		{"max(1 2)", 7, "','"},
+		{"1e", 1, "잘못된 숫자"},
+		{"1 2", 3, "예상하지 못한 숫자 2"},
+		{"학군 +\n  관리비 *", 8, "값이 필요"},
+		{strings.Repeat("(", maxDepth+1) + "1" + strings.Repeat(")", maxDepth+1), maxDepth + 1, "중첩"},
+	}
+	for _, tt := range tests {
+		_, err := Parse(tt.src)
+		var exprErr *Error
+		if !errors.As(err, &exprErr) || exprErr.Column != tt.column || !strings.Contains(exprErr.Message, tt.message) {
+			t.Errorf("%q: %v, want column %d with %q", tt.src, err, tt.column, tt.message)
+		}
+	}
+	_, err := Parse("학군 +\n  관리비 *")
+	if exprErr := err.(*Error); exprErr.Line != 2 || exprErr.Context() != "  관리비 *\n          ^" {
+		t.Errorf("multiline error: line %d, context:\n%s", exprErr.Line, exprErr.Context())
+	}
+}
+
+func TestEvalErrors(t *testing.T) {
+	for src, column := range map[string]int{"1 / (학군 - 90)": 3, "5 % 0": 3, "clamp(1, 10, 0)": 1} {
+		e, err := Parse(src)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var exprErr *Error
+		if _, err := e.Eval(testEnv()); !errors.As(err, &exprErr) || exprErr.Column != column {
+			t.Errorf("%s: %v, want column %d", src, err, column)
+		}
+	}
+	condition, _ := Parse("1 > 0")
+	if _, err := condition.Eval(testEnv()); err == nil {
+		t.Error("Eval accepts a condition")
+	}
+	number, _ := Parse("1")
+	if _, err := number.Test(testEnv()); err == nil {
+		t.Error("Test accepts a number")
+	}
+}
+
+func TestExprMetadata(t *testing.T) {
+	e, err := Parse("max(관리비, `Apartment Size`, apartment_size) + total")
+	if err != nil {
+		t.Fatal(err)
+	}
+	factors := e.Factors()
+	if len(factors) != 2 || factors[0] != metadata.ApartmentSize || factors[1] != metadata.MaintenanceFee || !e.UsesTotal() {
+		t.Errorf("factors %v, uses total %v", factors, e.UsesTotal())
+	}
+
+	data, err := json.Marshal(map[string]*Expr{"rule": e})
+	if err != nil || string(data) != `{"rule":"max(관리비, `+"`Apartment Size`"+`, apartment_size) + total"}` {
+		t.Fatalf("marshal: %s, %v", data, err)
+	}
+	var decoded map[string]*Expr
+	if err := json.Unmarshal(data, &decoded); err != nil || decoded["rule"].String() != e.String() {
+		t.Errorf("unmarshal: %v, %v", decoded, err)
+	}
+	if err := json.Unmarshal([]byte(`{"rule":"1 +"}`), &decoded); err == nil {
+		t.Error("invalid expression unmarshaled")
+	}
+}
@@ -0,0 +1,147 @@
+package expr
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokEOF tokenKind = iota
+	tokNumber
+	tokIdent
+	tokQuoted // `...`로 감싼 요소 이름
+	tokOp
+	tokLParen
+	tokRParen
+	tokComma
+)
+
+type token struct {
+	kind  tokenKind
+	text  string  // 연산자, 식별자 또는 이름 (키워드는 소문자)
+	value float64 // tokNumber의 값
+	pos   int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokEOF:
+		return "식의 끝"
+	case tokNumber:
+		return fmt.Sprintf("숫자 %s", t.text)
+	case tokIdent, tokQuoted:
+		return fmt.Sprintf("이름 %s", t.text)
+	default:
+		return fmt.Sprintf("'%s'", t.text)
+	}
+}
+
+// keywords are the reserved words, matched case-insensitively.
+var keywords = map[string]bool{"if": true, "then": true, "else": true, "and": true, "or": true, "not": true}
+
+// operators lists multi-character operators before their single-character prefixes.
+var operators = []string{"<=", ">=", "==", "!=", "&&", "||", "<", ">", "+", "-", "*", "/", "%", "!"}
+
+type lexer struct {
+	source string
+	offset int
+}
+
+// next scans the token at the current offset.
+func (l *lexer) next() (token, error) {
+	for l.offset < len(l.source) {
+		r, size := utf8.DecodeRuneInString(l.source[l.offset:])
+		if !unicode.IsSpace(r) {
+			break
+		}
+		l.offset += size
+	}
+	start := l.offset
+	if start == len(l.source) {
+		return token{kind: tokEOF, pos: start}, nil
+	}
+	rest := l.source[start:]
+	r, size := utf8.DecodeRuneInString(rest)
+	switch {
+	case r == utf8.RuneError && size == 1:
+		return token{}, newError(l.source, start, "올바른 UTF-8 문자가 아닙니다")
+	case r == '(':
+		l.offset++
+		return token{kind: tokLParen, text: "(", pos: start}, nil
+	case r == ')':
+		l.offset++
+		return token{kind: tokRParen, text: ")", pos: start}, nil
+	case r == ',':
+		l.offset++
+		return token{kind: tokComma, text: ",", pos: start}, nil
+	case r == '`':
+		end := strings.IndexByte(rest[1:], '`')
+		if end < 0 {
+			return token{}, newError(l.source, start, "닫히지 않은 `")
+		}
+		name := strings.TrimSpace(rest[1 : end+1])
+		if name == "" {
+			return token{}, newError(l.source, start, "요소 이름이 비어 있습니다")
+		}
+		l.offset += end + 2
+		return token{kind: tokQuoted, text: name, pos: start}, nil
+	case r == '.' || (r >= '0' && r <= '9'):
+		return l.number(start)
+	case r == '_' || unicode.IsLetter(r):
+		end := start
+		for end < len(l.source) {
+			r, size := utf8.DecodeRuneInString(l.source[end:])
+			if r != '_' && !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				break
+			}
+			end += size
+		}
+		l.offset = end
+		text := l.source[start:end]
+		if keyword := strings.ToLower(text); keywords[keyword] {
+			return token{kind: tokOp, text: keyword, pos: start}, nil
+		}
+		return token{kind: tokIdent, text: text, pos: start}, nil
+	}
+	for _, op := range operators {
+		if strings.HasPrefix(rest, op) {
+			l.offset += len(op)
+			return token{kind: tokOp, text: op, pos: start}, nil
+		}
+	}
+	return token{}, newError(l.source, start, "예상하지 못한 문자 %q", r)
+}
+
+// number scans a decimal literal such as 3, 0.5, .5 or 1e3.
+func (l *lexer) number(start int) (token, error) {
+	end := start
+	digits := func() {
+		for end < len(l.source) && l.source[end] >= '0' && l.source[end] <= '9' {
+			end++
+		}
+	}
+	digits()
+	if end < len(l.source) && l.source[end] == '.' {
+		end++
+		digits()
+	}
+	if end < len(l.source) && (l.source[end] == 'e' || l.source[end] == 'E') {
+		end++
+		if end < len(l.source) && (l.source[end] == '+' || l.source[end] == '-') {
+			end++
+		}
+		digits()
+	}
+	text := l.source[start:end]
+	value, err := strconv.ParseFloat(text, 64)
+	if err != nil {
+		return token{}, newError(l.source, start, "잘못된 숫자: %s", text)
+	}
+	l.offset = end
+	return token{kind: tokNumber, text: text, value: value, pos: start}, nil
+}
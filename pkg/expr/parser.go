@@ -0,0 +1,599 @@
+package expr
+
+import (
+	"apart_score/pkg/metadata"
+	"math"
+	"strings"
+)
+
+// node is a type-checked expression tree node. Bool nodes evaluate to 1 or 0. pos is where
+// the node's source starts; binary nodes keep their operator's position for evaluation errors.
+type node interface {
+	typ() Type
+	pos() int
+	eval(e *Expr, env Env) (float64, error)
+}
+
+type parser struct {
+	lexer
+	tok       token
+	depth     int
+	factors   map[metadata.MetadataType]bool
+	usesTotal bool
+}
+
+func (p *parser) next() error {
+	tok, err := p.lexer.next()
+	p.tok = tok
+	return err
+}
+
+func (p *parser) errorf(format string, args ...interface{}) *Error {
+	return newError(p.source, p.tok.pos, format, args...)
+}
+
+// isOp reports whether the current token is one of the operators or keywords.
+func (p *parser) isOp(ops ...string) bool {
+	if p.tok.kind != tokOp {
+		return false
+	}
+	for _, op := range ops {
+		if p.tok.text == op {
+			return true
+		}
+	}
+	return false
+}
+
+// expect consumes the operator or keyword op.
+func (p *parser) expect(op string) error {
+	if !p.isOp(op) {
+		return p.errorf("'%s' 키워드가 필요합니다 (%s)", op, p.tok)
+	}
+	return p.next()
+}
+
+// enter bounds the nesting depth; every call must be paired with p.depth--.
+func (p *parser) enter() error {
+	p.depth++
+	if p.depth > maxDepth {
+		return p.errorf("식이 너무 깊게 중첩되었습니다 (최대 %d단계)", maxDepth)
+	}
+	return nil
+}
+
+// checkType reports an operand of the wrong type at its position.
+func (p *parser) checkType(n node, want Type, context string) error {
+	if n.typ() != want {
+		return newError(p.source, n.pos(), "%s에는 %s 식이 필요합니다 (현재 %s 식)", context, want, n.typ())
+	}
+	return nil
+}
+
+// parseExpr parses a full expression: expr := or.
+func (p *parser) parseExpr() (node, error) {
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+	return p.parseOr()
+}
+
+// parseOr parses or := and (("or" | "||") and)*.
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("or", "||") {
+		op := p.tok
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.logical(op, left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// parseAnd parses and := not (("and" | "&&") not)*.
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("and", "&&") {
+		op := p.tok
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		if left, err = p.logical(op, left, right); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) logical(op token, left, right node) (node, error) {
+	for _, operand := range []node{left, right} {
+		if err := p.checkType(operand, Bool, op.text); err != nil {
+			return nil, err
+		}
+	}
+	return &logicalNode{at: op.pos, or: op.text == "or" || op.text == "||", left: left, right: right}, nil
+}
+
+// parseNot parses not := ("not" | "!") not | comparison.
+func (p *parser) parseNot() (node, error) {
+	if !p.isOp("not", "!") {
+		return p.parseComparison()
+	}
+	op := p.tok
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	operand, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkType(operand, Bool, op.text); err != nil {
+		return nil, err
+	}
+	return &notNode{at: op.pos, operand: operand}, nil
+}
+
+// parseComparison parses comparison := sum (op sum)?; comparisons do not chain.
+func (p *parser) parseComparison() (node, error) {
+	left, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if !p.isOp("<", "<=", ">", ">=", "==", "!=") {
+		return left, nil
+	}
+	op := p.tok
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	right, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.isOp("<", "<=", ">", ">=", "==", "!=") {
+		return nil, p.errorf("비교 연산은 이어 쓸 수 없습니다 (and로 나누어 쓰세요)")
+	}
+	if op.text == "==" || op.text == "!=" {
+		if left.typ() != right.typ() {
+			return nil, newError(p.source, op.pos, "%s 식과 %s 식은 비교할 수 없습니다", left.typ(), right.typ())
+		}
+	} else {
+		for _, operand := range []node{left, right} {
+			if err := p.checkType(operand, Number, op.text); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &compareNode{at: op.pos, op: op.text, left: left, right: right}, nil
+}
+
+// parseSum parses sum := product (("+" | "-") product)*.
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("+", "-") {
+		if left, err = p.arithmetic(left, p.parseProduct); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+// parseProduct parses product := unary (("*" | "/" | "%") unary)*.
+func (p *parser) parseProduct() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isOp("*", "/", "%") {
+		if left, err = p.arithmetic(left, p.parseUnary); err != nil {
+			return nil, err
+		}
+	}
+	return left, nil
+}
+
+func (p *parser) arithmetic(left node, operand func() (node, error)) (node, error) {
+	op := p.tok
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	right, err := operand()
+	if err != nil {
+		return nil, err
+	}
+	for _, n := range []node{left, right} {
+		if err := p.checkType(n, Number, op.text); err != nil {
+			return nil, err
+		}
+	}
+	return &arithmeticNode{at: op.pos, op: op.text, left: left, right: right}, nil
+}
+
+// parseUnary parses unary := ("-" | "+") unary | primary.
+func (p *parser) parseUnary() (node, error) {
+	if !p.isOp("-", "+") {
+		return p.parsePrimary()
+	}
+	op := p.tok
+	if err := p.enter(); err != nil {
+		return nil, err
+	}
+	defer func() { p.depth-- }()
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	operand, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkType(operand, Number, op.text); err != nil {
+		return nil, err
+	}
+	if op.text == "+" {
+		return operand, nil
+	}
+	return &negateNode{at: op.pos, operand: operand}, nil
+}
+
+// parsePrimary parses a number, a factor, total, a function call, a parenthesized
+// expression or a conditional.
+func (p *parser) parsePrimary() (node, error) {
+	tok := p.tok
+	switch {
+	case tok.kind == tokNumber:
+		return &numberNode{at: tok.pos, value: tok.value}, p.next()
+	case tok.kind == tokQuoted:
+		mt, ok := metadata.GetByEnglishName(tok.text)
+		if !ok {
+			mt, ok = metadata.GetByKoreanName(tok.text)
+		}
+		if !ok {
+			return nil, p.errorf("알 수 없는 요소: %s", tok.text)
+		}
+		p.factors[mt] = true
+		return &factorNode{at: tok.pos, factor: mt}, p.next()
+	case tok.kind == tokIdent:
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		if p.tok.kind == tokLParen {
+			return p.parseCall(tok)
+		}
+		if strings.EqualFold(tok.text, "total") || tok.text == "총점" {
+			p.usesTotal = true
+			return &totalNode{at: tok.pos}, nil
+		}
+		mt, ok := metadata.GetByIdentifier(tok.text)
+		if !ok {
+			return nil, newError(p.source, tok.pos, "알 수 없는 요소: %s", tok.text)
+		}
+		p.factors[mt] = true
+		return &factorNode{at: tok.pos, factor: mt}, nil
+	case tok.kind == tokLParen:
+		if err := p.next(); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if p.tok.kind != tokRParen {
+			return nil, p.errorf("')'가 필요합니다 (%s)", p.tok)
+		}
+		return inner, p.next()
+	case p.isOp("if"):
+		return p.parseIf()
+	}
+	return nil, p.errorf("값이 필요합니다 (%s)", tok)
+}
+
+// parseIf parses "if" expr "then" expr ("else" expr)?. Without else, the branch must be a
+// number and the conditional is 0 when the condition is false.
+func (p *parser) parseIf() (node, error) {
+	at := p.tok.pos
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	cond, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.checkType(cond, Bool, "if"); err != nil {
+		return nil, err
+	}
+	if err := p.expect("then"); err != nil {
+		return nil, err
+	}
+	then, err := p.parseExpr()
+	if err != nil {
+		return nil, err
+	}
+	n := &ifNode{at: at, cond: cond, then: then}
+	if !p.isOp("else") {
+		if err := p.checkType(then, Number, "else 없는 if"); err != nil {
+			return nil, err
+		}
+		n.otherwise = &numberNode{at: p.tok.pos}
+		return n, nil
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	if n.otherwise, err = p.parseExpr(); err != nil {
+		return nil, err
+	}
+	if then.typ() != n.otherwise.typ() {
+		return nil, newError(p.source, n.otherwise.pos(), "then과 else의 타입이 다릅니다 (%s, %s)", then.typ(), n.otherwise.typ())
+	}
+	return n, nil
+}
+
+// functions maps each built-in function to its minimum and maximum argument count (-1: any).
+var functions = map[string][2]int{
+	"min":   {2, -1},
+	"max":   {2, -1},
+	"clamp": {3, 3},
+	"abs":   {1, 1},
+}
+
+// parseCall parses the arguments of a call to the function named by name; the current
+// token is the opening parenthesis.
+func (p *parser) parseCall(name token) (node, error) {
+	fn := strings.ToLower(name.text)
+	arity, ok := functions[fn]
+	if !ok {
+		return nil, newError(p.source, name.pos, "알 수 없는 함수: %s (사용 가능: min, max, clamp, abs)", name.text)
+	}
+	if err := p.next(); err != nil {
+		return nil, err
+	}
+	var args []node
+	for p.tok.kind != tokRParen {
+		if len(args) > 0 {
+			if p.tok.kind != tokComma {
+				return nil, p.errorf("',' 또는 ')'가 필요합니다 (%s)", p.tok)
+			}
+			if err := p.next(); err != nil {
+				return nil, err
+			}
+		}
+		arg, err := p.parseExpr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.checkType(arg, Number, fn+" 함수의 인자"); err != nil {
+			return nil, err
+		}
+		args = append(args, arg)
+	}
+	if len(args) < arity[0] || (arity[1] >= 0 && len(args) > arity[1]) {
+		if arity[0] == arity[1] {
+			return nil, newError(p.source, name.pos, "%s 함수는 인자 %d개가 필요합니다 (%d개)", fn, arity[0], len(args))
+		}
+		return nil, newError(p.source, name.pos, "%s 함수는 인자가 %d개 이상 필요합니다 (%d개)", fn, arity[0], len(args))
+	}
+	return &callNode{at: name.pos, fn: fn, args: args}, p.next()
+}
+
+type numberNode struct {
+	at    int
+	value float64
+}
+
+func (n *numberNode) typ() Type                        { return Number }
+func (n *numberNode) pos() int                         { return n.at }
+func (n *numberNode) eval(*Expr, Env) (float64, error) { return n.value, nil }
+
+type factorNode struct {
+	at     int
+	factor metadata.MetadataType
+}
+
+func (n *factorNode) typ() Type { return Number }
+func (n *factorNode) pos() int  { return n.at }
+func (n *factorNode) eval(_ *Expr, env Env) (float64, error) {
+	if env.Score == nil {
+		return math.NaN(), nil
+	}
+	if score, ok := env.Score(n.factor); ok {
+		return score, nil
+	}
+	return math.NaN(), nil
+}
+
+type totalNode struct{ at int }
+
+func (n *totalNode) typ() Type                              { return Number }
+func (n *totalNode) pos() int                               { return n.at }
+func (n *totalNode) eval(_ *Expr, env Env) (float64, error) { return env.Total, nil }
+
+type negateNode struct {
+	at      int
+	operand node
+}
+
+func (n *negateNode) typ() Type { return Number }
+func (n *negateNode) pos() int  { return n.at }
+func (n *negateNode) eval(e *Expr, env Env) (float64, error) {
+	v, err := n.operand.eval(e, env)
+	return -v, err
+}
+
+type arithmeticNode struct {
+	at          int
+	op          string
+	left, right node
+}
+
+func (n *arithmeticNode) typ() Type { return Number }
+func (n *arithmeticNode) pos() int  { return n.left.pos() }
+func (n *arithmeticNode) eval(e *Expr, env Env) (float64, error) {
+	left, err := n.left.eval(e, env)
+	if err != nil {
+		return 0, err
+	}
+	right, err := n.right.eval(e, env)
+	if err != nil {
+		return 0, err
+	}
+	switch n.op {
+	case "+":
+		return left + right, nil
+	case "-":
+		return left - right, nil
+	case "*":
+		return left * right, nil
+	}
+	if right == 0 {
+		return 0, newError(e.source, n.at, "0으로 나눌 수 없습니다")
+	}
+	if n.op == "/" {
+		return left / right, nil
+	}
+	return math.Mod(left, right), nil
+}
+
+type compareNode struct {
+	at          int
+	op          string
+	left, right node
+}
+
+func (n *compareNode) typ() Type { return Bool }
+func (n *compareNode) pos() int  { return n.left.pos() }
+func (n *compareNode) eval(e *Expr, env Env) (float64, error) {
+	left, err := n.left.eval(e, env)
+	if err != nil {
+		return 0, err
+	}
+	right, err := n.right.eval(e, env)
+	if err != nil {
+		return 0, err
+	}
+	if isMissing(left) || isMissing(right) {
+		return 0, nil // 데이터가 없는 요소와의 비교는 항상 거짓
+	}
+	switch n.op {
+	case "<":
+		return truth(left < right), nil
+	case "<=":
+		return truth(left <= right), nil
+	case ">":
+		return truth(left > right), nil
+	case ">=":
+		return truth(left >= right), nil
+	case "==":
+		return truth(left == right), nil
+	default:
+		return truth(left != right), nil
+	}
+}
+
+type logicalNode struct {
+	at          int
+	or          bool
+	left, right node
+}
+
+func (n *logicalNode) typ() Type { return Bool }
+func (n *logicalNode) pos() int  { return n.left.pos() }
+func (n *logicalNode) eval(e *Expr, env Env) (float64, error) {
+	left, err := n.left.eval(e, env)
+	if err != nil || (left != 0) == n.or {
+		return left, err // 단락 평가
+	}
+	return n.right.eval(e, env)
+}
+
+type notNode struct {
+	at      int
+	operand node
+}
+
+func (n *notNode) typ() Type { return Bool }
+func (n *notNode) pos() int  { return n.at }
+func (n *notNode) eval(e *Expr, env Env) (float64, error) {
+	v, err := n.operand.eval(e, env)
+	return truth(v == 0), err
+}
+
+type ifNode struct {
+	at                    int
+	cond, then, otherwise node
+}
+
+func (n *ifNode) typ() Type { return n.then.typ() }
+func (n *ifNode) pos() int  { return n.at }
+func (n *ifNode) eval(e *Expr, env Env) (float64, error) {
+	cond, err := n.cond.eval(e, env)
+	if err != nil {
+		return 0, err
+	}
+	if cond != 0 {
+		return n.then.eval(e, env)
+	}
+	return n.otherwise.eval(e, env)
+}
+
+type callNode struct {
+	at   int
+	fn   string
+	args []node
+}
+
+func (n *callNode) typ() Type { return Number }
+func (n *callNode) pos() int  { return n.at }
+func (n *callNode) eval(e *Expr, env Env) (float64, error) {
+	args := make([]float64, len(n.args))
+	for i, arg := range n.args {
+		v, err := arg.eval(e, env)
+		if err != nil {
+			return 0, err
+		}
+		if isMissing(v) {
+			return v, nil
+		}
+		args[i] = v
+	}
+	switch n.fn {
+	case "abs":
+		return math.Abs(args[0]), nil
+	case "clamp":
+		if args[1] > args[2] {
+			return 0, newError(e.source, n.at, "clamp의 하한(%g)이 상한(%g)보다 큽니다", args[1], args[2])
+		}
+		return math.Max(args[1], math.Min(args[2], args[0])), nil
+	}
+	result := args[0]
+	for _, v := range args[1:] {
+		if (n.fn == "min") == (v < result) {
+			result = v
+		}
+	}
+	return result, nil
+}
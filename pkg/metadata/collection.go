@@ -1,7 +1,7 @@
 // Package metadata provides apartment scoring metadata types and utilities.
 package metadata
 
-// AllMetadataTypes returns all metadata types as an array.
+// AllMetadataTypes returns the built-in metadata types as an array. Use All to include custom types.
 func AllMetadataTypes() [MetadataTypeCount]MetadataType {
 	var types [MetadataTypeCount]MetadataType
 	for i := MetadataType(0); i < MetadataTypeCount; i++ {
@@ -9,3 +9,13 @@ func AllMetadataTypes() [MetadataTypeCount]MetadataType {
 	}
 	return types
 }
+
+// All returns every registered metadata type in index order, built-in types first.
+func All() []MetadataType {
+	n := Count()
+	types := make([]MetadataType, n)
+	for i := range types {
+		types[i] = MetadataType(i)
+	}
+	return types
+}
@@ -5,9 +5,11 @@ type metadataInfo struct {
 	koreanName  string
 	description string
 	factorType  FactorType
+	// defaultWeight is only used by custom types registered at runtime.
+	defaultWeight float64
 }
 
-var metadataInfos = [MetadataTypeCount]metadataInfo{
+var builtinMetadataInfos = [MetadataTypeCount]metadataInfo{
 	{
 		englishName: "Floor Level",
 		koreanName:  "층수",
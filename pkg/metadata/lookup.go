@@ -1,5 +1,10 @@
 package metadata
 
+import (
+	"strings"
+	"unicode"
+)
+
 // GetByIndex returns the metadata type for the given index.
 func GetByIndex(index int) (MetadataType, bool) {
 	mt := MetadataType(index)
@@ -11,9 +16,11 @@ func GetByIndex(index int) (MetadataType, bool) {
 
 // GetByEnglishName returns the metadata type for the given English name.
 func GetByEnglishName(englishName string) (MetadataType, bool) {
-	for i := MetadataType(0); i < MetadataTypeCount; i++ {
-		if metadataInfos[i].englishName == englishName {
-			return i, true
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for i, info := range metadataInfos {
+		if info.englishName == englishName {
+			return MetadataType(i), true
 		}
 	}
 	return MetadataType(-1), false
@@ -21,10 +28,40 @@ func GetByEnglishName(englishName string) (MetadataType, bool) {
 
 // GetByKoreanName returns the metadata type for the given Korean name.
 func GetByKoreanName(koreanName string) (MetadataType, bool) {
-	for i := MetadataType(0); i < MetadataTypeCount; i++ {
-		if metadataInfos[i].koreanName == koreanName {
-			return i, true
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for i, info := range metadataInfos {
+		if info.koreanName == koreanName {
+			return MetadataType(i), true
+		}
+	}
+	return MetadataType(-1), false
+}
+
+// GetByIdentifier returns the metadata type whose English or Korean name matches the identifier
+// when case, spaces and underscores are ignored, so "ApartmentSize", "apartment_size" and
+// "아파트크기" all name Apartment Size.
+func GetByIdentifier(identifier string) (MetadataType, bool) {
+	key := identifierKey(identifier)
+	if key == "" {
+		return MetadataType(-1), false
+	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	for i, info := range metadataInfos {
+		if identifierKey(info.englishName) == key || identifierKey(info.koreanName) == key {
+			return MetadataType(i), true
 		}
 	}
 	return MetadataType(-1), false
 }
+
+// identifierKey folds a name for GetByIdentifier.
+func identifierKey(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == '_' || unicode.IsSpace(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
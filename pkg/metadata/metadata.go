@@ -9,45 +9,45 @@ func (mt MetadataType) Index() int {
 
 // String returns the English name of the metadata type.
 func (mt MetadataType) String() string {
-	if mt.IsValid() {
-		return metadataInfos[mt].englishName
+	if info, ok := lookupInfo(mt); ok {
+		return info.englishName
 	}
 	return fmt.Sprintf("Unknown(%d)", int(mt))
 }
 
 // KoreanName returns the Korean name of the metadata type.
 func (mt MetadataType) KoreanName() string {
-	if mt.IsValid() {
-		return metadataInfos[mt].koreanName
+	if info, ok := lookupInfo(mt); ok {
+		return info.koreanName
 	}
 	return fmt.Sprintf("알 수 없음(%d)", int(mt))
 }
 
 // Description returns the description of the metadata type.
 func (mt MetadataType) Description() string {
-	if mt.IsValid() {
-		return metadataInfos[mt].description
+	if info, ok := lookupInfo(mt); ok {
+		return info.description
 	}
 	return fmt.Sprintf("설명 없음(%d)", int(mt))
 }
 
-// IsValid checks if the metadata type is valid.
+// IsValid checks if the metadata type is a built-in or registered type.
 func (mt MetadataType) IsValid() bool {
-	return mt >= 0 && mt < MetadataTypeCount
+	return mt >= 0 && int(mt) < Count()
 }
 
 // FactorType returns the factor type (internal/external) of the metadata.
 func (mt MetadataType) FactorType() FactorType {
-	if mt.IsValid() {
-		return metadataInfos[mt].factorType
+	if info, ok := lookupInfo(mt); ok {
+		return info.factorType
 	}
 	return FactorInternal
 }
 
-// GetDefaultFactorTypes returns the default factor types for all metadata.
+// GetDefaultFactorTypes returns the default factor types for all registered metadata.
 func GetDefaultFactorTypes() map[MetadataType]FactorType {
 	result := make(map[MetadataType]FactorType)
-	for mt := MetadataType(0); mt < MetadataTypeCount; mt++ {
+	for _, mt := range All() {
 		result[mt] = mt.FactorType()
 	}
 	return result
@@ -61,11 +61,14 @@ func SetFactorType(mt MetadataType, factorType FactorType) error {
 	if factorType != FactorInternal && factorType != FactorExternal {
 		return fmt.Errorf("유효하지 않은 팩터 타입: %s", factorType)
 	}
+	registryMu.Lock()
+	defer registryMu.Unlock()
 	metadataInfos[mt].factorType = factorType
 	return nil
 }
 
-// GetMetadataByFactorType returns all metadata types of the specified factor type.
+// GetMetadataByFactorType returns the built-in metadata types of the specified factor type,
+// packed at the front of the array. Use ByFactorType to include custom types.
 func GetMetadataByFactorType(factorType FactorType) [MetadataTypeCount]MetadataType {
 	var result [MetadataTypeCount]MetadataType
 	index := 0
@@ -77,3 +80,14 @@ func GetMetadataByFactorType(factorType FactorType) [MetadataTypeCount]MetadataT
 	}
 	return result
 }
+
+// ByFactorType returns all registered metadata types of the specified factor type.
+func ByFactorType(factorType FactorType) []MetadataType {
+	var result []MetadataType
+	for _, mt := range All() {
+		if mt.FactorType() == factorType {
+			result = append(result, mt)
+		}
+	}
+	return result
+}
@@ -291,6 +291,27 @@ func TestGetByKoreanName(t *testing.T) {
 	}
 }
 
+func TestGetByIdentifier(t *testing.T) {
+	tests := map[string]MetadataType{
+		"ApartmentSize":       ApartmentSize,
+		"apartment_size":      ApartmentSize,
+		"Distance to Station": DistanceToStation,
+		"역까지거리":               DistanceToStation,
+		"역까지_거리":              DistanceToStation,
+		"학군":                  SchoolDistrict,
+	}
+	for identifier, expected := range tests {
+		if got, ok := GetByIdentifier(identifier); !ok || got != expected {
+			t.Errorf("GetByIdentifier(%s) = %v, %v, expected %v, true", identifier, got, ok, expected)
+		}
+	}
+	for _, identifier := range []string{"", "_", "Apartment", "잘못된이름"} {
+		if got, ok := GetByIdentifier(identifier); ok {
+			t.Errorf("GetByIdentifier(%q) expected no match, but got %v", identifier, got)
+		}
+	}
+}
+
 func TestAllMetadataTypes(t *testing.T) {
 	allTypes := AllMetadataTypes()
 
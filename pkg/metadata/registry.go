@@ -0,0 +1,101 @@
+package metadata
+
+import (
+	"apart_score/internal/metadatatest"
+	"fmt"
+	"sync"
+)
+
+// MetadataDefinition describes a custom metadata type registered at runtime.
+type MetadataDefinition struct {
+	EnglishName   string     // 영문 이름 (고유)
+	KoreanName    string     // 한글 이름 (고유)
+	Description   string     // 설명
+	FactorType    FactorType // 내부/외부 요인
+	DefaultWeight float64    // 시나리오 가중치에 추가될 기본 가중치 (0.0-1.0, 0이면 추가하지 않음)
+}
+
+var (
+	registryMu    sync.RWMutex
+	metadataInfos = newBuiltinInfos()
+)
+
+func newBuiltinInfos() []metadataInfo {
+	infos := make([]metadataInfo, MetadataTypeCount, MaxMetadataTypes)
+	copy(infos, builtinMetadataInfos[:])
+	return infos
+}
+
+func init() {
+	metadatatest.Reset = resetRegistry
+}
+
+// resetRegistry removes every registered custom metadata type so tests can restore the registry.
+func resetRegistry() {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	metadataInfos = newBuiltinInfos()
+}
+
+// Register adds a custom metadata type after the built-in types and returns its index.
+// Registered types take part in scoring, validation, scenarios and the dashboard like built-in types.
+func Register(def MetadataDefinition) (MetadataType, error) {
+	if def.EnglishName == "" || def.KoreanName == "" {
+		return MetadataType(-1), fmt.Errorf("메타데이터 이름(영문/한글)은 필수입니다")
+	}
+	if def.FactorType != FactorInternal && def.FactorType != FactorExternal {
+		return MetadataType(-1), fmt.Errorf("유효하지 않은 팩터 타입: %s", def.FactorType)
+	}
+	if def.DefaultWeight < 0 || def.DefaultWeight > 1 {
+		return MetadataType(-1), fmt.Errorf("기본 가중치는 0.0에서 1.0 사이여야 합니다 (%.3f)", def.DefaultWeight)
+	}
+
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	if len(metadataInfos) >= MaxMetadataTypes {
+		return MetadataType(-1), fmt.Errorf("등록 가능한 메타데이터 수(%d)를 초과했습니다", MaxMetadataTypes)
+	}
+	for _, info := range metadataInfos {
+		if info.englishName == def.EnglishName || info.koreanName == def.KoreanName {
+			return MetadataType(-1), fmt.Errorf("이미 등록된 메타데이터 이름: %s / %s", def.EnglishName, def.KoreanName)
+		}
+	}
+	metadataInfos = append(metadataInfos, metadataInfo{
+		englishName:   def.EnglishName,
+		koreanName:    def.KoreanName,
+		description:   def.Description,
+		factorType:    def.FactorType,
+		defaultWeight: def.DefaultWeight,
+	})
+	return MetadataType(len(metadataInfos) - 1), nil
+}
+
+// Count returns the number of registered metadata types, built-in types included.
+func Count() int {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return len(metadataInfos)
+}
+
+// IsBuiltin reports whether the metadata type is one of the built-in types.
+func (mt MetadataType) IsBuiltin() bool {
+	return mt >= 0 && mt < MetadataTypeCount
+}
+
+// DefaultWeight returns the default scenario weight of a custom metadata type (0 for built-in types).
+func (mt MetadataType) DefaultWeight() float64 {
+	info, ok := lookupInfo(mt)
+	if !ok {
+		return 0
+	}
+	return info.defaultWeight
+}
+
+func lookupInfo(mt MetadataType) (metadataInfo, bool) {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	if mt < 0 || int(mt) >= len(metadataInfos) {
+		return metadataInfo{}, false
+	}
+	return metadataInfos[mt], true
+}
@@ -0,0 +1,78 @@
+package metadata
+
+import "testing"
+
+func TestRegister(t *testing.T) {
+	t.Cleanup(resetRegistry)
+
+	mt, err := Register(MetadataDefinition{
+		EnglishName:   "Sunlight Orientation",
+		KoreanName:    "채광 방향",
+		Description:   "남향일수록 높은 점수",
+		FactorType:    FactorInternal,
+		DefaultWeight: 0.05,
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	if mt.Index() != int(MetadataTypeCount) {
+		t.Errorf("First custom type should follow built-in types, got index %d", mt.Index())
+	}
+	if !mt.IsValid() || mt.IsBuiltin() {
+		t.Error("Registered type should be valid and not built-in")
+	}
+	if mt.String() != "Sunlight Orientation" || mt.KoreanName() != "채광 방향" {
+		t.Errorf("Unexpected names: %s / %s", mt.String(), mt.KoreanName())
+	}
+	if mt.DefaultWeight() != 0.05 {
+		t.Errorf("Expected default weight 0.05, got %v", mt.DefaultWeight())
+	}
+	if Count() != int(MetadataTypeCount)+1 || len(All()) != Count() {
+		t.Errorf("Count/All should include custom type, got %d/%d", Count(), len(All()))
+	}
+
+	if got, ok := GetByKoreanName("채광 방향"); !ok || got != mt {
+		t.Errorf("GetByKoreanName should find custom type, got %v, %v", got, ok)
+	}
+	if got, ok := GetByEnglishName("Sunlight Orientation"); !ok || got != mt {
+		t.Errorf("GetByEnglishName should find custom type, got %v, %v", got, ok)
+	}
+
+	found := false
+	for _, internal := range ByFactorType(FactorInternal) {
+		if internal == mt {
+			found = true
+		}
+	}
+	if !found {
+		t.Error("Custom internal type should be listed in internal factors")
+	}
+	for _, builtin := range GetMetadataByFactorType(FactorInternal) {
+		if builtin == mt {
+			t.Error("GetMetadataByFactorType should list built-in types only")
+		}
+	}
+}
+
+func TestRegister_Invalid(t *testing.T) {
+	t.Cleanup(resetRegistry)
+
+	tests := []struct {
+		name string
+		def  MetadataDefinition
+	}{
+		{"Missing name", MetadataDefinition{EnglishName: "Noise", FactorType: FactorExternal}},
+		{"Duplicate built-in name", MetadataDefinition{EnglishName: "Parking", KoreanName: "주차", FactorType: FactorInternal}},
+		{"Invalid factor type", MetadataDefinition{EnglishName: "Noise", KoreanName: "소음", FactorType: FactorType("other")}},
+		{"Invalid default weight", MetadataDefinition{EnglishName: "Noise", KoreanName: "소음", FactorType: FactorExternal, DefaultWeight: 1.5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Register(tt.def); err == nil {
+				t.Errorf("Register should fail for %s", tt.name)
+			}
+		})
+	}
+}
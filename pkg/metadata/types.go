@@ -47,7 +47,12 @@ type FactorDefinition struct {
 	Characteristics []string // 주요 특징
 }
 
-// Metadata types for apartment scoring
+// MaxMetadataTypes is the capacity of the fixed-size score and weight arrays,
+// including built-in and runtime-registered metadata types.
+const MaxMetadataTypes = 32
+
+// Metadata types for apartment scoring.
+// MetadataTypeCount is the number of built-in types; custom types registered with Register follow it.
 const (
 	// FloorLevel represents the floor level of the apartment
 	FloorLevel MetadataType = iota
@@ -0,0 +1,52 @@
+// Package normalization converts raw apartment facts into 0-100 ScoreValues.
+package normalization
+
+import (
+	"fmt"
+	"sort"
+)
+
+// CurvePoint is a control point mapping a raw measurement to a 0-100 score.
+type CurvePoint struct {
+	Input float64 `json:"input"`
+	Score float64 `json:"score"`
+}
+
+// Curve maps raw measurements to scores by piecewise-linear interpolation.
+// Inputs below the first point or above the last point are clamped to the end scores.
+type Curve []CurvePoint
+
+// Validate checks that the curve has points in strictly ascending input order with scores in 0-100.
+func (c Curve) Validate() error {
+	if len(c) == 0 {
+		return fmt.Errorf("곡선에 제어점이 없습니다")
+	}
+	for i, p := range c {
+		if p.Score < 0 || p.Score > 100 {
+			return fmt.Errorf("곡선 점수가 범위를 벗어남 (%d번째 점: %.1f)", i, p.Score)
+		}
+		if i > 0 && p.Input <= c[i-1].Input {
+			return fmt.Errorf("곡선 입력값은 오름차순이어야 합니다 (%d번째 점: %.1f)", i, p.Input)
+		}
+	}
+	return nil
+}
+
+// Evaluate returns the interpolated score for the raw input.
+func (c Curve) Evaluate(x float64) float64 {
+	if len(c) == 0 {
+		return 0
+	}
+	if x <= c[0].Input {
+		return c[0].Score
+	}
+	last := c[len(c)-1]
+	if x >= last.Input {
+		return last.Score
+	}
+	// x보다 큰 첫 제어점을 찾아 직전 점과 선형 보간
+	i := sort.Search(len(c), func(i int) bool { return c[i].Input > x })
+	lo, hi := c[i-1], c[i]
+	ratio := (x - lo.Input) / (hi.Input - lo.Input)
+	return lo.Score + ratio*(hi.Score-lo.Score)
+}
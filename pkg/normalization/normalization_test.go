@@ -0,0 +1,132 @@
+package normalization
+
+import (
+	"testing"
+
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+)
+
+func TestCurveEvaluate(t *testing.T) {
+	curve := Curve{{0, 100}, {10, 50}, {20, 0}}
+
+	tests := []struct {
+		input    float64
+		expected float64
+	}{
+		{-5, 100},
+		{0, 100},
+		{5, 75},
+		{10, 50},
+		{15, 25},
+		{30, 0},
+	}
+
+	for _, tt := range tests {
+		if got := curve.Evaluate(tt.input); got != tt.expected {
+			t.Errorf("Evaluate(%v) = %v, expected %v", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestCurveValidate(t *testing.T) {
+	if err := DefaultStationDistanceCurve.Validate(); err != nil {
+		t.Errorf("Default curve should be valid: %v", err)
+	}
+	if err := (Curve{{10, 50}, {5, 60}}).Validate(); err == nil {
+		t.Error("Descending inputs should fail validation")
+	}
+	if err := (Curve{{0, 120}}).Validate(); err == nil {
+		t.Error("Score above 100 should fail validation")
+	}
+}
+
+func TestNormalizers_RejectInvalidCurves(t *testing.T) {
+	for name, curve := range map[string]Curve{"empty": nil, "unsorted": {{10, 50}, {5, 60}}} {
+		if _, err := NewCurveNormalizer(curve); err == nil {
+			t.Errorf("NewCurveNormalizer(%s) should fail", name)
+		}
+		if _, err := NewFloorNormalizer(0.5, curve); err == nil {
+			t.Errorf("NewFloorNormalizer(%s) should fail", name)
+		}
+		if _, err := NewConstructionYearNormalizer(2025, curve); err == nil {
+			t.Errorf("NewConstructionYearNormalizer(%s) should fail", name)
+		}
+		// 생성자 없이 만든 정규화기도 0점 대신 오류
+		for _, n := range []Normalizer{
+			CurveNormalizer{Curve: curve},
+			FloorNormalizer{PreferredPosition: 0.5, Curve: curve},
+			ConstructionYearNormalizer{ReferenceYear: 2025, Curve: curve},
+		} {
+			if _, err := n.Normalize(RawValue{Value: 1, Reference: 10}); err == nil {
+				t.Errorf("%T with %s curve should fail to normalize", n, name)
+			}
+		}
+	}
+	if _, err := NewFloorNormalizer(1.5, DefaultFloorCurve); err == nil {
+		t.Error("Preferred position above 1 should fail")
+	}
+	if n, err := NewFloorNormalizer(0.5, DefaultFloorCurve); err != nil || n.Curve == nil {
+		t.Errorf("NewFloorNormalizer with the default curve: %+v, %v", n, err)
+	}
+}
+
+func TestFloorNormalizer_PrefersMiddleFloors(t *testing.T) {
+	n := DefaultNormalizers()[metadata.FloorLevel]
+
+	middle, err := n.Normalize(RawValue{Value: 13, Reference: 25})
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+	top, _ := n.Normalize(RawValue{Value: 25, Reference: 25})
+	bottom, _ := n.Normalize(RawValue{Value: 1, Reference: 25})
+
+	if middle != shared.ScoreValueFromFloat(100) {
+		t.Errorf("Middle floor should score 100, got %.1f", middle.ToFloat())
+	}
+	if top >= middle || bottom >= middle {
+		t.Errorf("Extreme floors should score lower than middle: top %.1f, bottom %.1f", top.ToFloat(), bottom.ToFloat())
+	}
+
+	if _, err := n.Normalize(RawValue{Value: 30, Reference: 25}); err == nil {
+		t.Error("Floor above total floors should fail")
+	}
+}
+
+func TestNormalizers_Normalize(t *testing.T) {
+	facts := Facts{}
+	facts.SetFloor(8, 15)
+	facts.SetStationDistance(350)
+	facts.SetElevator(true)
+	facts.SetConstructionYear(2015)
+	facts.SetApartmentSize(84)
+	facts.SetMaintenanceFee(250000)
+
+	normalizers := DefaultNormalizers()
+	normalizers[metadata.ConstructionYear] = ConstructionYearNormalizer{ReferenceYear: 2025, Curve: DefaultBuildingAgeCurve}
+
+	scores, err := normalizers.Normalize(facts)
+	if err != nil {
+		t.Fatalf("Normalize failed: %v", err)
+	}
+
+	if len(scores) != len(facts) {
+		t.Errorf("Expected %d scores, got %d", len(facts), len(scores))
+	}
+	if got := scores[metadata.ConstructionYear]; got != shared.ScoreValueFromFloat(85) {
+		t.Errorf("10-year-old building should score 85, got %.1f", got.ToFloat())
+	}
+	if got := scores[metadata.DistanceToStation]; got != shared.ScoreValueFromFloat(95) {
+		t.Errorf("350m to station should score 95, got %.1f", got.ToFloat())
+	}
+	for mt, score := range scores {
+		if score < 0 || score > shared.ScoreValueFromFloat(100) {
+			t.Errorf("%s score out of range: %.1f", mt.String(), score.ToFloat())
+		}
+	}
+
+	facts[metadata.SchoolDistrict] = RawValue{Value: 1}
+	if _, err := normalizers.Normalize(facts); err == nil {
+		t.Error("Facts without a normalizer should fail")
+	}
+}
@@ -0,0 +1,219 @@
+package normalization
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"fmt"
+	"math"
+	"time"
+)
+
+// RawValue holds a measured fact for one factor.
+type RawValue struct {
+	Value     float64 // 측정값 (층, 미터, 연도, 원, ㎡ 등)
+	Reference float64 // 보조값 (예: 전체 층수), 필요 없는 경우 0
+}
+
+// Normalizer converts a raw fact into a 0-100 ScoreValue.
+type Normalizer interface {
+	Normalize(raw RawValue) (shared.ScoreValue, error)
+}
+
+// CurveNormalizer scores the raw value directly through a curve.
+type CurveNormalizer struct {
+	Curve Curve
+}
+
+// NewCurveNormalizer returns a CurveNormalizer after validating the curve.
+func NewCurveNormalizer(curve Curve) (CurveNormalizer, error) {
+	if err := curve.Validate(); err != nil {
+		return CurveNormalizer{}, err
+	}
+	return CurveNormalizer{Curve: curve}, nil
+}
+
+// Normalize implements Normalizer. An invalid curve is an error rather than a score of 0.
+func (n CurveNormalizer) Normalize(raw RawValue) (shared.ScoreValue, error) {
+	if err := n.Curve.Validate(); err != nil {
+		return 0, err
+	}
+	if raw.Value < 0 {
+		return 0, fmt.Errorf("음수 측정값은 허용되지 않습니다 (%.1f)", raw.Value)
+	}
+	return toScore(n.Curve.Evaluate(raw.Value)), nil
+}
+
+// FloorNormalizer scores a floor by how far its relative position is from the preferred position.
+// RawValue.Value is the floor number and RawValue.Reference is the total floor count.
+type FloorNormalizer struct {
+	PreferredPosition float64 // 선호 위치 (0 = 최저층, 1 = 최고층)
+	Curve             Curve   // 선호 위치와의 거리(0-1) → 점수
+}
+
+// NewFloorNormalizer returns a FloorNormalizer after validating the preferred position and curve.
+func NewFloorNormalizer(preferredPosition float64, curve Curve) (FloorNormalizer, error) {
+	n := FloorNormalizer{PreferredPosition: preferredPosition, Curve: curve}
+	if err := n.validate(); err != nil {
+		return FloorNormalizer{}, err
+	}
+	return n, nil
+}
+
+func (n FloorNormalizer) validate() error {
+	if n.PreferredPosition < 0 || n.PreferredPosition > 1 {
+		return fmt.Errorf("선호 위치는 0에서 1 사이여야 합니다 (%.2f)", n.PreferredPosition)
+	}
+	return n.Curve.Validate()
+}
+
+// Normalize implements Normalizer.
+func (n FloorNormalizer) Normalize(raw RawValue) (shared.ScoreValue, error) {
+	if err := n.validate(); err != nil {
+		return 0, err
+	}
+	floor, total := raw.Value, raw.Reference
+	if total < 1 {
+		return 0, fmt.Errorf("전체 층수가 필요합니다 (%.0f)", total)
+	}
+	if floor < 1 || floor > total {
+		return 0, fmt.Errorf("층수가 범위를 벗어남 (%.0f/%.0f층)", floor, total)
+	}
+	position := 0.5
+	if total > 1 {
+		position = (floor - 1) / (total - 1)
+	}
+	deviation := math.Abs(position - n.PreferredPosition)
+	return toScore(n.Curve.Evaluate(deviation)), nil
+}
+
+// ConstructionYearNormalizer scores a build year by the building's age.
+type ConstructionYearNormalizer struct {
+	ReferenceYear int   // 기준 연도, 0이면 현재 연도
+	Curve         Curve // 건물 연령(년) → 점수
+}
+
+// NewConstructionYearNormalizer returns a ConstructionYearNormalizer after validating the curve.
+func NewConstructionYearNormalizer(referenceYear int, curve Curve) (ConstructionYearNormalizer, error) {
+	if err := curve.Validate(); err != nil {
+		return ConstructionYearNormalizer{}, err
+	}
+	return ConstructionYearNormalizer{ReferenceYear: referenceYear, Curve: curve}, nil
+}
+
+// Normalize implements Normalizer.
+func (n ConstructionYearNormalizer) Normalize(raw RawValue) (shared.ScoreValue, error) {
+	if err := n.Curve.Validate(); err != nil {
+		return 0, err
+	}
+	reference := n.ReferenceYear
+	if reference == 0 {
+		reference = time.Now().Year()
+	}
+	age := float64(reference) - raw.Value
+	if age < 0 {
+		return 0, fmt.Errorf("건축년도가 기준 연도보다 늦습니다 (%.0f > %d)", raw.Value, reference)
+	}
+	return toScore(n.Curve.Evaluate(age)), nil
+}
+
+// BooleanNormalizer scores a yes/no fact. RawValue.Value is non-zero for yes.
+type BooleanNormalizer struct {
+	TrueScore  float64
+	FalseScore float64
+}
+
+// Normalize implements Normalizer.
+func (n BooleanNormalizer) Normalize(raw RawValue) (shared.ScoreValue, error) {
+	if raw.Value != 0 {
+		return toScore(n.TrueScore), nil
+	}
+	return toScore(n.FalseScore), nil
+}
+
+// Default curves used by DefaultNormalizers.
+var (
+	// DefaultFloorCurve favours middle floors and penalises the lowest and highest floors.
+	DefaultFloorCurve = Curve{{0, 100}, {0.15, 95}, {0.3, 80}, {0.4, 65}, {0.5, 50}}
+	// DefaultStationDistanceCurve maps walking metres to the station to a score.
+	DefaultStationDistanceCurve = Curve{{200, 100}, {500, 90}, {800, 75}, {1200, 55}, {2000, 25}, {3000, 0}}
+	// DefaultBuildingAgeCurve maps building age in years to a score.
+	DefaultBuildingAgeCurve = Curve{{0, 100}, {5, 95}, {10, 85}, {20, 65}, {30, 40}, {40, 20}, {50, 0}}
+	// DefaultMaintenanceFeeCurve maps monthly maintenance fees in KRW to a score.
+	DefaultMaintenanceFeeCurve = Curve{{100000, 100}, {200000, 85}, {300000, 65}, {400000, 45}, {600000, 15}, {800000, 0}}
+	// DefaultApartmentSizeCurve maps exclusive area in ㎡ to a score, peaking around 85-135㎡.
+	DefaultApartmentSizeCurve = Curve{{20, 20}, {40, 50}, {60, 75}, {85, 100}, {135, 100}, {200, 80}}
+)
+
+// Normalizers maps each metadata type to the normalizer that scores its raw facts.
+type Normalizers map[metadata.MetadataType]Normalizer
+
+// DefaultNormalizers returns normalizers with the default curves for the factors that have measurable facts.
+func DefaultNormalizers() Normalizers {
+	return Normalizers{
+		metadata.FloorLevel:        FloorNormalizer{PreferredPosition: 0.5, Curve: DefaultFloorCurve},
+		metadata.DistanceToStation: CurveNormalizer{Curve: DefaultStationDistanceCurve},
+		metadata.ElevatorPresence:  BooleanNormalizer{TrueScore: 100, FalseScore: 0},
+		metadata.ConstructionYear:  ConstructionYearNormalizer{Curve: DefaultBuildingAgeCurve},
+		metadata.ApartmentSize:     CurveNormalizer{Curve: DefaultApartmentSizeCurve},
+		metadata.MaintenanceFee:    CurveNormalizer{Curve: DefaultMaintenanceFeeCurve},
+	}
+}
+
+// Normalize converts every fact into a score. The result only contains factors present in facts,
+// so it can be merged with hand-scored factors before calling scoring.CalculateWithStrategy.
+func (n Normalizers) Normalize(facts Facts) (map[metadata.MetadataType]shared.ScoreValue, error) {
+	scores := make(map[metadata.MetadataType]shared.ScoreValue, len(facts))
+	for mt, raw := range facts {
+		normalizer, exists := n[mt]
+		if !exists {
+			return nil, fmt.Errorf("정규화기가 등록되지 않은 요소: %s", mt.String())
+		}
+		score, err := normalizer.Normalize(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%s 정규화 실패: %w", mt.String(), err)
+		}
+		scores[mt] = score
+	}
+	return scores, nil
+}
+
+// Facts collects raw apartment facts keyed by metadata type.
+type Facts map[metadata.MetadataType]RawValue
+
+// SetFloor records the floor number and the building's total floor count.
+func (f Facts) SetFloor(floor, totalFloors int) {
+	f[metadata.FloorLevel] = RawValue{Value: float64(floor), Reference: float64(totalFloors)}
+}
+
+// SetStationDistance records the walking distance to the nearest station in metres.
+func (f Facts) SetStationDistance(meters float64) {
+	f[metadata.DistanceToStation] = RawValue{Value: meters}
+}
+
+// SetElevator records whether the building has an elevator.
+func (f Facts) SetElevator(present bool) {
+	value := 0.0
+	if present {
+		value = 1
+	}
+	f[metadata.ElevatorPresence] = RawValue{Value: value}
+}
+
+// SetConstructionYear records the year the building was completed.
+func (f Facts) SetConstructionYear(year int) {
+	f[metadata.ConstructionYear] = RawValue{Value: float64(year)}
+}
+
+// SetApartmentSize records the exclusive area in square metres.
+func (f Facts) SetApartmentSize(squareMeters float64) {
+	f[metadata.ApartmentSize] = RawValue{Value: squareMeters}
+}
+
+// SetMaintenanceFee records the monthly maintenance fee in KRW.
+func (f Facts) SetMaintenanceFee(krw int64) {
+	f[metadata.MaintenanceFee] = RawValue{Value: float64(krw)}
+}
+
+func toScore(score float64) shared.ScoreValue {
+	return shared.ScoreValueFromFloat(math.Max(0, math.Min(100, score)))
+}
@@ -0,0 +1,193 @@
+// Package profile persists named scoring profiles as versioned JSON documents and migrates
+// documents written by older versions on load.
+package profile
+
+import (
+	"apart_score/pkg/codec"
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/scoring"
+	"apart_score/pkg/shared"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// SchemaVersion is the document version written by this package.
+//
+//   - 1: scoring.ScoringProfile marshaled as is (Go field names, weights as per-mille
+//     integers keyed by metadata type number); no schema_version field.
+//   - 2: snake_case fields, weights as fractions keyed by factor name, constraints and
+//     optional value functions keyed by factor name.
+const SchemaVersion = 2
+
+// Document is the on-disk representation of a scoring.ScoringProfile.
+type Document struct {
+	SchemaVersion  int                        `json:"schema_version"`
+	Name           string                     `json:"name"`
+	Description    string                     `json:"description,omitempty"`
+	Strategy       scoring.StrategyType       `json:"strategy,omitempty"`
+	Scenario       scoring.ScoringScenario    `json:"scenario,omitempty"`
+	Weights        codec.FactorWeights        `json:"weights,omitempty"`
+	Constraints    *codec.ConstraintsRecord   `json:"constraints,omitempty"`
+	ValueFunctions codec.ValueFunctionsRecord `json:"value_functions,omitempty"`
+}
+
+// NewDocument converts a profile to the current document version.
+func NewDocument(p scoring.ScoringProfile) Document {
+	doc := Document{
+		SchemaVersion: SchemaVersion,
+		Name:          p.Name,
+		Description:   p.Description,
+		Strategy:      p.Method,
+		Scenario:      p.Scenario,
+	}
+	if len(p.Weights) > 0 {
+		doc.Weights = codec.NewFactorWeights(p.Weights)
+	}
+	if !p.Constraints.IsEmpty() {
+		record := codec.NewConstraintsRecord(p.Constraints)
+		doc.Constraints = &record
+	}
+	if len(p.ValueFunctions) > 0 {
+		doc.ValueFunctions = codec.NewValueFunctionsRecord(p.ValueFunctions)
+	}
+	return doc
+}
+
+// Profile resolves factor names and validates the profile.
+func (d Document) Profile() (scoring.ScoringProfile, error) {
+	p := scoring.ScoringProfile{
+		Name:        d.Name,
+		Description: d.Description,
+		Method:      d.Strategy,
+		Scenario:    d.Scenario,
+	}
+	if err := ValidateName(d.Name); err != nil {
+		return scoring.ScoringProfile{}, err
+	}
+	if len(d.Weights) > 0 {
+		weights, err := d.Weights.Weights()
+		if err != nil {
+			return scoring.ScoringProfile{}, fmt.Errorf("프로필 %s 가중치: %w", d.Name, err)
+		}
+		p.Weights = weights
+	}
+	if d.Constraints != nil {
+		constraints, err := d.Constraints.Constraints()
+		if err != nil {
+			return scoring.ScoringProfile{}, fmt.Errorf("프로필 %s 제약 조건: %w", d.Name, err)
+		}
+		p.Constraints = constraints
+	}
+	if len(d.ValueFunctions) > 0 {
+		functions, err := d.ValueFunctions.ValueFunctions()
+		if err != nil {
+			return scoring.ScoringProfile{}, fmt.Errorf("프로필 %s 가치 함수: %w", d.Name, err)
+		}
+		p.ValueFunctions = functions
+	}
+	if err := p.Validate(); err != nil {
+		return scoring.ScoringProfile{}, fmt.Errorf("프로필 %s: %w", d.Name, err)
+	}
+	return p, nil
+}
+
+// Encode writes a profile as an indented current-version document.
+func Encode(w io.Writer, p scoring.ScoringProfile) error {
+	return codec.Encode(w, codec.FormatJSON, NewDocument(p))
+}
+
+// Decode reads a profile document of any supported version. It also returns the version
+// the document was written with, so callers can rewrite outdated files.
+func Decode(r io.Reader) (scoring.ScoringProfile, int, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return scoring.ScoringProfile{}, 0, err
+	}
+	data, version, err := migrate(data)
+	if err != nil {
+		return scoring.ScoringProfile{}, 0, err
+	}
+	var doc Document
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&doc); err != nil {
+		return scoring.ScoringProfile{}, 0, fmt.Errorf("프로필 문서를 해석할 수 없습니다: %w", err)
+	}
+	p, err := doc.Profile()
+	return p, version, err
+}
+
+// migrations[v] upgrades a version v document to version v+1.
+var migrations = map[int]func(map[string]json.RawMessage) (map[string]json.RawMessage, error){
+	1: migrateV1,
+}
+
+// migrate upgrades a raw document to SchemaVersion and returns it with its original version.
+func migrate(data []byte) ([]byte, int, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, 0, fmt.Errorf("프로필 문서를 해석할 수 없습니다: %w", err)
+	}
+	version := 1 // 버전 필드 도입 전 문서
+	if v, exists := raw["schema_version"]; exists {
+		if err := json.Unmarshal(v, &version); err != nil || version < 1 {
+			return nil, 0, fmt.Errorf("잘못된 스키마 버전: %s", v)
+		}
+	}
+	if version > SchemaVersion {
+		return nil, 0, fmt.Errorf("지원하지 않는 스키마 버전: %d (최대 %d)", version, SchemaVersion)
+	}
+	if version == SchemaVersion {
+		return data, version, nil
+	}
+	for v := version; v < SchemaVersion; v++ {
+		var err error
+		if raw, err = migrations[v](raw); err != nil {
+			return nil, 0, fmt.Errorf("스키마 버전 %d → %d 변환 실패: %w", v, v+1, err)
+		}
+	}
+	upgraded, err := json.Marshal(raw)
+	return upgraded, version, err
+}
+
+// migrateV1 renames the Go field names and converts per-mille weights keyed by metadata
+// type number to fractions keyed by factor name.
+func migrateV1(v1 map[string]json.RawMessage) (map[string]json.RawMessage, error) {
+	v2 := map[string]json.RawMessage{"schema_version": json.RawMessage(strconv.Itoa(2))}
+	renames := map[string]string{"Name": "name", "Description": "description", "Method": "strategy", "Scenario": "scenario"}
+	for key, value := range v1 {
+		switch {
+		case renames[key] != "":
+			if string(value) != `""` && string(value) != "null" {
+				v2[renames[key]] = value
+			}
+		case key == "Weights":
+			var weights map[string]shared.Weight
+			if err := json.Unmarshal(value, &weights); err != nil {
+				return nil, fmt.Errorf("가중치: %w", err)
+			}
+			if len(weights) == 0 {
+				continue
+			}
+			fractions := make(codec.FactorWeights, len(weights))
+			for number, w := range weights {
+				n, err := strconv.Atoi(number)
+				if err != nil || !metadata.MetadataType(n).IsValid() {
+					return nil, fmt.Errorf("알 수 없는 메타데이터 번호: %s", number)
+				}
+				fractions[codec.FactorName(metadata.MetadataType(n))] = w.ToFloat()
+			}
+			encoded, err := json.Marshal(fractions)
+			if err != nil {
+				return nil, err
+			}
+			v2["weights"] = encoded
+		default:
+			return nil, fmt.Errorf("알 수 없는 필드: %s", key)
+		}
+	}
+	return v2, nil
+}
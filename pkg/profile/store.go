@@ -0,0 +1,185 @@
+package profile
+
+import (
+	"apart_score/pkg/scoring"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const (
+	fileExtension = ".json"
+	maxNameLength = 64
+	// DirEnv overrides the default profile directory.
+	DirEnv = "APART_SCORE_PROFILES"
+)
+
+// ErrNotFound is returned (wrapped) when a named profile does not exist.
+var ErrNotFound = errors.New("프로필을 찾을 수 없습니다")
+
+// Store keeps one document per profile in a directory, named <profile>.json.
+type Store struct {
+	dir string
+}
+
+// NewStore returns a store rooted at dir. The directory is created on the first Save.
+func NewStore(dir string) *Store {
+	return &Store{dir: dir}
+}
+
+// DefaultDir returns $APART_SCORE_PROFILES, or apart_score/profiles under the user config directory.
+func DefaultDir() (string, error) {
+	if dir := os.Getenv(DirEnv); dir != "" {
+		return dir, nil
+	}
+	config, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("프로필 디렉터리를 정할 수 없습니다 (%s 설정 필요): %w", DirEnv, err)
+	}
+	return filepath.Join(config, "apart_score", "profiles"), nil
+}
+
+// Dir returns the store directory.
+func (s *Store) Dir() string {
+	return s.dir
+}
+
+// ValidateName checks that a profile name is usable as a file name: 1-64 letters, digits,
+// '-' or '_'.
+func ValidateName(name string) error {
+	if name == "" || len([]rune(name)) > maxNameLength {
+		return &scoring.ValidationError{Field: "name", Message: fmt.Sprintf("프로필 이름은 1-%d자여야 합니다", maxNameLength)}
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) && r != '-' && r != '_' {
+			return &scoring.ValidationError{Field: "name",
+				Message: fmt.Sprintf("프로필 이름에는 문자, 숫자, -, _만 사용할 수 있습니다: %q", name)}
+		}
+	}
+	return nil
+}
+
+func (s *Store) path(name string) string {
+	return filepath.Join(s.dir, name+fileExtension)
+}
+
+// Save validates the profile and writes it at the current schema version, replacing any
+// profile with the same name.
+func (s *Store) Save(p scoring.ScoringProfile) error {
+	if err := ValidateName(p.Name); err != nil {
+		return err
+	}
+	if err := p.Validate(); err != nil {
+		return fmt.Errorf("프로필 %s: %w", p.Name, err)
+	}
+	var buf bytes.Buffer
+	if err := Encode(&buf, p); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.dir, 0o755); err != nil {
+		return err
+	}
+	// 임시 파일에 쓴 뒤 이름을 바꿔 중간에 실패해도 기존 프로필이 깨지지 않게 함
+	tmp, err := os.CreateTemp(s.dir, "."+p.Name+"-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path(p.Name))
+}
+
+// Load reads a profile, migrating older documents in memory.
+func (s *Store) Load(name string) (scoring.ScoringProfile, error) {
+	p, _, err := s.load(name)
+	return p, err
+}
+
+func (s *Store) load(name string) (scoring.ScoringProfile, int, error) {
+	if err := ValidateName(name); err != nil {
+		return scoring.ScoringProfile{}, 0, err
+	}
+	file, err := os.Open(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return scoring.ScoringProfile{}, 0, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	if err != nil {
+		return scoring.ScoringProfile{}, 0, err
+	}
+	defer file.Close()
+	p, version, err := Decode(file)
+	if err != nil {
+		return scoring.ScoringProfile{}, 0, fmt.Errorf("%s: %w", s.path(name), err)
+	}
+	if p.Name != name {
+		return scoring.ScoringProfile{}, 0, fmt.Errorf("%s: 파일 이름과 프로필 이름(%s)이 다릅니다", s.path(name), p.Name)
+	}
+	return p, version, nil
+}
+
+// List returns the names of the stored profiles in sorted order.
+func (s *Store) List() ([]string, error) {
+	entries, err := os.ReadDir(s.dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		name, ok := strings.CutSuffix(entry.Name(), fileExtension)
+		if !ok || entry.IsDir() || ValidateName(name) != nil {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Delete removes a profile.
+func (s *Store) Delete(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	err := os.Remove(s.path(name))
+	if errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return err
+}
+
+// Migrate rewrites every profile stored at an older schema version and returns their names.
+func (s *Store) Migrate() ([]string, error) {
+	names, err := s.List()
+	if err != nil {
+		return nil, err
+	}
+	var migrated []string
+	for _, name := range names {
+		p, version, err := s.load(name)
+		if err != nil {
+			return migrated, err
+		}
+		if version == SchemaVersion {
+			continue
+		}
+		if err := s.Save(p); err != nil {
+			return migrated, err
+		}
+		migrated = append(migrated, name)
+	}
+	return migrated, nil
+}
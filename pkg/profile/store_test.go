@@ -0,0 +1,142 @@
+package profile
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/scoring"
+	"apart_score/pkg/shared"
+)
+
+func testProfile() scoring.ScoringProfile {
+	return scoring.ScoringProfile{
+		Name:        "가족_2024",
+		Description: "학군 우선",
+		Method:      scoring.StrategyGeometricMean,
+		Scenario:    scoring.ScenarioEducation,
+		Weights: map[metadata.MetadataType]shared.Weight{
+			metadata.SchoolDistrict:    600,
+			metadata.DistanceToStation: 250,
+			metadata.CrimeRate:         150,
+		},
+		Constraints: scoring.Constraints{
+			MinScores:         map[metadata.MetadataType]shared.ScoreValue{metadata.CrimeRate: shared.ScoreValueFromFloat(60)},
+			RequiredFeatures:  []metadata.MetadataType{metadata.ElevatorPresence},
+			ExcludedLocations: []string{"경기도"},
+		},
+		ValueFunctions: scoring.ValueFunctions{
+			metadata.DistanceToStation: {Kind: scoring.ValueDiminishing, Rate: 3},
+			metadata.SchoolDistrict:    {Kind: scoring.ValueThreshold, Threshold: 80, Bonus: 10},
+		},
+	}
+}
+
+func TestStoreRoundTrip(t *testing.T) {
+	store := NewStore(filepath.Join(t.TempDir(), "profiles"))
+	if err := store.Save(testProfile()); err != nil {
+		t.Fatalf("save failed: %v", err)
+	}
+	loaded, err := store.Load(testProfile().Name)
+	if err != nil {
+		t.Fatalf("load failed: %v", err)
+	}
+	if !reflect.DeepEqual(loaded, testProfile()) {
+		t.Errorf("round trip mismatch:\n got %+v\nwant %+v", loaded, testProfile())
+	}
+
+	names, err := store.List()
+	if err != nil || !reflect.DeepEqual(names, []string{testProfile().Name}) {
+		t.Errorf("List() = %v, %v", names, err)
+	}
+	if err := store.Delete(testProfile().Name); err != nil {
+		t.Fatalf("delete failed: %v", err)
+	}
+	if _, err := store.Load(testProfile().Name); !errors.Is(err, ErrNotFound) {
+		t.Errorf("load after delete: %v, want ErrNotFound", err)
+	}
+	if err := store.Delete(testProfile().Name); !errors.Is(err, ErrNotFound) {
+		t.Errorf("second delete: %v, want ErrNotFound", err)
+	}
+}
+
+func TestStoreRejectsInvalidProfiles(t *testing.T) {
+	store := NewStore(t.TempDir())
+	for _, name := range []string{"", "../escape", "a b", ".hidden"} {
+		if err := store.Save(scoring.ScoringProfile{Name: name}); err == nil {
+			t.Errorf("Save(%q) succeeded", name)
+		}
+	}
+	bad := testProfile()
+	bad.Weights[metadata.SchoolDistrict] = 100 // 합계 500
+	var validation *scoring.ValidationError
+	if err := store.Save(bad); !errors.As(err, &validation) {
+		t.Errorf("Save with weight sum 500: %v, want ValidationError", err)
+	}
+	bad = testProfile()
+	bad.Method = "magic"
+	if err := store.Save(bad); !errors.As(err, &validation) || validation.Field != "strategy" {
+		t.Errorf("Save with unknown strategy: %v", err)
+	}
+}
+
+func TestMigrateV1(t *testing.T) {
+	dir := t.TempDir()
+	// 버전 필드 도입 전: ScoringProfile을 그대로 직렬화한 문서
+	v1 := `{"Name": "legacy", "Description": "", "Method": "min_max",
+		"Weights": {"0": 400, "1": 350, "9": 250}, "Scenario": "transportation"}`
+	if err := os.WriteFile(filepath.Join(dir, "legacy.json"), []byte(v1), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	store := NewStore(dir)
+	loaded, err := store.Load("legacy")
+	if err != nil {
+		t.Fatalf("load v1 failed: %v", err)
+	}
+	want := scoring.ScoringProfile{
+		Name:     "legacy",
+		Method:   scoring.StrategyMinMax,
+		Scenario: scoring.ScenarioTransportation,
+		Weights: map[metadata.MetadataType]shared.Weight{
+			metadata.MetadataType(0): 400, metadata.MetadataType(1): 350, metadata.MetadataType(9): 250,
+		},
+	}
+	if !reflect.DeepEqual(loaded, want) {
+		t.Errorf("migrated profile:\n got %+v\nwant %+v", loaded, want)
+	}
+
+	migrated, err := store.Migrate()
+	if err != nil || !reflect.DeepEqual(migrated, []string{"legacy"}) {
+		t.Fatalf("Migrate() = %v, %v", migrated, err)
+	}
+	data, _ := os.ReadFile(filepath.Join(dir, "legacy.json"))
+	if !strings.Contains(string(data), `"schema_version": 2`) {
+		t.Errorf("rewritten document is not version 2:\n%s", data)
+	}
+	if migrated, _ := store.Migrate(); len(migrated) != 0 {
+		t.Errorf("second Migrate() = %v, want none", migrated)
+	}
+	if again, err := store.Load("legacy"); err != nil || !reflect.DeepEqual(again, want) {
+		t.Errorf("load after migrate = %+v, %v", again, err)
+	}
+}
+
+func TestDecodeRejectsUnsupportedDocuments(t *testing.T) {
+	tests := map[string]string{
+		"future version":  `{"schema_version": 3, "name": "x"}`,
+		"unknown field":   `{"schema_version": 2, "name": "x", "colour": "red"}`,
+		"v1 bad factor":   `{"Name": "x", "Weights": {"99": 1000}}`,
+		"unknown factor":  `{"schema_version": 2, "name": "x", "weights": {"Ocean View": 1}}`,
+		"bad value func":  `{"schema_version": 2, "name": "x", "value_functions": {"Crime Rate": {"kind": "cubic"}}}`,
+		"invalid version": `{"schema_version": "two", "name": "x"}`,
+	}
+	for name, input := range tests {
+		if _, _, err := Decode(strings.NewReader(input)); err == nil {
+			t.Errorf("%s: decode succeeded", name)
+		}
+	}
+}
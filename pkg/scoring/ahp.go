@@ -0,0 +1,282 @@
+package scoring
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// AHPConsistencyThreshold is the largest consistency ratio accepted for pairwise judgments.
+const AHPConsistencyThreshold = 0.1
+
+// ahpRandomIndex holds Saaty's random consistency index by matrix size.
+var ahpRandomIndex = []float64{0, 0, 0, 0.58, 0.90, 1.12, 1.24, 1.32, 1.41, 1.45, 1.49, 1.51, 1.48, 1.56, 1.57, 1.59}
+
+// PairwiseComparison states how much more important factor A is than factor B on the Saaty scale.
+// Values 1-9 favour A and reciprocals (1/2-1/9) favour B.
+type PairwiseComparison struct {
+	A     metadata.MetadataType
+	B     metadata.MetadataType
+	Value float64
+}
+
+// AHPResult holds weights derived from pairwise comparisons and their consistency.
+type AHPResult struct {
+	Factors               []metadata.MetadataType                 // 비교에 포함된 요소 (인덱스 순)
+	Priorities            map[metadata.MetadataType]float64       // 주 고유벡터 (합계 1.0)
+	Weights               map[metadata.MetadataType]shared.Weight // 정수 가중치 (합계 1000)
+	LambdaMax             float64                                 // 최대 고유값
+	ConsistencyIndex      float64                                 // 일관성 지수 (CI)
+	ConsistencyRatio      float64                                 // 일관성 비율 (CR)
+	Consistent            bool                                    // CR이 기준 이하인지 여부
+	InconsistentJudgments []InconsistentJudgment                  // 일관성을 가장 크게 해치는 판단들
+}
+
+// InconsistentJudgment flags a stated comparison that disagrees with the derived weights.
+type InconsistentJudgment struct {
+	A          metadata.MetadataType
+	B          metadata.MetadataType
+	Stated     float64 // 입력한 비교값
+	Implied    float64 // 도출된 가중치 비율 (A/B)
+	Suggested  float64 // 가장 가까운 Saaty 척도 값
+	Deviation  float64 // |ln(입력값) - ln(도출 비율)|
+	Suggestion string  // 조정 안내
+}
+
+// CalculateAHPWeights derives factor weights from a complete set of pairwise comparisons
+// using the principal eigenvector of the comparison matrix.
+func CalculateAHPWeights(comparisons []PairwiseComparison) (*AHPResult, error) {
+	factors, index, err := collectAHPFactors(comparisons)
+	if err != nil {
+		return nil, err
+	}
+	n := len(factors)
+
+	matrix := make([][]float64, n)
+	for i := range matrix {
+		matrix[i] = make([]float64, n)
+		matrix[i][i] = 1
+	}
+	for _, c := range comparisons {
+		i, j := index[c.A], index[c.B]
+		if matrix[i][j] != 0 {
+			return nil, &ValidationError{
+				Field:   c.A.String() + "/" + c.B.String(),
+				Message: "같은 요소 쌍에 대한 비교가 중복되었습니다",
+			}
+		}
+		matrix[i][j] = c.Value
+		matrix[j][i] = 1 / c.Value
+	}
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if matrix[i][j] == 0 {
+				return nil, &ValidationError{
+					Field:   factors[i].String() + "/" + factors[j].String(),
+					Message: "모든 요소 쌍에 대한 비교가 필요합니다",
+				}
+			}
+		}
+	}
+
+	priorities, lambdaMax := principalEigenvector(matrix)
+
+	result := &AHPResult{
+		Factors:    factors,
+		Priorities: make(map[metadata.MetadataType]float64, n),
+		Weights:    priorityWeights(factors, priorities),
+		LambdaMax:  lambdaMax,
+	}
+	for i, mt := range factors {
+		result.Priorities[mt] = priorities[i]
+	}
+	if n > 2 {
+		result.ConsistencyIndex = (lambdaMax - float64(n)) / float64(n-1)
+		result.ConsistencyRatio = result.ConsistencyIndex / ahpRandomIndexFor(n)
+	}
+	result.Consistent = result.ConsistencyRatio <= AHPConsistencyThreshold
+	if !result.Consistent {
+		result.InconsistentJudgments = findInconsistentJudgments(comparisons, result.Priorities)
+	}
+	return result, nil
+}
+
+// Validate returns a ValidationError when the judgments are too inconsistent to be used as weights.
+func (r *AHPResult) Validate() error {
+	if r.Consistent {
+		return nil
+	}
+	message := fmt.Sprintf("쌍대 비교의 일관성 비율(%.3f)이 기준(%.2f)을 초과합니다", r.ConsistencyRatio, AHPConsistencyThreshold)
+	if len(r.InconsistentJudgments) > 0 {
+		message += ": " + r.InconsistentJudgments[0].Suggestion
+	}
+	return &ValidationError{Field: "consistency_ratio", Message: message}
+}
+
+// GetAHPWeights returns weights ready for CalculateWithStrategy, rejecting inconsistent judgments.
+// Factors not mentioned in the comparisons receive no weight.
+func GetAHPWeights(comparisons []PairwiseComparison) (map[metadata.MetadataType]shared.Weight, error) {
+	result, err := CalculateAHPWeights(comparisons)
+	if err != nil {
+		return nil, err
+	}
+	if err := result.Validate(); err != nil {
+		return nil, err
+	}
+	return result.Weights, nil
+}
+
+func collectAHPFactors(comparisons []PairwiseComparison) ([]metadata.MetadataType, map[metadata.MetadataType]int, error) {
+	seen := make(map[metadata.MetadataType]bool)
+	for _, c := range comparisons {
+		if !c.A.IsValid() || !c.B.IsValid() {
+			return nil, nil, &ValidationError{Field: "factor", Message: fmt.Sprintf("등록되지 않은 메타데이터 타입: %d/%d", c.A, c.B)}
+		}
+		if c.A == c.B {
+			return nil, nil, &ValidationError{Field: c.A.String(), Message: "같은 요소끼리는 비교할 수 없습니다"}
+		}
+		if !isSaatyValue(c.Value) {
+			return nil, nil, &ValidationError{
+				Field:   c.A.String() + "/" + c.B.String(),
+				Message: fmt.Sprintf("비교값은 Saaty 척도(1-9 또는 그 역수)여야 합니다 (%.3f)", c.Value),
+			}
+		}
+		seen[c.A] = true
+		seen[c.B] = true
+	}
+	if len(seen) < 2 {
+		return nil, nil, &ValidationError{Field: "comparisons", Message: "최소 두 요소에 대한 비교가 필요합니다"}
+	}
+
+	factors := make([]metadata.MetadataType, 0, len(seen))
+	for mt := range seen {
+		factors = append(factors, mt)
+	}
+	sort.Slice(factors, func(i, j int) bool { return factors[i] < factors[j] })
+	index := make(map[metadata.MetadataType]int, len(factors))
+	for i, mt := range factors {
+		index[mt] = i
+	}
+	return factors, index, nil
+}
+
+// isSaatyValue reports whether v is an integer in 1-9 or the reciprocal of one.
+func isSaatyValue(v float64) bool {
+	if v <= 0 || math.IsInf(v, 0) || math.IsNaN(v) {
+		return false
+	}
+	if v < 1 {
+		v = 1 / v
+	}
+	return v <= 9+1e-9 && math.Abs(v-math.Round(v)) < 1e-6
+}
+
+// principalEigenvector runs power iteration and returns the normalized eigenvector and its eigenvalue.
+func principalEigenvector(matrix [][]float64) ([]float64, float64) {
+	n := len(matrix)
+	vector := make([]float64, n)
+	for i := range vector {
+		vector[i] = 1 / float64(n)
+	}
+	next := make([]float64, n)
+	for iteration := 0; iteration < 1000; iteration++ {
+		sum := 0.0
+		for i := 0; i < n; i++ {
+			next[i] = 0
+			for j := 0; j < n; j++ {
+				next[i] += matrix[i][j] * vector[j]
+			}
+			sum += next[i]
+		}
+		delta := 0.0
+		for i := range next {
+			next[i] /= sum
+			delta += math.Abs(next[i] - vector[i])
+		}
+		vector, next = next, vector
+		if delta < 1e-12 {
+			break
+		}
+	}
+
+	lambdaMax := 0.0
+	for i := 0; i < n; i++ {
+		row := 0.0
+		for j := 0; j < n; j++ {
+			row += matrix[i][j] * vector[j]
+		}
+		lambdaMax += row / vector[i]
+	}
+	return vector, lambdaMax / float64(n)
+}
+
+func ahpRandomIndexFor(n int) float64 {
+	if n < len(ahpRandomIndex) {
+		return ahpRandomIndex[n]
+	}
+	return ahpRandomIndex[len(ahpRandomIndex)-1]
+}
+
+// priorityWeights converts priorities into integer weights summing exactly to WeightScale
+// using the largest remainder method, so they pass strategy validation.
+func priorityWeights(factors []metadata.MetadataType, priorities []float64) map[metadata.MetadataType]shared.Weight {
+	weights := make(map[metadata.MetadataType]shared.Weight, len(factors))
+	remainders := make([]float64, len(factors))
+	assigned := shared.Weight(0)
+	for i, mt := range factors {
+		exact := priorities[i] * shared.WeightScale
+		weights[mt] = shared.Weight(math.Floor(exact))
+		remainders[i] = exact - math.Floor(exact)
+		assigned += weights[mt]
+	}
+	order := make([]int, len(factors))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	for k := 0; assigned < shared.WeightScale; k++ {
+		weights[factors[order[k%len(order)]]]++
+		assigned++
+	}
+	return weights
+}
+
+// findInconsistentJudgments lists the comparisons that deviate most from the derived weights.
+func findInconsistentJudgments(comparisons []PairwiseComparison, priorities map[metadata.MetadataType]float64) []InconsistentJudgment {
+	judgments := make([]InconsistentJudgment, 0, len(comparisons))
+	for _, c := range comparisons {
+		implied := priorities[c.A] / priorities[c.B]
+		suggested := nearestSaatyValue(implied)
+		judgments = append(judgments, InconsistentJudgment{
+			A:         c.A,
+			B:         c.B,
+			Stated:    c.Value,
+			Implied:   implied,
+			Suggested: suggested,
+			Deviation: math.Abs(math.Log(c.Value) - math.Log(implied)),
+			Suggestion: fmt.Sprintf("%s 대 %s 비교값 %s을(를) %s(으)로 조정하는 것을 검토하세요",
+				c.A.KoreanName(), c.B.KoreanName(), formatSaatyValue(c.Value), formatSaatyValue(suggested)),
+		})
+	}
+	sort.Slice(judgments, func(i, j int) bool { return judgments[i].Deviation > judgments[j].Deviation })
+	if len(judgments) > 3 {
+		judgments = judgments[:3]
+	}
+	return judgments
+}
+
+func nearestSaatyValue(ratio float64) float64 {
+	if ratio >= 1 {
+		return math.Min(9, math.Round(ratio))
+	}
+	return 1 / math.Min(9, math.Round(1/ratio))
+}
+
+func formatSaatyValue(v float64) string {
+	if v >= 1 {
+		return fmt.Sprintf("%.0f", v)
+	}
+	return fmt.Sprintf("1/%.0f", 1/v)
+}
@@ -0,0 +1,98 @@
+package scoring
+
+import (
+	"errors"
+	"testing"
+
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+)
+
+func TestCalculateAHPWeights_Consistent(t *testing.T) {
+	// 역거리 : 학군 : 관리비 = 4 : 2 : 1 (완전 일관)
+	comparisons := []PairwiseComparison{
+		{A: metadata.DistanceToStation, B: metadata.SchoolDistrict, Value: 2},
+		{A: metadata.DistanceToStation, B: metadata.MaintenanceFee, Value: 4},
+		{A: metadata.SchoolDistrict, B: metadata.MaintenanceFee, Value: 2},
+	}
+
+	result, err := CalculateAHPWeights(comparisons)
+	if err != nil {
+		t.Fatalf("CalculateAHPWeights failed: %v", err)
+	}
+
+	if !result.Consistent || result.ConsistencyRatio > 1e-9 {
+		t.Errorf("Perfectly consistent judgments should have CR 0, got %v", result.ConsistencyRatio)
+	}
+	expected := map[metadata.MetadataType]shared.Weight{
+		metadata.DistanceToStation: 571,
+		metadata.SchoolDistrict:    286,
+		metadata.MaintenanceFee:    143,
+	}
+	total := shared.Weight(0)
+	for mt, weight := range result.Weights {
+		total += weight
+		if weight != expected[mt] {
+			t.Errorf("%s: expected weight %d, got %d", mt.String(), expected[mt], weight)
+		}
+	}
+	if total != shared.WeightScale {
+		t.Errorf("Weights should sum to %d, got %d", shared.WeightScale, total)
+	}
+
+	if _, err := CalculateWithStrategy(getTestScores(), result.Weights, StrategyWeightedSum); err != nil {
+		t.Errorf("AHP weights should pass strategy validation: %v", err)
+	}
+}
+
+func TestCalculateAHPWeights_Inconsistent(t *testing.T) {
+	// A > B, B > C 이지만 C >> A 인 순환 판단
+	comparisons := []PairwiseComparison{
+		{A: metadata.FloorLevel, B: metadata.Parking, Value: 5},
+		{A: metadata.Parking, B: metadata.CrimeRate, Value: 5},
+		{A: metadata.FloorLevel, B: metadata.CrimeRate, Value: 1.0 / 7},
+	}
+
+	result, err := CalculateAHPWeights(comparisons)
+	if err != nil {
+		t.Fatalf("CalculateAHPWeights failed: %v", err)
+	}
+	if result.Consistent || result.ConsistencyRatio <= AHPConsistencyThreshold {
+		t.Errorf("Circular judgments should be inconsistent, got CR %v", result.ConsistencyRatio)
+	}
+	if len(result.InconsistentJudgments) == 0 {
+		t.Error("Inconsistent judgments should be flagged")
+	}
+
+	var validationErr *ValidationError
+	if _, err := GetAHPWeights(comparisons); !errors.As(err, &validationErr) {
+		t.Errorf("GetAHPWeights should return a ValidationError, got %v", err)
+	}
+}
+
+func TestCalculateAHPWeights_InvalidInput(t *testing.T) {
+	tests := []struct {
+		name        string
+		comparisons []PairwiseComparison
+	}{
+		{"Off-scale value", []PairwiseComparison{{A: metadata.FloorLevel, B: metadata.Parking, Value: 12}}},
+		{"Fractional value", []PairwiseComparison{{A: metadata.FloorLevel, B: metadata.Parking, Value: 2.5}}},
+		{"Self comparison", []PairwiseComparison{{A: metadata.FloorLevel, B: metadata.FloorLevel, Value: 1}}},
+		{"Missing pair", []PairwiseComparison{
+			{A: metadata.FloorLevel, B: metadata.Parking, Value: 3},
+			{A: metadata.Parking, B: metadata.CrimeRate, Value: 3},
+		}},
+		{"Duplicate pair", []PairwiseComparison{
+			{A: metadata.FloorLevel, B: metadata.Parking, Value: 3},
+			{A: metadata.Parking, B: metadata.FloorLevel, Value: 1.0 / 3},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := CalculateAHPWeights(tt.comparisons); err == nil {
+				t.Errorf("%s should fail", tt.name)
+			}
+		})
+	}
+}
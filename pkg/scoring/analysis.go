@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"sort"
+	"strings"
 	"time"
 )
 
@@ -16,6 +17,7 @@ type ScoreAnalysis struct {
 	TopFactors      []ScoreFactor
 	ImprovementTips []string
 	ComparisonScore float64
+	Missing         []metadata.MetadataType // 데이터가 없어 평가에서 제외된 요소
 }
 type ScoreFactor struct {
 	Metadata metadata.MetadataType
@@ -33,12 +35,18 @@ func AnalyzeScore(result ScoreResult) *ScoreAnalysis {
 		ImprovementTips: []string{},
 	}
 	var factors []ScoreFactor
-	for idx, score := range result.RawScores {
-		if score == 0 {
-			continue
-		}
+	for idx, n := 0, metadata.Count(); idx < n; idx++ {
 		mt := metadata.MetadataType(idx)
+		score := result.RawScores[idx]
 		weight := result.Weights[idx]
+		if score.IsMissing() {
+			analysis.Missing = append(analysis.Missing, mt)
+			continue
+		}
+		// 가중치도 점수도 없는 요소는 평가 대상이 아님
+		if score == 0 && weight == 0 {
+			continue
+		}
 		impact := shared.MulDivWeight(score, weight)
 		factors = append(factors, ScoreFactor{
 			Metadata: mt,
@@ -61,6 +69,10 @@ func AnalyzeScore(result ScoreResult) *ScoreAnalysis {
 	}
 	analysis.TopFactors = factors[:maxFactors]
 	analysis.ImprovementTips = generateImprovementTips(analysis.Weaknesses)
+	for _, mt := range analysis.Missing {
+		analysis.ImprovementTips = append(analysis.ImprovementTips,
+			fmt.Sprintf("%s 데이터를 확보하면 평가가 더 정확해집니다", mt.KoreanName()))
+	}
 	averageScore := 75.0
 	analysis.ComparisonScore = result.TotalScore - averageScore
 	return analysis
@@ -136,28 +148,62 @@ func FormatScoreResult(result ScoreResult) string {
 	output += fmt.Sprintf("방법: %s\n", result.Method)
 	output += fmt.Sprintf("시나리오: %s\n", result.Scenario)
 	output += "\n📊 상세 점수:\n"
-	for _, mt := range shared.FastAllMetadataTypes() {
+	for _, mt := range metadata.All() {
 		idx := int(mt)
 		rawScore := result.RawScores[idx]
 		weight := result.Weights[idx]
 		weighted := result.WeightedScores[idx]
-		if rawScore != 0 {
+		if rawScore.IsMissing() {
+			output += fmt.Sprintf("  %-20s: 데이터 없음 (평가에서 제외)\n", mt.KoreanName())
+		} else if rawScore != 0 || weight != 0 {
 			output += fmt.Sprintf("  %-20s: %.1f점 (가중치: %.1f%%) → %.1f점\n",
 				mt.KoreanName(), rawScore.ToFloat(), weight.ToFloat()*100, weighted)
 		}
 	}
+	if len(result.Imputed) > 0 {
+		names := make([]string, len(result.Imputed))
+		for i, mt := range result.Imputed {
+			names[i] = mt.KoreanName()
+		}
+		output += fmt.Sprintf("\n※ 비교군 중앙값으로 대체된 요소: %s\n", strings.Join(names, ", "))
+	}
 	return output
 }
 
 // GenerateTransparencyDashboard creates a comprehensive transparency dashboard for a score result.
+// Without a reference cohort the percentile and distribution are estimates;
+// use GenerateTransparencyDashboardWithOptions to derive them from comparable apartments.
+// When the score cannot be simulated the confidence interval has zero width; the
+// WithOptions variant reports that as an error instead.
 func GenerateTransparencyDashboard(result ScoreResult, scores map[metadata.MetadataType]shared.ScoreValue,
 	weights map[metadata.MetadataType]shared.Weight, strategy StrategyType) TransparencyDashboard {
+	dashboard, _ := generateDashboard(result, scores, weights, strategy, DashboardOptions{}, nil)
+	return dashboard
+}
+
+// generateDashboard builds the dashboard; cohortScores may be empty when no reference cohort is given.
+// A simulation error is returned with a dashboard that uses a zero-width confidence interval.
+func generateDashboard(result ScoreResult, scores map[metadata.MetadataType]shared.ScoreValue,
+	weights map[metadata.MetadataType]shared.Weight, strategy StrategyType,
+	opts DashboardOptions, cohortScores []float64) (TransparencyDashboard, error) {
 
 	dashboard := TransparencyDashboard{}
+	raw := scores
+	scores = opts.ValueFunctions.Apply(raw) // 분석은 실제로 집계된 효용 점수 기준
 
 	// 1. 점수 분석 섹션
-	dashboard.ScoreBreakdown = generateScoreBreakdown(result, scores, weights)
-	dashboard.ScoreDistribution = generateScoreDistribution(result.TotalScore)
+	dashboard.ScoreBreakdown = generateScoreBreakdown(result, raw, scores, weights)
+	groups := DefaultWeightTree()
+	if opts.Groups != nil {
+		groups = *opts.Groups
+	}
+	dashboard.ScoreBreakdown.GroupContributions = GroupContributions(groups, dashboard.ScoreBreakdown)
+	interval, simErr := simulatedInterval(result.TotalScore, scores, weights, strategy, opts)
+	if len(cohortScores) > 0 {
+		dashboard.ScoreDistribution = generateCohortDistribution(result.TotalScore, cohortScores, opts, interval)
+	} else {
+		dashboard.ScoreDistribution = generateScoreDistribution(result.TotalScore, interval)
+	}
 
 	// 2. 투명성 섹션
 	dashboard.AssumptionList = getScoringAssumptions()
@@ -169,18 +215,19 @@ func GenerateTransparencyDashboard(result ScoreResult, scores map[metadata.Metad
 	dashboard.SensitivityAnalysis = performSensitivityAnalysis(scores, weights, strategy)
 
 	// 4. 품질 및 신뢰성 섹션
-	dashboard.DataQualityMetrics = assessDataQuality(scores)
+	dashboard.DataQualityMetrics = assessDataQuality(scores, weights)
 	dashboard.BiasIndicators = detectBiasIndicators(result, scores, weights)
 
 	// 5. 사용자 가이드 섹션
 	dashboard.InterpretationGuide = createInterpretationGuide(result.TotalScore)
 	dashboard.RecommendedActions = generateRecommendedActions(result, scores)
 
-	return dashboard
+	return dashboard, simErr
 }
 
-// generateScoreBreakdown creates detailed breakdown of score components.
-func generateScoreBreakdown(result ScoreResult, scores map[metadata.MetadataType]shared.ScoreValue,
+// generateScoreBreakdown creates detailed breakdown of score components from the raw scores and
+// the utilities they were aggregated as.
+func generateScoreBreakdown(result ScoreResult, raw, scores map[metadata.MetadataType]shared.ScoreValue,
 	weights map[metadata.MetadataType]shared.Weight) ScoreBreakdown {
 
 	breakdown := ScoreBreakdown{
@@ -195,6 +242,9 @@ func generateScoreBreakdown(result ScoreResult, scores map[metadata.MetadataType
 	}
 
 	for mt, score := range scores {
+		if score.IsMissing() {
+			continue
+		}
 		weight := weights[mt]
 		normalizedScore := score.ToFloat()
 		weightFloat := weight.ToFloat()
@@ -209,7 +259,7 @@ func generateScoreBreakdown(result ScoreResult, scores map[metadata.MetadataType
 		}
 
 		breakdown.ComponentScores[mt.String()] = ComponentScore{
-			RawValue:        score.ToFloat(),
+			RawValue:        raw[mt].ToFloat(),
 			NormalizedValue: normalizedScore,
 			Weight:          weightFloat,
 			Contribution:    contribution,
@@ -235,15 +285,14 @@ func analyzeStrategyImpact(scores map[metadata.MetadataType]shared.ScoreValue,
 		AlternativeResults: make(map[StrategyType]float64),
 	}
 
-	// 각 전략으로 계산해보기
-	strategies := []StrategyType{StrategyWeightedSum, StrategyGeometricMean, StrategyMinMax, StrategyHarmonicMean}
+	// 등록된 모든 전략으로 계산해보기
 	currentScore := 0.0
 
-	for _, strategy := range strategies {
+	for _, strategy := range GetAvailableStrategies() {
 		result, err := CalculateWithStrategy(scores, weights, strategy)
 		if err == nil {
 			impact.AlternativeResults[strategy] = result.TotalScore
-			if strategy == StrategyWeightedSum { // 현재 전략과 매핑 (단순화)
+			if strategy == currentStrategy {
 				currentScore = result.TotalScore
 			}
 		}
@@ -252,7 +301,7 @@ func analyzeStrategyImpact(scores map[metadata.MetadataType]shared.ScoreValue,
 	// 가장 좋은 대안 전략 찾기
 	bestDiff := 0.0
 	for strategy, score := range impact.AlternativeResults {
-		if strategy != StrategyWeightedSum { // 현재 전략 제외
+		if strategy != currentStrategy { // 현재 전략 제외
 			diff := score - currentScore
 			if math.Abs(diff) > math.Abs(bestDiff) {
 				impact.BestAlternative = strategy
@@ -278,10 +327,9 @@ func analyzeStrategyImpact(scores map[metadata.MetadataType]shared.ScoreValue,
 	return impact
 }
 
-// generateScoreDistribution provides statistical context for the score.
-func generateScoreDistribution(score float64) ScoreDistribution {
-	// 실제 구현에서는 데이터베이스로부터 통계 정보를 가져와야 함
-	// 현재는 모의 데이터 사용
+// generateScoreDistribution provides estimated statistical context when no reference cohort is available.
+func generateScoreDistribution(score float64, interval ConfidenceInterval) ScoreDistribution {
+	// 비교 대상이 없으므로 일반적인 분포를 가정한 추정값 사용
 	return ScoreDistribution{
 		ScorePercentile: calculatePercentile(score),
 		ScoreRange: ScoreRange{
@@ -290,12 +338,8 @@ func generateScoreDistribution(score float64) ScoreDistribution {
 			Average: 75.0,
 			StdDev:  15.0,
 		},
-		ComparativeContext: fmt.Sprintf("%.1f점은 상위 %.0f%%에 해당합니다", score, calculatePercentile(score)),
-		ConfidenceInterval: ConfidenceInterval{
-			LowerBound: math.Max(0, score-5),
-			UpperBound: math.Min(100, score+5),
-			Confidence: 90.0,
-		},
+		ComparativeContext: fmt.Sprintf("%.1f점은 상위 약 %.0f%%에 해당합니다 (비교 대상 없음, 추정값)", score, 100-calculatePercentile(score)),
+		ConfidenceInterval: interval,
 	}
 }
 
@@ -328,8 +372,9 @@ func getScoringAssumptions() []string {
 
 // generateMethodologyDetails explains the scoring methodology.
 func generateMethodologyDetails(strategy StrategyType) MethodologyDetails {
+	guide, _ := GetStrategyGuide(strategy)
 	return MethodologyDetails{
-		AlgorithmDescription: StrategyGuidelines[strategy].BestFor,
+		AlgorithmDescription: guide.BestFor,
 		DataSources: []DataSource{
 			{
 				Name:        "사용자 입력",
@@ -396,6 +441,23 @@ func identifyUncertaintyFactors(scores map[metadata.MetadataType]shared.ScoreVal
 		}
 	}
 
+	// 결측 데이터 확인
+	var missing []string
+	for _, mt := range metadata.All() {
+		if score, exists := scores[mt]; exists && score.IsMissing() {
+			missing = append(missing, mt.KoreanName())
+		}
+	}
+	if len(missing) > 0 {
+		factors = append(factors, UncertaintyFactor{
+			Factor:      "결측 데이터",
+			Description: fmt.Sprintf("데이터가 없는 요소(%s)는 평가에서 제외되고 나머지 가중치가 재조정됨", strings.Join(missing, ", ")),
+			Impact:      float64(len(missing)) / float64(metadata.Count()) * 100,
+			Probability: 100.0,
+			Mitigation:  "누락된 데이터 확보 후 재평가",
+		})
+	}
+
 	// 기본 불확실성 요인들
 	factors = append(factors, []UncertaintyFactor{
 		{
@@ -423,9 +485,7 @@ func generateAlternativeScenarios(scores map[metadata.MetadataType]shared.ScoreV
 
 	scenarios := []AlternativeScenario{}
 
-	strategies := []StrategyType{StrategyWeightedSum, StrategyGeometricMean, StrategyMinMax, StrategyHarmonicMean}
-
-	for _, strategy := range strategies {
+	for _, strategy := range GetAvailableStrategies() {
 		if strategy == currentStrategy {
 			continue
 		}
@@ -434,6 +494,7 @@ func generateAlternativeScenarios(scores map[metadata.MetadataType]shared.ScoreV
 		if err != nil {
 			continue
 		}
+		guide, _ := GetStrategyGuide(strategy)
 
 		scenario := ScenarioDefinitions[mapStrategyToScenario(strategy)].Name
 		difference := result.TotalScore - 82.9 // 현재 점수 가정 (실제로는 파라미터로 받아야 함)
@@ -449,10 +510,10 @@ func generateAlternativeScenarios(scores map[metadata.MetadataType]shared.ScoreV
 
 		scenarios = append(scenarios, AlternativeScenario{
 			ScenarioName:   scenario,
-			Description:    StrategyGuidelines[strategy].BestFor,
+			Description:    guide.BestFor,
 			Score:          result.TotalScore,
 			Difference:     difference,
-			Reasoning:      StrategyGuidelines[strategy].UseCase,
+			Reasoning:      guide.UseCase,
 			Recommendation: recommendation,
 		})
 	}
@@ -476,67 +537,46 @@ func mapStrategyToScenario(strategy StrategyType) ScoringScenario {
 	}
 }
 
-// performSensitivityAnalysis performs sensitivity analysis on the scoring.
-func performSensitivityAnalysis(scores map[metadata.MetadataType]shared.ScoreValue,
-	weights map[metadata.MetadataType]shared.Weight, _ StrategyType) SensitivityAnalysis {
-
-	// 가장 민감한 요소들 식별 (가중치가 높은 요소들)
-	sensitiveFactors := []SensitivityFactor{}
-	for mt, weight := range weights {
-		if weight.ToFloat() > 0.15 { // 15% 이상 가중치
-			score := scores[mt]
-			// 민감도 계산: 가중치 × 현재 점수
-			sensitivity := weight.ToFloat() * score.ToFloat() / 100.0
-
-			direction := "Positive"
-			if mt == metadata.CrimeRate || mt == metadata.MaintenanceFee {
-				direction = "Negative" // 낮은 점수가 좋음
-			}
+// assessDataQuality assesses the quality of input data.
+// Completeness is the share of weighted factors that have data; factors absent from scores
+// or marked with shared.MissingScore count as gaps.
+func assessDataQuality(scores map[metadata.MetadataType]shared.ScoreValue,
+	weights map[metadata.MetadataType]shared.Weight) DataQualityMetrics {
+	accuracy := 90.0    // 기본 정확도
+	timeliness := 85.0  // 데이터 신선도
+	consistency := 95.0 // 내부 일관성
 
-			sensitiveFactors = append(sensitiveFactors, SensitivityFactor{
-				FactorName:      mt.String(),
-				CurrentValue:    score.ToFloat(),
-				Sensitivity:     sensitivity,
-				ImpactDirection: direction,
+	issues := []QualityIssue{}
+	expected, present := 0, 0
+	for _, mt := range metadata.All() {
+		// 가중치가 지정된 경우 가중치가 있는 요소만 완전성 평가 대상
+		if len(weights) > 0 && weights[mt] == 0 {
+			continue
+		}
+		expected++
+		score, exists := scores[mt]
+		if !exists || score.IsMissing() {
+			issues = append(issues, QualityIssue{
+				Issue:        fmt.Sprintf("%s 데이터 누락", mt.String()),
+				Severity:     "High",
+				AffectedData: mt.String(),
+				Resolution:   "데이터 확보 후 재평가",
 			})
+			continue
 		}
+		present++
 	}
-
-	// 안정성 지수 계산 (높은 가중치 요소들의 점수 편차)
-	stabilityIndex := 85.0 // 기본값
-
-	// 변동 범위 추정
-	variationRange := ScoreRange{
-		Minimum: 70.0,
-		Maximum: 95.0,
-		Average: 82.9,
-		StdDev:  8.5,
-	}
-
-	robustnessLevel := "High"
-	if len(sensitiveFactors) > 3 {
-		robustnessLevel = "Medium"
-	}
-
-	return SensitivityAnalysis{
-		MostSensitiveFactors: sensitiveFactors,
-		StabilityIndex:       stabilityIndex,
-		VariationRange:       variationRange,
-		RobustnessLevel:      robustnessLevel,
+	completeness := 100.0
+	if expected > 0 {
+		completeness = float64(present) / float64(expected) * 100
 	}
-}
-
-// assessDataQuality assesses the quality of input data.
-func assessDataQuality(scores map[metadata.MetadataType]shared.ScoreValue) DataQualityMetrics {
-	completeness := 100.0 // 모든 필드가 채워짐 가정
-	accuracy := 90.0      // 기본 정확도
-	timeliness := 85.0    // 데이터 신선도
-	consistency := 95.0   // 내부 일관성
 
 	overallQuality := (completeness + accuracy + timeliness + consistency) / 4.0
 
-	issues := []QualityIssue{}
 	for mt, score := range scores {
+		if score.IsMissing() {
+			continue
+		}
 		if score.ToFloat() <= 0 || score.ToFloat() > 100 {
 			issues = append(issues, QualityIssue{
 				Issue:        fmt.Sprintf("%s 점수가 유효 범위를 벗어남", mt.String()),
@@ -585,6 +625,9 @@ func detectBiasIndicators(_ ScoreResult, scores map[metadata.MetadataType]shared
 
 	// 점수 극단값 편향
 	for mt, score := range scores {
+		if score.IsMissing() {
+			continue
+		}
 		if score.ToFloat() > 95.0 || score.ToFloat() < 20.0 {
 			indicators = append(indicators, BiasIndicator{
 				BiasType:        "극단값 데이터 편향",
@@ -707,7 +750,7 @@ func generateRecommendedActions(result ScoreResult, scores map[metadata.Metadata
 
 	// 특정 요소 기반 추천
 	for mt, score := range scores {
-		if score.ToFloat() < 60 {
+		if !score.IsMissing() && score.ToFloat() < 60 {
 			switch mt {
 			case metadata.SchoolDistrict:
 				actions = append(actions, RecommendedAction{
@@ -732,6 +775,17 @@ func generateRecommendedActions(result ScoreResult, scores map[metadata.Metadata
 	return actions
 }
 
+// formatGroupContributions lists group contributions, indenting subgroups under their parent.
+func formatGroupContributions(groups []GroupContribution, indent string) string {
+	output := ""
+	for _, g := range groups {
+		output += fmt.Sprintf("%s• %s: %.1f점 기여 (%.0f%%, 가중치 %.0f%%, 평균 %.1f점)\n",
+			indent, g.Group, g.Contribution, g.Share, g.Weight*100, g.AverageScore)
+		output += formatGroupContributions(g.Groups, indent+"  ")
+	}
+	return output
+}
+
 // FormatTransparencyDashboard formats the transparency dashboard as a readable string.
 func FormatTransparencyDashboard(dashboard TransparencyDashboard) string {
 	output := "🔍 투명성 평가 대시보드\n"
@@ -740,7 +794,20 @@ func FormatTransparencyDashboard(dashboard TransparencyDashboard) string {
 	// 점수 분석 섹션
 	output += "📊 점수 분석\n"
 	output += fmt.Sprintf("총점: %.1f점\n", dashboard.ScoreBreakdown.TotalScore)
-	output += fmt.Sprintf("백분위수: 상위 %.0f%%\n", dashboard.ScoreDistribution.ScorePercentile)
+	distribution := dashboard.ScoreDistribution
+	if distribution.CohortSize > 0 {
+		output += fmt.Sprintf("백분위수: %.0f (상위 %.0f%%, 비교 대상 %d개)\n",
+			distribution.ScorePercentile, 100-distribution.ScorePercentile, distribution.CohortSize)
+		output += fmt.Sprintf("백분위수 구간: %.0f - %.0f (%.0f%% 신뢰도)\n",
+			distribution.PercentileInterval.LowerBound, distribution.PercentileInterval.UpperBound,
+			distribution.PercentileInterval.Confidence)
+		output += fmt.Sprintf("비교 대상 분포: 평균 %.1f점, 표준편차 %.1f점 (%.1f - %.1f점)\n",
+			distribution.ScoreRange.Average, distribution.ScoreRange.StdDev,
+			distribution.ScoreRange.Minimum, distribution.ScoreRange.Maximum)
+	} else {
+		output += fmt.Sprintf("백분위수: %.0f (상위 약 %.0f%%, 추정값)\n",
+			distribution.ScorePercentile, 100-distribution.ScorePercentile)
+	}
 	output += fmt.Sprintf("신뢰 구간: %.1f - %.1f점 (%.0f%% 신뢰도)\n\n",
 		dashboard.ScoreDistribution.ConfidenceInterval.LowerBound,
 		dashboard.ScoreDistribution.ConfidenceInterval.UpperBound,
@@ -750,12 +817,21 @@ func FormatTransparencyDashboard(dashboard TransparencyDashboard) string {
 	output += "🎯 주요 기여 요소:\n"
 	for name, component := range dashboard.ScoreBreakdown.ComponentScores {
 		if component.ImpactLevel == "High" {
-			output += fmt.Sprintf("  • %s: %.1f점 기여 (영향도: %s)\n",
-				name, component.Contribution, component.ImpactLevel)
+			output += fmt.Sprintf("  • %s: %.1f점 기여 (영향도: %s", name, component.Contribution, component.ImpactLevel)
+			if component.NormalizedValue != component.RawValue {
+				output += fmt.Sprintf(", 원점수 %.1f → 효용 %.1f", component.RawValue, component.NormalizedValue)
+			}
+			output += ")\n"
 		}
 	}
 	output += "\n"
 
+	if len(dashboard.ScoreBreakdown.GroupContributions) > 0 {
+		output += "🧩 그룹별 기여도:\n"
+		output += formatGroupContributions(dashboard.ScoreBreakdown.GroupContributions, "  ")
+		output += "\n"
+	}
+
 	// 전략 비교
 	output += "🔄 전략 비교:\n"
 	for strategy, score := range dashboard.ScoreBreakdown.StrategyImpact.AlternativeResults {
@@ -771,6 +847,18 @@ func FormatTransparencyDashboard(dashboard TransparencyDashboard) string {
 	}
 	output += "\n"
 
+	// 가중치 민감도
+	sensitivity := dashboard.SensitivityAnalysis
+	if len(sensitivity.MostSensitiveFactors) > 0 {
+		output += fmt.Sprintf("🎚️ 가중치 민감도 (안정성 지수: %.0f%%, 견고성: %s):\n",
+			sensitivity.StabilityIndex, sensitivity.RobustnessLevel)
+		for _, factor := range sensitivity.MostSensitiveFactors {
+			output += fmt.Sprintf("  • %s: 가중치 10%%p당 %+.1f점\n", factor.FactorName, factor.Sensitivity*0.1)
+		}
+		output += fmt.Sprintf("  ±5%%p 변경 시 점수 범위: %.1f - %.1f점\n\n",
+			sensitivity.VariationRange.Minimum, sensitivity.VariationRange.Maximum)
+	}
+
 	// 불확실성 요인
 	if len(dashboard.UncertaintyFactors) > 0 {
 		output += "⚠️ 주요 불확실성 요인:\n"
@@ -798,5 +886,12 @@ func FormatTransparencyDashboard(dashboard TransparencyDashboard) string {
 		}
 	}
 
+	if dashboard.PipelineTrace != nil {
+		output += "\n" + FormatPipelineTrace(dashboard.PipelineTrace)
+	}
+	if dashboard.Counterfactual != nil {
+		output += "\n" + FormatCounterfactual(dashboard.Counterfactual)
+	}
+
 	return output
 }
@@ -0,0 +1,294 @@
+package scoring
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"container/heap"
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+)
+
+// DefaultProgressInterval is the number of scored apartments between progress reports.
+const DefaultProgressInterval = 1000
+
+// BatchOptions configures RankBatch and RankStream.
+type BatchOptions struct {
+	Workers        int                // 동시에 점수를 계산할 고루틴 수 (0이면 GOMAXPROCS)
+	TopK           int                // 유지할 상위 순위 수 (0이면 전체를 정렬)
+	MissingPolicy  MissingValuePolicy // 결측값 처리 정책 (기본값: MissingRenormalize, 중앙값 대체는 지원 안 함)
+	ValueFunctions ValueFunctions     // 집계 전 적용할 요소별 효용 곡선
+	// OnProgress, when set, is called from a single goroutine every ProgressInterval scored
+	// apartments and once more when scoring ends.
+	OnProgress       func(BatchProgress)
+	ProgressInterval int // 0이면 DefaultProgressInterval
+}
+
+// BatchProgress reports how far a batch ranking has come.
+type BatchProgress struct {
+	Scored int     `json:"scored"`
+	Total  int     `json:"total"` // 전체 아파트 수 (스트림이면 0)
+	Best   float64 `json:"best"`  // 지금까지의 최고 점수
+}
+
+// batchEntry is a scored apartment; seq keeps ties in input order.
+type batchEntry struct {
+	seq    int
+	apt    ApartmentData
+	result ScoreResult
+	err    error
+}
+
+// worse reports whether a ranks below b.
+func (a batchEntry) worse(b batchEntry) bool {
+	if a.result.TotalScore != b.result.TotalScore {
+		return a.result.TotalScore < b.result.TotalScore
+	}
+	return a.seq > b.seq
+}
+
+// topHeap is a min-heap of the best entries seen so far; its root is the worst one kept.
+type topHeap []batchEntry
+
+func (h topHeap) Len() int            { return len(h) }
+func (h topHeap) Less(i, j int) bool  { return h[i].worse(h[j]) }
+func (h topHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *topHeap) Push(x interface{}) { *h = append(*h, x.(batchEntry)) }
+func (h *topHeap) Pop() interface{} {
+	old := *h
+	last := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return last
+}
+
+// RankBatch ranks apartments concurrently with a pool of workers. Unlike CalculateRankings it
+// keeps only the TopK best results in a heap instead of sorting all of them. Cohort strategies
+// such as TOPSIS need every apartment at once and are not supported. It stops and returns
+// ctx.Err() when ctx is cancelled.
+func RankBatch(ctx context.Context, apartments []ApartmentData, weights map[metadata.MetadataType]shared.Weight,
+	strategy StrategyType, opts BatchOptions) (*RankingsSummary, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	source := make(chan ApartmentData)
+	go func() {
+		defer close(source)
+		for _, apt := range apartments {
+			select {
+			case source <- apt:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return rankStream(ctx, source, len(apartments), weights, strategy, opts)
+}
+
+// RankStream is RankBatch over apartments received from source until it is closed, so the
+// input never has to be held in memory. The producer should stop sending once ctx is done,
+// since RankStream stops receiving when it returns early.
+func RankStream(ctx context.Context, source <-chan ApartmentData, weights map[metadata.MetadataType]shared.Weight,
+	strategy StrategyType, opts BatchOptions) (*RankingsSummary, error) {
+	return rankStream(ctx, source, 0, weights, strategy, opts)
+}
+
+// validateBatch checks the options and returns the weight array to score with.
+func validateBatch(weights map[metadata.MetadataType]shared.Weight, strategy StrategyType, opts BatchOptions) (shared.WeightArray, error) {
+	var weightArray shared.WeightArray
+	if opts.Workers < 0 {
+		return weightArray, &ValidationError{Field: "workers", Message: "작업자 수는 0 이상이어야 합니다"}
+	}
+	if opts.TopK < 0 {
+		return weightArray, &ValidationError{Field: "top_k", Message: "상위 순위 수는 0 이상이어야 합니다"}
+	}
+	if opts.ProgressInterval < 0 {
+		return weightArray, &ValidationError{Field: "progress_interval", Message: "진행 상황 보고 간격은 0 이상이어야 합니다"}
+	}
+	impl, exists := LookupStrategy(strategy)
+	if !exists {
+		return weightArray, fmt.Errorf(errUnsupportedStrategy, strategy)
+	}
+	if _, isCohort := impl.(CohortStrategy); isCohort {
+		return weightArray, &ValidationError{Field: "strategy",
+			Message: fmt.Sprintf("코호트 전략(%s)은 전체 아파트가 필요해 일괄 순위 계산에서 사용할 수 없습니다", strategy)}
+	}
+	if err := opts.MissingPolicy.Validate(); err != nil {
+		return weightArray, err
+	}
+	if opts.MissingPolicy == MissingImputeMedian {
+		return weightArray, &ValidationError{Field: "missing_policy",
+			Message: "코호트 중앙값 대체는 전체 아파트가 필요해 일괄 순위 계산에서 사용할 수 없습니다"}
+	}
+	if err := opts.ValueFunctions.Validate(); err != nil {
+		return weightArray, err
+	}
+	_, weightArray, err := toArrays(nil, weights)
+	if err != nil {
+		return weightArray, err
+	}
+	return weightArray, validateStrategyInputsArray(shared.ScoreArray{}, weightArray)
+}
+
+func rankStream(ctx context.Context, source <-chan ApartmentData, total int, weights map[metadata.MetadataType]shared.Weight,
+	strategy StrategyType, opts BatchOptions) (*RankingsSummary, error) {
+	weightArray, err := validateBatch(weights, strategy, opts)
+	if err != nil {
+		return nil, fmt.Errorf("입력 검증 실패: %w", err)
+	}
+	workers := opts.Workers
+	if workers == 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	interval := opts.ProgressInterval
+	if interval == 0 {
+		interval = DefaultProgressInterval
+	}
+
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// 입력에 순번을 매겨 작업자에게 분배
+	jobs := make(chan batchEntry, workers)
+	go func() {
+		defer close(jobs)
+		for seq := 0; ; seq++ {
+			select {
+			case apt, ok := <-source:
+				if !ok {
+					return
+				}
+				select {
+				case jobs <- batchEntry{seq: seq, apt: apt}:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	results := make(chan batchEntry, workers)
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range jobs {
+				entry.result, entry.err = scoreBatchEntry(entry.apt, weightArray, strategy, opts)
+				select {
+				case results <- entry:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	// 결과 수집: 상위 K개만 힙에 유지하고 점수 범위는 누적 통계로 계산
+	var (
+		top      topHeap
+		all      []batchEntry
+		firstErr error
+		progress = BatchProgress{Total: total}
+		reported = -1
+		sum      float64
+		minScore = 100.0
+		maxScore = 0.0
+	)
+	for entry := range results {
+		if firstErr != nil {
+			continue
+		}
+		if entry.err != nil {
+			firstErr = entry.err
+			cancel()
+			continue
+		}
+		score := entry.result.TotalScore
+		sum += score
+		if score < minScore {
+			minScore = score
+		}
+		if score > maxScore {
+			maxScore = score
+		}
+		if progress.Scored == 0 || score > progress.Best {
+			progress.Best = score
+		}
+		switch {
+		case opts.TopK == 0:
+			all = append(all, entry)
+		case top.Len() < opts.TopK:
+			heap.Push(&top, entry)
+		case top[0].worse(entry):
+			top[0] = entry
+			heap.Fix(&top, 0)
+		}
+		progress.Scored++
+		if opts.OnProgress != nil && progress.Scored%interval == 0 {
+			opts.OnProgress(progress)
+			reported = progress.Scored
+		}
+	}
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := parent.Err(); err != nil {
+		return nil, err
+	}
+	if opts.OnProgress != nil && reported != progress.Scored {
+		opts.OnProgress(progress)
+	}
+	if progress.Scored == 0 {
+		return nil, errors.New(errNoApartments)
+	}
+
+	if opts.TopK != 0 {
+		all = top
+	}
+	sort.Slice(all, func(i, j int) bool { return all[j].worse(all[i]) })
+	summary := &RankingsSummary{
+		TotalApartments: progress.Scored,
+		Strategy:        strategy,
+		TopRanked:       make([]RankingResult, len(all)),
+	}
+	for i, entry := range all {
+		ranking := RankingResult{
+			Apartment:  entry.apt,
+			Rank:       i + 1,
+			Score:      entry.result.TotalScore,
+			Method:     entry.result.Method,
+			Weights:    entry.result.Weights,
+			Percentile: 100.0,
+		}
+		if maxScore > minScore {
+			ranking.Percentile = (ranking.Score - minScore) / (maxScore - minScore) * 100.0
+		}
+		summary.TopRanked[i] = ranking
+	}
+	summary.ScoreRange.Min = minScore
+	summary.ScoreRange.Max = maxScore
+	summary.ScoreRange.Avg = sum / float64(progress.Scored)
+	return summary, nil
+}
+
+// scoreBatchEntry scores one apartment of a batch.
+func scoreBatchEntry(apt ApartmentData, weights shared.WeightArray, strategy StrategyType, opts BatchOptions) (ScoreResult, error) {
+	scores, _, err := toArrays(opts.ValueFunctions.Apply(apt.Scores), nil)
+	if err != nil {
+		return ScoreResult{}, fmt.Errorf(errCalculationFailed, apt.ID, err)
+	}
+	result, err := calculateArray(scores, weights, strategy, opts.MissingPolicy)
+	if err != nil {
+		return ScoreResult{}, fmt.Errorf(errCalculationFailed, apt.ID, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,157 @@
+package scoring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+)
+
+// batchApartments generates n apartments with pseudo-random scores.
+func batchApartments(n int) []ApartmentData {
+	rng := rand.New(rand.NewSource(42))
+	apartments := make([]ApartmentData, n)
+	for i := range apartments {
+		scores := make(map[metadata.MetadataType]shared.ScoreValue)
+		for _, mt := range metadata.All() {
+			scores[mt] = shared.ScoreValue(rng.Intn(int(shared.ScoreValueFromFloat(100)) + 1))
+		}
+		apartments[i] = ApartmentData{ID: fmt.Sprintf("apt-%03d", i), Scores: scores}
+	}
+	return apartments
+}
+
+func TestRankBatchMatchesRankings(t *testing.T) {
+	apartments := batchApartments(200)
+	apartments[7].Scores[metadata.Parking] = shared.MissingScore
+	weights := GetScenarioWeights(ScenarioFamilyFriendly)
+	want, err := CalculateRankings(apartments, weights, StrategyWeightedSum)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, topK := range []int{0, 5} {
+		got, err := RankBatch(context.Background(), apartments, weights, StrategyWeightedSum, BatchOptions{Workers: 4, TopK: topK})
+		if err != nil {
+			t.Fatal(err)
+		}
+		// 평균은 합산 순서에 따라 부동소수점 오차가 생김
+		if got.TotalApartments != len(apartments) || got.ScoreRange.Min != want.ScoreRange.Min ||
+			got.ScoreRange.Max != want.ScoreRange.Max || math.Abs(got.ScoreRange.Avg-want.ScoreRange.Avg) > 1e-9 {
+			t.Errorf("top %d: total %d, range %+v, want %+v", topK, got.TotalApartments, got.ScoreRange, want.ScoreRange)
+		}
+		wantLen := topK
+		if wantLen == 0 {
+			wantLen = len(apartments)
+		}
+		if len(got.TopRanked) != wantLen {
+			t.Fatalf("top %d: %d rankings", topK, len(got.TopRanked))
+		}
+		for i, ranking := range got.TopRanked {
+			expected := want.TopRanked[i]
+			if ranking.Apartment.ID != expected.Apartment.ID || ranking.Rank != expected.Rank ||
+				ranking.Score != expected.Score || ranking.Percentile != expected.Percentile {
+				t.Errorf("top %d rank %d: %s %.3f (%.1f%%), want %s %.3f (%.1f%%)", topK, i+1,
+					ranking.Apartment.ID, ranking.Score, ranking.Percentile, expected.Apartment.ID, expected.Score, expected.Percentile)
+			}
+		}
+	}
+
+	// 동점이면 입력 순서대로
+	twins := []ApartmentData{{ID: "a", Scores: uniformScores(70)}, {ID: "b", Scores: uniformScores(80)}, {ID: "c", Scores: uniformScores(70)}}
+	got, err := RankBatch(context.Background(), twins, weights, StrategyWeightedSum, BatchOptions{TopK: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got.TopRanked) != 2 || got.TopRanked[0].Apartment.ID != "b" || got.TopRanked[1].Apartment.ID != "a" {
+		t.Errorf("ties: %+v", got.TopRanked)
+	}
+}
+
+func TestRankBatchProgress(t *testing.T) {
+	var reports []BatchProgress
+	opts := BatchOptions{TopK: 1, ProgressInterval: 100, OnProgress: func(p BatchProgress) { reports = append(reports, p) }}
+	summary, err := RankBatch(context.Background(), batchApartments(250), GetScenarioWeights(ScenarioBalanced), StrategyWeightedSum, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 3 || reports[0].Scored != 100 || reports[2].Scored != 250 || reports[2].Total != 250 {
+		t.Fatalf("reports: %+v", reports)
+	}
+	if reports[2].Best != summary.TopRanked[0].Score || reports[0].Best > reports[1].Best {
+		t.Errorf("best: %+v, top score %.3f", reports, summary.TopRanked[0].Score)
+	}
+}
+
+func TestRankStream(t *testing.T) {
+	source := make(chan ApartmentData)
+	go func() {
+		defer close(source)
+		for _, apt := range batchApartments(30) {
+			source <- apt
+		}
+	}()
+	summary, err := RankStream(context.Background(), source, GetScenarioWeights(ScenarioBalanced), StrategyGeometricMean, BatchOptions{TopK: 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.TotalApartments != 30 || len(summary.TopRanked) != 3 || summary.TopRanked[0].Method != StrategyGeometricMean {
+		t.Errorf("summary: %+v", summary)
+	}
+
+	empty := make(chan ApartmentData)
+	close(empty)
+	if _, err := RankStream(context.Background(), empty, GetScenarioWeights(ScenarioBalanced), StrategyWeightedSum, BatchOptions{}); err == nil {
+		t.Error("empty stream did not fail")
+	}
+}
+
+func TestRankBatchCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	opts := BatchOptions{Workers: 2, ProgressInterval: 10, OnProgress: func(BatchProgress) {
+		calls++
+		cancel()
+	}}
+	_, err := RankBatch(ctx, batchApartments(5000), GetScenarioWeights(ScenarioBalanced), StrategyWeightedSum, opts)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if calls == 0 {
+		t.Error("progress was never reported")
+	}
+}
+
+func TestRankBatchErrors(t *testing.T) {
+	weights := GetScenarioWeights(ScenarioBalanced)
+	apartments := batchApartments(10)
+	tests := map[string]struct {
+		strategy StrategyType
+		opts     BatchOptions
+		field    string
+	}{
+		"cohort strategy": {StrategyTOPSIS, BatchOptions{}, "strategy"},
+		"median impute":   {StrategyWeightedSum, BatchOptions{MissingPolicy: MissingImputeMedian}, "missing_policy"},
+		"negative top k":  {StrategyWeightedSum, BatchOptions{TopK: -1}, "top_k"},
+		"negative worker": {StrategyWeightedSum, BatchOptions{Workers: -1}, "workers"},
+	}
+	for name, tt := range tests {
+		var validation *ValidationError
+		_, err := RankBatch(context.Background(), apartments, weights, tt.strategy, tt.opts)
+		if !errors.As(err, &validation) || validation.Field != tt.field {
+			t.Errorf("%s: %v, want ValidationError on %s", name, err, tt.field)
+		}
+	}
+
+	apartments[4].Scores[metadata.Parking] = shared.ScoreValueFromFloat(150)
+	_, err := RankBatch(context.Background(), apartments, weights, StrategyWeightedSum, BatchOptions{})
+	if err == nil || !strings.Contains(err.Error(), apartments[4].ID) {
+		t.Errorf("invalid score: %v", err)
+	}
+}
@@ -0,0 +1,244 @@
+package scoring
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// comparisonTolerance is the score difference below which two apartments are tied.
+const comparisonTolerance = 1e-9
+
+// FactorComparison is one factor's part in the difference between two apartments.
+type FactorComparison struct {
+	Factor metadata.MetadataType `json:"factor"`
+	ScoreA shared.ScoreValue     `json:"score_a"` // shared.MissingScore면 데이터 없음
+	ScoreB shared.ScoreValue     `json:"score_b"`
+	// Delta is the factor's contribution to ScoreA - ScoreB in total points: the average score
+	// change of swapping the factor's value between the apartments. For the weighted sum without
+	// missing scores it is exactly weight × (a - b).
+	Delta  float64 `json:"delta"`
+	Favors string  `json:"favors,omitempty"` // 유리한 아파트 ID (동률이면 비어 있음)
+}
+
+// ComparisonOutcome is the result of a comparison under an alternative scenario or strategy.
+type ComparisonOutcome struct {
+	Scenario ScoringScenario `json:"scenario,omitempty"`
+	Strategy StrategyType    `json:"strategy"`
+	ScoreA   float64         `json:"score_a"`
+	ScoreB   float64         `json:"score_b"`
+	Winner   string          `json:"winner,omitempty"` // 동률이면 비어 있음
+	Changed  bool            `json:"changed"`          // 원래 결과와 승자가 다른지 여부
+}
+
+// ComparisonResult explains why one apartment scores higher than another under a profile.
+type ComparisonResult struct {
+	A          string       `json:"a"`
+	B          string       `json:"b"`
+	Strategy   StrategyType `json:"strategy"`
+	ScoreA     float64      `json:"score_a"`
+	ScoreB     float64      `json:"score_b"`
+	Difference float64      `json:"difference"`       // ScoreA - ScoreB
+	Winner     string       `json:"winner,omitempty"` // 동률이면 비어 있음
+	// Factors are ordered by the size of their contribution delta, largest first.
+	Factors []FactorComparison `json:"factors"`
+	// Decisive are the fewest factors favoring the winner whose deltas together cover the
+	// difference: without their advantage the winner would not be ahead.
+	Decisive []metadata.MetadataType `json:"decisive"`
+	// Interaction is the part of the difference not attributed to single factors, which is
+	// non-zero only for non-additive strategies.
+	Interaction  float64             `json:"interaction"`
+	Alternatives []ComparisonOutcome `json:"alternatives"`
+	Robust       bool                `json:"robust"` // 모든 대안에서 승자가 같은지 여부
+}
+
+// comparePair scores two apartments together, so cohort strategies compare them to each other.
+func comparePair(a, b ApartmentData, weights map[metadata.MetadataType]shared.Weight,
+	strategy StrategyType, values ValueFunctions) (float64, float64, error) {
+	results, err := scoreCohort(values.applyAll([]ApartmentData{a, b}), weights, strategy, MissingRenormalize)
+	if err != nil {
+		return 0, 0, err
+	}
+	return results[0].TotalScore, results[1].TotalScore, nil
+}
+
+// winner returns the ID of the higher scoring apartment, or "" for a tie.
+func winner(a, b ApartmentData, scoreA, scoreB float64) string {
+	switch {
+	case scoreA-scoreB > comparisonTolerance:
+		return a.ID
+	case scoreB-scoreA > comparisonTolerance:
+		return b.ID
+	}
+	return ""
+}
+
+// swapFactor returns a copy of scores with the factor's value taken from other.
+func swapFactor(scores, other map[metadata.MetadataType]shared.ScoreValue, mt metadata.MetadataType) map[metadata.MetadataType]shared.ScoreValue {
+	swapped := copyScores(scores)
+	swapped[mt] = other[mt]
+	return swapped
+}
+
+// CompareApartments explains the difference between two apartments under the profile's weights,
+// strategy and value functions, and checks whether the winner holds under the other scenarios
+// and strategies. Alternatives that cannot be calculated are left out.
+func CompareApartments(a, b ApartmentData, p ScoringProfile) (*ComparisonResult, error) {
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf(errInvalidProfile, p.Name, err)
+	}
+	if a.ID == b.ID {
+		return nil, &ValidationError{Field: "b", Message: fmt.Sprintf("같은 아파트끼리는 비교할 수 없습니다: %s", a.ID)}
+	}
+	weights, strategy := p.EffectiveWeights(), p.Strategy()
+	scoreA, scoreB, err := comparePair(a, b, weights, strategy, p.ValueFunctions)
+	if err != nil {
+		return nil, err
+	}
+	result := &ComparisonResult{
+		A: a.ID, B: b.ID, Strategy: strategy,
+		ScoreA: scoreA, ScoreB: scoreB, Difference: scoreA - scoreB,
+		Winner:       winner(a, b, scoreA, scoreB),
+		Factors:      []FactorComparison{},
+		Decisive:     []metadata.MetadataType{},
+		Alternatives: []ComparisonOutcome{},
+		Robust:       true,
+	}
+
+	// 요소 값을 서로 바꿨을 때의 점수 변화를 양쪽에서 구해 평균
+	explained := 0.0
+	for _, mt := range metadata.All() {
+		valueA, valueB := a.Scores[mt], b.Scores[mt]
+		if valueA == valueB || weights[mt] == 0 {
+			continue
+		}
+		swappedA := ApartmentData{ID: a.ID, Scores: swapFactor(a.Scores, b.Scores, mt)}
+		swappedB := ApartmentData{ID: b.ID, Scores: swapFactor(b.Scores, a.Scores, mt)}
+		withBsA, _, err := comparePair(swappedA, b, weights, strategy, p.ValueFunctions)
+		if err != nil {
+			return nil, err
+		}
+		_, withAsB, err := comparePair(a, swappedB, weights, strategy, p.ValueFunctions)
+		if err != nil {
+			return nil, err
+		}
+		factor := FactorComparison{Factor: mt, ScoreA: valueA, ScoreB: valueB,
+			Delta: ((scoreA - withBsA) + (withAsB - scoreB)) / 2}
+		if factor.Delta > comparisonTolerance {
+			factor.Favors = a.ID
+		} else if factor.Delta < -comparisonTolerance {
+			factor.Favors = b.ID
+		}
+		explained += factor.Delta
+		result.Factors = append(result.Factors, factor)
+	}
+	sort.SliceStable(result.Factors, func(i, j int) bool {
+		return math.Abs(result.Factors[i].Delta) > math.Abs(result.Factors[j].Delta)
+	})
+	result.Interaction = result.Difference - explained
+
+	if result.Winner != "" {
+		covered := 0.0
+		for _, factor := range result.Factors {
+			if covered >= math.Abs(result.Difference) {
+				break
+			}
+			if factor.Favors == result.Winner {
+				result.Decisive = append(result.Decisive, factor.Factor)
+				covered += math.Abs(factor.Delta)
+			}
+		}
+	}
+
+	// 다른 시나리오(현재 전략)와 다른 전략(현재 가중치)에서의 승자
+	alternative := func(scenario ScoringScenario, weights map[metadata.MetadataType]shared.Weight, strategy StrategyType) {
+		scoreA, scoreB, err := comparePair(a, b, weights, strategy, p.ValueFunctions)
+		if err != nil {
+			return
+		}
+		outcome := ComparisonOutcome{Scenario: scenario, Strategy: strategy, ScoreA: scoreA, ScoreB: scoreB,
+			Winner: winner(a, b, scoreA, scoreB)}
+		outcome.Changed = outcome.Winner != result.Winner
+		if outcome.Changed {
+			result.Robust = false
+		}
+		result.Alternatives = append(result.Alternatives, outcome)
+	}
+	for _, scenario := range GetAllScenarios() {
+		if len(p.Weights) == 0 && scenario == p.BaseScenario() {
+			continue
+		}
+		alternative(scenario, GetScenarioWeights(scenario), strategy)
+	}
+	for _, s := range GetAvailableStrategies() {
+		if s != strategy {
+			alternative("", weights, s)
+		}
+	}
+	return result, nil
+}
+
+// FormatComparison formats a comparison result for display.
+func FormatComparison(c *ComparisonResult) string {
+	output := fmt.Sprintf("⚖️ %s vs %s (%s)\n", c.A, c.B, c.Strategy)
+	output += "━━━━━━━━━━━━━━━━━━━━━━━━━\n"
+	output += fmt.Sprintf("%s: %.1f점 / %s: %.1f점\n", c.A, c.ScoreA, c.B, c.ScoreB)
+	if c.Winner == "" {
+		output += "두 아파트의 점수가 같습니다\n"
+	} else {
+		output += fmt.Sprintf("%s가 %.1f점 더 높습니다\n", c.Winner, math.Abs(c.Difference))
+	}
+
+	if len(c.Decisive) > 0 {
+		output += "\n🎯 결정 요소:"
+		for i, mt := range c.Decisive {
+			if i > 0 {
+				output += ","
+			}
+			output += " " + mt.KoreanName()
+		}
+		output += "\n"
+	}
+
+	output += "\n📊 요소별 기여도 차이 (A - B):\n"
+	for _, f := range c.Factors {
+		output += fmt.Sprintf("  %-20s: %s vs %s → %+.2f점", f.Factor.KoreanName(), formatScoreValue(f.ScoreA), formatScoreValue(f.ScoreB), f.Delta)
+		if f.Favors != "" {
+			output += fmt.Sprintf(" (%s 유리)", f.Favors)
+		}
+		output += "\n"
+	}
+	if math.Abs(c.Interaction) >= 0.05 {
+		output += fmt.Sprintf("  %-20s: %+.2f점\n", "요소 간 상호작용", c.Interaction)
+	}
+
+	output += "\n🔄 다른 시나리오와 전략에서의 결과:\n"
+	for _, o := range c.Alternatives {
+		name := string(o.Strategy)
+		if o.Scenario != "" {
+			name = string(o.Scenario)
+		}
+		outcome := o.Winner
+		if outcome == "" {
+			outcome = "동률"
+		}
+		output += fmt.Sprintf("  • %-16s: %.1f vs %.1f → %s", name, o.ScoreA, o.ScoreB, outcome)
+		if o.Changed {
+			output += " (결과 바뀜)"
+		}
+		output += "\n"
+	}
+	if c.Robust {
+		output += "모든 대안에서 같은 결과입니다\n"
+	}
+	return output
+}
+
+func formatScoreValue(score shared.ScoreValue) string {
+	if score.IsMissing() {
+		return "데이터 없음"
+	}
+	return fmt.Sprintf("%.1f", score.ToFloat())
+}
@@ -0,0 +1,107 @@
+package scoring
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+)
+
+func TestCompareApartmentsWeightedSum(t *testing.T) {
+	a := ApartmentData{ID: "station", Scores: uniformScores(70)}
+	a.Scores[metadata.DistanceToStation] = shared.ScoreValueFromFloat(100)
+	a.Scores[metadata.Parking] = shared.ScoreValueFromFloat(60)
+	b := ApartmentData{ID: "school", Scores: uniformScores(70)}
+	b.Scores[metadata.SchoolDistrict] = shared.ScoreValueFromFloat(80)
+
+	p := ScoringProfile{Scenario: ScenarioTransportation}
+	c, err := CompareApartments(a, b, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Winner != "station" || math.Abs(c.Difference-(c.ScoreA-c.ScoreB)) > 1e-9 {
+		t.Fatalf("unexpected result: %+v", c)
+	}
+	weights := p.EffectiveWeights()
+	sum := 0.0
+	for _, f := range c.Factors {
+		sum += f.Delta
+		if f.Factor == metadata.DistanceToStation {
+			if want := weights[f.Factor].ToFloat() * 30; math.Abs(f.Delta-want) > 1e-9 || f.Favors != "station" {
+				t.Errorf("station delta = %.4f (%s), want %.4f", f.Delta, f.Favors, want)
+			}
+		}
+	}
+	if math.Abs(c.Difference-sum) > 1e-9 || math.Abs(c.Interaction) > 1e-9 {
+		t.Errorf("deltas sum to %.3f, difference %.3f", sum, c.Difference)
+	}
+	if len(c.Factors) == 0 || c.Factors[0].Factor != metadata.DistanceToStation {
+		t.Errorf("largest delta should come first: %+v", c.Factors)
+	}
+	if len(c.Decisive) != 1 || c.Decisive[0] != metadata.DistanceToStation {
+		t.Errorf("decisive = %v, want [%s]", c.Decisive, metadata.DistanceToStation)
+	}
+
+	b.Scores[metadata.CrimeRate] = shared.MissingScore
+	if c, err = CompareApartments(a, b, p); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range c.Factors {
+		if f.Factor == metadata.CrimeRate && !f.ScoreB.IsMissing() {
+			t.Errorf("missing crime rate of b: %+v", f)
+		}
+	}
+}
+
+func TestCompareApartmentsAlternatives(t *testing.T) {
+	a := ApartmentData{ID: "station", Scores: uniformScores(70)}
+	a.Scores[metadata.DistanceToStation] = shared.ScoreValueFromFloat(95)
+	b := ApartmentData{ID: "school", Scores: uniformScores(70)}
+	b.Scores[metadata.SchoolDistrict] = shared.ScoreValueFromFloat(95)
+
+	c, err := CompareApartments(a, b, ScoringProfile{Scenario: ScenarioEducation, Method: StrategyGeometricMean})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Winner != "school" || c.Robust {
+		t.Fatalf("winner %s, robust %v", c.Winner, c.Robust)
+	}
+	var transport *ComparisonOutcome
+	for i, o := range c.Alternatives {
+		if o.Scenario == ScenarioEducation {
+			t.Error("active scenario listed as an alternative")
+		}
+		if o.Scenario == ScenarioTransportation {
+			transport = &c.Alternatives[i]
+		}
+	}
+	if transport == nil || transport.Winner != "station" || !transport.Changed {
+		t.Errorf("transportation outcome: %+v", transport)
+	}
+	text := FormatComparison(c)
+	for _, want := range []string{"결정 요소", "결과 바뀜", metadata.SchoolDistrict.KoreanName()} {
+		if !strings.Contains(text, want) {
+			t.Errorf("formatted comparison lacks %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestCompareApartmentsTie(t *testing.T) {
+	a := ApartmentData{ID: "a", Scores: uniformScores(70)}
+	b := ApartmentData{ID: "b", Scores: uniformScores(70)}
+	c, err := CompareApartments(a, b, ScoringProfile{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.Winner != "" || len(c.Factors) != 0 || len(c.Decisive) != 0 || !c.Robust {
+		t.Errorf("tie: %+v", c)
+	}
+
+	var validation *ValidationError
+	if _, err := CompareApartments(a, a, ScoringProfile{}); !errors.As(err, &validation) {
+		t.Errorf("same apartment: %v", err)
+	}
+}
@@ -0,0 +1,188 @@
+package scoring
+
+import (
+	"apart_score/pkg/expr"
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"fmt"
+	"strings"
+)
+
+// RequiredFeatureThreshold is the score a required yes/no feature must reach to count as present.
+const RequiredFeatureThreshold = shared.ScoreValue(50 * shared.ScoreScale)
+
+// ConstraintKind identifies the type of a constraint.
+type ConstraintKind string
+
+const (
+	ConstraintMinScore         ConstraintKind = "min_score"
+	ConstraintRequiredFeature  ConstraintKind = "required_feature"
+	ConstraintExcludedLocation ConstraintKind = "excluded_location"
+	ConstraintRule             ConstraintKind = "rule"
+)
+
+// Constraints declares must-have conditions that eliminate apartments before they are scored.
+type Constraints struct {
+	MinScores         map[metadata.MetadataType]shared.ScoreValue `json:"min_scores,omitempty"`         // 요소별 최소 점수
+	RequiredFeatures  []metadata.MetadataType                     `json:"required_features,omitempty"`  // 반드시 있어야 하는 요소 (예: 엘리베이터)
+	ExcludedLocations []string                                    `json:"excluded_locations,omitempty"` // 제외할 지역 (부분 일치)
+	// Rules are condition expressions over raw scores that must hold, such as
+	// "SchoolDistrict >= 80 or TransportationAccess >= 90".
+	Rules []*expr.Expr `json:"rules,omitempty"`
+}
+
+// ConstraintViolation describes why an apartment failed a constraint.
+type ConstraintViolation struct {
+	Kind     ConstraintKind        `json:"kind"`
+	Factor   metadata.MetadataType `json:"factor,omitempty"`
+	Location string                `json:"location,omitempty"`
+	Rule     string                `json:"rule,omitempty"`
+	Required float64               `json:"required,omitempty"` // 요구 점수
+	Actual   float64               `json:"actual,omitempty"`   // 실제 점수
+	// DominatedBy lists the dominating apartments of a ConstraintDominated violation.
+	DominatedBy []string `json:"dominated_by,omitempty"`
+	Message     string   `json:"message"`
+}
+
+// Elimination records an apartment removed before ranking and every constraint it violated.
+type Elimination struct {
+	Apartment  ApartmentData         `json:"apartment"`
+	Violations []ConstraintViolation `json:"violations"`
+}
+
+// IsEmpty reports whether no constraint is configured.
+func (c Constraints) IsEmpty() bool {
+	return len(c.MinScores) == 0 && len(c.RequiredFeatures) == 0 && len(c.ExcludedLocations) == 0 && len(c.Rules) == 0
+}
+
+// Validate checks that every constraint references a registered factor and a valid score.
+func (c Constraints) Validate() error {
+	for mt, minScore := range c.MinScores {
+		if !mt.IsValid() {
+			return &ValidationError{Field: "min_scores", Message: fmt.Sprintf(errUnknownMetadata, int(mt))}
+		}
+		if minScore < 0 || minScore > 100*shared.ScoreScale {
+			return &ValidationError{Field: mt.String(), Message: fmt.Sprintf("최소 점수는 0에서 100 사이여야 합니다 (%.1f)", minScore.ToFloat())}
+		}
+	}
+	for _, mt := range c.RequiredFeatures {
+		if !mt.IsValid() {
+			return &ValidationError{Field: "required_features", Message: fmt.Sprintf(errUnknownMetadata, int(mt))}
+		}
+	}
+	for _, location := range c.ExcludedLocations {
+		if strings.TrimSpace(location) == "" {
+			return &ValidationError{Field: "excluded_locations", Message: "제외 지역은 비어 있을 수 없습니다"}
+		}
+	}
+	for _, rule := range c.Rules {
+		switch {
+		case rule == nil:
+			return &ValidationError{Field: "rules", Message: "조건 식은 비어 있을 수 없습니다"}
+		case rule.Type() != expr.Bool:
+			return &ValidationError{Field: "rules", Message: fmt.Sprintf("조건 식 %q는 참/거짓이 아닌 %s 식입니다", rule, rule.Type())}
+		case rule.UsesTotal():
+			return &ValidationError{Field: "rules", Message: fmt.Sprintf("조건 식 %q: 제약 조건은 총점(total) 계산 전에 적용됩니다", rule)}
+		}
+	}
+	return nil
+}
+
+// Check returns every constraint the apartment violates, or nil if it passes.
+func (c Constraints) Check(apt ApartmentData) []ConstraintViolation {
+	var violations []ConstraintViolation
+	for _, location := range c.ExcludedLocations {
+		if strings.Contains(apt.Location, location) {
+			violations = append(violations, ConstraintViolation{
+				Kind:     ConstraintExcludedLocation,
+				Location: location,
+				Message:  fmt.Sprintf("제외 지역(%s)에 위치함", location),
+			})
+		}
+	}
+	for _, mt := range c.RequiredFeatures {
+		if score := apt.Scores[mt]; score.IsMissing() {
+			violations = append(violations, missingViolation(ConstraintRequiredFeature, mt, RequiredFeatureThreshold))
+		} else if score < RequiredFeatureThreshold {
+			violations = append(violations, ConstraintViolation{
+				Kind:     ConstraintRequiredFeature,
+				Factor:   mt,
+				Required: RequiredFeatureThreshold.ToFloat(),
+				Actual:   score.ToFloat(),
+				Message:  fmt.Sprintf("필수 요소 %s 없음", mt.KoreanName()),
+			})
+		}
+	}
+	for _, mt := range metadata.All() {
+		minScore, exists := c.MinScores[mt]
+		if !exists {
+			continue
+		}
+		if score := apt.Scores[mt]; score.IsMissing() {
+			violations = append(violations, missingViolation(ConstraintMinScore, mt, minScore))
+		} else if score < minScore {
+			violations = append(violations, ConstraintViolation{
+				Kind:     ConstraintMinScore,
+				Factor:   mt,
+				Required: minScore.ToFloat(),
+				Actual:   score.ToFloat(),
+				Message:  fmt.Sprintf("%s 점수 %.1f점이 최소 기준 %.1f점 미만", mt.KoreanName(), score.ToFloat(), minScore.ToFloat()),
+			})
+		}
+	}
+	for _, rule := range c.Rules {
+		if violation, ok := checkRule(rule, apt); !ok {
+			violations = append(violations, violation)
+		}
+	}
+	return violations
+}
+
+// checkRule evaluates a rule against the apartment's raw scores. A rule that reads a factor
+// without data or fails to evaluate is reported as violated.
+func checkRule(rule *expr.Expr, apt ApartmentData) (ConstraintViolation, bool) {
+	holds, err := rule.Test(expr.MapEnv(apt.Scores))
+	if err == nil && holds {
+		return ConstraintViolation{}, true
+	}
+	violation := ConstraintViolation{Kind: ConstraintRule, Rule: rule.String()}
+	var missing []string
+	for _, mt := range rule.Factors() {
+		if apt.Scores[mt].IsMissing() {
+			missing = append(missing, mt.KoreanName())
+		}
+	}
+	switch {
+	case err != nil:
+		violation.Message = fmt.Sprintf("조건 %s 계산 실패: %v", rule, err)
+	case len(missing) > 0:
+		violation.Message = fmt.Sprintf("조건 %s 불충족 (%s 데이터 없음)", rule, strings.Join(missing, ", "))
+	default:
+		violation.Message = fmt.Sprintf("조건 %s 불충족", rule)
+	}
+	return violation, false
+}
+
+// ApplyConstraints splits apartments into those that pass every constraint and those eliminated.
+func ApplyConstraints(apartments []ApartmentData, c Constraints) ([]ApartmentData, []Elimination) {
+	passed := make([]ApartmentData, 0, len(apartments))
+	var eliminated []Elimination
+	for _, apt := range apartments {
+		if violations := c.Check(apt); len(violations) > 0 {
+			eliminated = append(eliminated, Elimination{Apartment: apt, Violations: violations})
+			continue
+		}
+		passed = append(passed, apt)
+	}
+	return passed, eliminated
+}
+
+// missingViolation reports a constraint that cannot be confirmed because the factor has no data.
+func missingViolation(kind ConstraintKind, mt metadata.MetadataType, required shared.ScoreValue) ConstraintViolation {
+	return ConstraintViolation{
+		Kind:     kind,
+		Factor:   mt,
+		Required: required.ToFloat(),
+		Message:  fmt.Sprintf("%s 데이터 없음 (조건 확인 불가)", mt.KoreanName()),
+	}
+}
@@ -0,0 +1,149 @@
+package scoring
+
+import (
+	"strings"
+	"testing"
+
+	"apart_score/pkg/expr"
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+)
+
+func TestApplyConstraints(t *testing.T) {
+	noElevator := uniformScores(80)
+	noElevator[metadata.ElevatorPresence] = 0
+	farFromStation := uniformScores(80)
+	farFromStation[metadata.DistanceToStation] = shared.ScoreValueFromFloat(40)
+
+	apartments := []ApartmentData{
+		{ID: "ok", Name: "통과", Scores: uniformScores(80), Location: "서울시 마포구"},
+		{ID: "no-elevator", Name: "엘리베이터 없음", Scores: noElevator, Location: "서울시 마포구"},
+		{ID: "far", Name: "역세권 아님", Scores: farFromStation, Location: "서울시 마포구"},
+		{ID: "excluded", Name: "제외 지역", Scores: uniformScores(80), Location: "서울시 강북구"},
+	}
+	constraints := Constraints{
+		MinScores:         map[metadata.MetadataType]shared.ScoreValue{metadata.DistanceToStation: shared.ScoreValueFromFloat(60)},
+		RequiredFeatures:  []metadata.MetadataType{metadata.ElevatorPresence},
+		ExcludedLocations: []string{"강북구"},
+	}
+
+	passed, eliminated := ApplyConstraints(apartments, constraints)
+	if len(passed) != 1 || passed[0].ID != "ok" {
+		t.Fatalf("Expected only 'ok' to pass, got %v", passed)
+	}
+	expected := map[string]ConstraintKind{
+		"no-elevator": ConstraintRequiredFeature,
+		"far":         ConstraintMinScore,
+		"excluded":    ConstraintExcludedLocation,
+	}
+	if len(eliminated) != len(expected) {
+		t.Fatalf("Expected %d eliminations, got %d", len(expected), len(eliminated))
+	}
+	for _, e := range eliminated {
+		if len(e.Violations) != 1 || e.Violations[0].Kind != expected[e.Apartment.ID] {
+			t.Errorf("%s: unexpected violations %v", e.Apartment.ID, e.Violations)
+		}
+	}
+}
+
+func TestCalculateRankingsWithConstraints(t *testing.T) {
+	noElevator := uniformScores(95)
+	noElevator[metadata.ElevatorPresence] = 0
+	apartments := []ApartmentData{
+		{ID: "a", Name: "A", Scores: uniformScores(70)},
+		{ID: "b", Name: "B", Scores: noElevator},
+	}
+	opts := RankingOptions{Constraints: &Constraints{RequiredFeatures: []metadata.MetadataType{metadata.ElevatorPresence}}}
+
+	summary, err := CalculateRankingsWithOptions(apartments, getTestWeights(), StrategyWeightedSum, opts)
+	if err != nil {
+		t.Fatalf("CalculateRankingsWithOptions failed: %v", err)
+	}
+	if summary.TotalApartments != 1 || summary.TopRanked[0].Apartment.ID != "a" {
+		t.Errorf("Expected only 'a' to be ranked, got %+v", summary.TopRanked)
+	}
+	if len(summary.Eliminated) != 1 || summary.Eliminated[0].Apartment.ID != "b" {
+		t.Errorf("Expected 'b' to be eliminated, got %+v", summary.Eliminated)
+	}
+	if !strings.Contains(FormatRankings(summary, 10), "제외된 아파트") {
+		t.Error("Expected formatted rankings to list eliminated apartments")
+	}
+
+	all, _ := CalculateRankingsWithOptions(apartments[1:], getTestWeights(), StrategyWeightedSum, opts)
+	if all.TotalApartments != 0 || len(all.Eliminated) != 1 {
+		t.Errorf("Expected all apartments eliminated, got %+v", all)
+	}
+	// 모두 제외되어도 가중치, 전략, 결측값 정책, 가치 함수는 검증
+	for name, bad := range map[string]func() error{
+		"weights": func() error {
+			_, err := CalculateRankingsWithOptions(apartments[1:], map[metadata.MetadataType]shared.Weight{metadata.FloorLevel: 10}, StrategyWeightedSum, opts)
+			return err
+		},
+		"strategy": func() error {
+			_, err := CalculateRankingsWithOptions(apartments[1:], getTestWeights(), StrategyType("unknown"), opts)
+			return err
+		},
+		"missing policy": func() error {
+			_, err := CalculateRankingsWithOptions(apartments[1:], getTestWeights(), StrategyWeightedSum,
+				RankingOptions{Constraints: opts.Constraints, MissingPolicy: "guess"})
+			return err
+		},
+		"value functions": func() error {
+			_, err := CalculateRankingsWithOptions(apartments[1:], getTestWeights(), StrategyWeightedSum,
+				RankingOptions{Constraints: opts.Constraints, ValueFunctions: ValueFunctions{metadata.FloorLevel: {}}})
+			return err
+		},
+	} {
+		if err := bad(); err == nil {
+			t.Errorf("%s: expected error even when every apartment is eliminated", name)
+		}
+	}
+
+	invalid := RankingOptions{Constraints: &Constraints{RequiredFeatures: []metadata.MetadataType{metadata.MetadataType(metadata.MaxMetadataTypes)}}}
+	if _, err := CalculateRankingsWithOptions(apartments, getTestWeights(), StrategyWeightedSum, invalid); err == nil {
+		t.Error("Expected error for unregistered constraint factor")
+	}
+}
+
+func TestConstraintRules(t *testing.T) {
+	rule, err := expr.Parse("학군 >= 80 or TransportationAccess >= 90")
+	if err != nil {
+		t.Fatal(err)
+	}
+	goodSchool := uniformScores(60)
+	goodSchool[metadata.SchoolDistrict] = shared.ScoreValueFromFloat(85)
+	unknown := uniformScores(60)
+	unknown[metadata.SchoolDistrict] = shared.MissingScore
+	apartments := []ApartmentData{
+		{ID: "school", Scores: goodSchool},
+		{ID: "neither", Scores: uniformScores(60)},
+		{ID: "unknown", Scores: unknown},
+	}
+	constraints := Constraints{Rules: []*expr.Expr{rule}}
+	if constraints.IsEmpty() || constraints.Validate() != nil {
+		t.Fatalf("rule constraints: empty %v, %v", constraints.IsEmpty(), constraints.Validate())
+	}
+	passed, eliminated := ApplyConstraints(apartments, constraints)
+	if len(passed) != 1 || passed[0].ID != "school" || len(eliminated) != 2 {
+		t.Fatalf("passed %v, eliminated %v", passed, eliminated)
+	}
+	for _, e := range eliminated {
+		v := e.Violations[0]
+		if v.Kind != ConstraintRule || v.Rule != rule.String() {
+			t.Errorf("%s: %+v", e.Apartment.ID, v)
+		}
+	}
+	if msg := eliminated[1].Violations[0].Message; !strings.Contains(msg, "학군 데이터 없음") {
+		t.Errorf("missing data message: %s", msg)
+	}
+
+	for _, source := range []string{"학군 + 1", "total > 50"} {
+		invalid, err := expr.Parse(source)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := (Constraints{Rules: []*expr.Expr{invalid}}).Validate(); err == nil {
+			t.Errorf("%s: rule is valid", source)
+		}
+	}
+}
@@ -0,0 +1,346 @@
+package scoring
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// maxCounterfactualFactors bounds the changeable factors, since every subset of them may be tried.
+const maxCounterfactualFactors = 16
+
+// CounterfactualGoal is what an apartment should achieve: a total score, or ranking above a rival.
+type CounterfactualGoal struct {
+	TargetScore float64        // 도달할 총점 (Rival이 없을 때, 0-100)
+	Rival       *ApartmentData // 순위에서 앞서야 할 아파트
+	// Cohort holds the other apartments ranked alongside. It only matters for cohort strategies
+	// such as TOPSIS, whose scores depend on every ranked apartment.
+	Cohort []ApartmentData
+	// Changeable lists the factors that may be changed. Only metadata.FactorInternal factors can be
+	// changed by renovation; when empty, all of them are.
+	Changeable []metadata.MetadataType
+}
+
+// FactorChange is a raw score change of one factor.
+type FactorChange struct {
+	Factor metadata.MetadataType `json:"factor"`
+	From   float64               `json:"from"`  // 현재 점수
+	To     float64               `json:"to"`    // 필요한 점수
+	Delta  float64               `json:"delta"` // 점수 변화량
+}
+
+// Counterfactual is the smallest set of factor changes that achieves a goal: the fewest factors,
+// and among those the smallest total score change.
+type Counterfactual struct {
+	ApartmentID  string         `json:"apartment_id"`
+	RivalID      string         `json:"rival_id,omitempty"`
+	CurrentScore float64        `json:"current_score"`
+	TargetScore  float64        `json:"target_score"` // 목표 점수 또는 경쟁 아파트의 현재 점수
+	Achievable   bool           `json:"achievable"`   // false면 바꿀 수 있는 요소를 모두 100점으로 올려도 달성 불가
+	NewScore     float64        `json:"new_score"`    // 변경 후 점수 (달성 불가능하면 도달 가능한 최고 점수)
+	Changes      []FactorChange `json:"changes"`      // 점수를 올려야 하는 요소 (요소 번호 순)
+	TotalChange  float64        `json:"total_change"` // 점수 변화량 합계
+}
+
+// goalSeeker scores modified copies of an apartment against a goal.
+type goalSeeker struct {
+	apt      ApartmentData
+	others   []ApartmentData // 코호트 전략에서 함께 점수를 매길 아파트 (경쟁 아파트가 첫 번째)
+	rival    bool
+	target   float64
+	weights  map[metadata.MetadataType]shared.Weight
+	strategy StrategyType
+	values   ValueFunctions
+}
+
+// evaluate returns the apartment's total score and the score it must reach.
+func (g *goalSeeker) evaluate(scores map[metadata.MetadataType]shared.ScoreValue) (float64, float64, error) {
+	modified := g.apt
+	modified.Scores = scores
+	results, err := scoreCohort(g.values.applyAll(append([]ApartmentData{modified}, g.others...)),
+		g.weights, g.strategy, MissingRenormalize)
+	if err != nil {
+		return 0, 0, err
+	}
+	if g.rival {
+		return results[0].TotalScore, results[1].TotalScore, nil
+	}
+	return results[0].TotalScore, g.target, nil
+}
+
+// margin returns how far the apartment is above its goal.
+func (g *goalSeeker) margin(scores map[metadata.MetadataType]shared.ScoreValue) (float64, error) {
+	score, goal, err := g.evaluate(scores)
+	return score - goal, err
+}
+
+// met reports whether a margin achieves the goal; a rival must be beaten strictly.
+func (g *goalSeeker) met(margin float64) bool {
+	if g.rival {
+		return margin > 1e-9
+	}
+	return margin >= -1e-9
+}
+
+// ExplainCounterfactual finds the minimal raw score changes to the apartment's changeable factors
+// that achieve the goal under the profile. Only increases are considered, and factors that are
+// missing or carry no weight are left as they are.
+func ExplainCounterfactual(apt ApartmentData, p ScoringProfile, goal CounterfactualGoal) (*Counterfactual, error) {
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf(errInvalidProfile, p.Name, err)
+	}
+	changeable, err := goal.changeable()
+	if err != nil {
+		return nil, err
+	}
+	seeker := &goalSeeker{apt: apt, weights: p.EffectiveWeights(), strategy: p.Strategy(), values: p.ValueFunctions}
+	result := &Counterfactual{ApartmentID: apt.ID, Changes: []FactorChange{}}
+	switch {
+	case goal.Rival != nil && goal.Rival.ID == apt.ID:
+		return nil, &ValidationError{Field: "rival", Message: fmt.Sprintf("경쟁 아파트가 대상 아파트와 같습니다 (%s)", apt.ID)}
+	case goal.Rival != nil:
+		seeker.rival = true
+		seeker.others = []ApartmentData{*goal.Rival}
+		result.RivalID = goal.Rival.ID
+	case goal.TargetScore > 0 && goal.TargetScore <= 100:
+		seeker.target = goal.TargetScore
+	default:
+		return nil, &ValidationError{Field: "target", Message: fmt.Sprintf("목표 점수(0-100) 또는 경쟁 아파트가 필요합니다 (%g)", goal.TargetScore)}
+	}
+	if impl, _ := LookupStrategy(p.Strategy()); isCohortStrategy(impl) {
+		for _, other := range goal.Cohort {
+			if other.ID != apt.ID && (goal.Rival == nil || other.ID != goal.Rival.ID) {
+				seeker.others = append(seeker.others, other)
+			}
+		}
+	}
+
+	current, target, err := seeker.evaluate(apt.Scores)
+	if err != nil {
+		return nil, err
+	}
+	result.CurrentScore, result.TargetScore, result.NewScore = current, target, current
+	if seeker.met(current - target) {
+		result.Achievable = true
+		return result, nil
+	}
+
+	var candidates []metadata.MetadataType
+	for _, mt := range changeable {
+		score, exists := apt.Scores[mt]
+		if exists && !score.IsMissing() && score < 100*shared.ScoreScale && seeker.weights[mt] > 0 {
+			candidates = append(candidates, mt)
+		}
+	}
+	if len(candidates) > maxCounterfactualFactors {
+		return nil, &ValidationError{Field: "changeable",
+			Message: fmt.Sprintf("바꿀 수 있는 요소가 너무 많습니다 (%d개, 최대 %d개)", len(candidates), maxCounterfactualFactors)}
+	}
+
+	// 요소 수가 적은 조합부터 시도하고, 같은 수에서는 변화량 합계가 가장 작은 조합을 선택
+	for size := 1; size <= len(candidates); size++ {
+		var best map[metadata.MetadataType]shared.ScoreValue
+		bestTotal := math.Inf(1)
+		var searchErr error
+		forEachCombination(candidates, size, func(subset []metadata.MetadataType) bool {
+			maxed := copyScores(apt.Scores)
+			for _, mt := range subset {
+				maxed[mt] = 100 * shared.ScoreScale
+			}
+			margin, err := seeker.margin(maxed)
+			if err != nil {
+				searchErr = err
+				return false
+			}
+			if !seeker.met(margin) {
+				return true
+			}
+			raised, err := seeker.raise(subset)
+			if err != nil {
+				searchErr = err
+				return false
+			}
+			if total := totalChange(apt.Scores, raised); raised != nil && total < bestTotal {
+				best, bestTotal = raised, total
+			}
+			return true
+		})
+		if searchErr != nil {
+			return nil, searchErr
+		}
+		if best != nil {
+			result.Achievable = true
+			result.Changes = changesBetween(apt.Scores, best)
+			result.TotalChange = bestTotal
+			result.NewScore, _, err = seeker.evaluate(best)
+			return result, err
+		}
+	}
+
+	// 달성 불가능: 바꿀 수 있는 요소를 모두 올렸을 때의 점수를 보고
+	maxed := copyScores(apt.Scores)
+	for _, mt := range candidates {
+		maxed[mt] = 100 * shared.ScoreScale
+	}
+	result.NewScore, _, err = seeker.evaluate(maxed)
+	return result, err
+}
+
+// raise greedily raises the factors of subset one point at a time, always picking the factor with
+// the largest gain, and bisects the last step so the goal is just met. It returns nil when the
+// goal cannot be met.
+func (g *goalSeeker) raise(subset []metadata.MetadataType) (map[metadata.MetadataType]shared.ScoreValue, error) {
+	scores := copyScores(g.apt.Scores)
+	margin, err := g.margin(scores)
+	if err != nil {
+		return nil, err
+	}
+	for !g.met(margin) {
+		var next metadata.MetadataType
+		bestGain, bestMargin, found := math.Inf(-1), 0.0, false
+		for _, mt := range subset {
+			if scores[mt] >= 100*shared.ScoreScale {
+				continue
+			}
+			previous := scores[mt]
+			scores[mt] = min(previous+shared.ScoreScale, 100*shared.ScoreScale)
+			m, err := g.margin(scores)
+			scores[mt] = previous
+			if err != nil {
+				return nil, err
+			}
+			// 이득이 같으면 (예: min_max에서 최저 요소가 아닌 경우) 점수가 낮은 요소 우선
+			if gain := m - margin; !found || gain > bestGain+1e-12 ||
+				(math.Abs(gain-bestGain) <= 1e-12 && scores[mt] < scores[next]) {
+				next, bestGain, bestMargin, found = mt, gain, m, true
+			}
+		}
+		if !found {
+			return nil, nil
+		}
+		previous := scores[next]
+		scores[next] = min(previous+shared.ScoreScale, 100*shared.ScoreScale)
+		margin = bestMargin
+		if g.met(margin) {
+			// 마지막 1점 안에서 목표를 만족하는 최소 점수를 이분 탐색
+			lo, hi := previous, scores[next]
+			for hi-lo > 1 {
+				mid := lo + (hi-lo)/2
+				scores[next] = mid
+				m, err := g.margin(scores)
+				if err != nil {
+					return nil, err
+				}
+				if g.met(m) {
+					hi = mid
+				} else {
+					lo = mid
+				}
+			}
+			scores[next] = hi
+		}
+	}
+	return scores, nil
+}
+
+// changeable returns the factors the goal may change, rejecting external factors.
+func (goal CounterfactualGoal) changeable() ([]metadata.MetadataType, error) {
+	if len(goal.Changeable) == 0 {
+		return metadata.ByFactorType(metadata.FactorInternal), nil
+	}
+	factors := make([]metadata.MetadataType, 0, len(goal.Changeable))
+	seen := make(map[metadata.MetadataType]bool, len(goal.Changeable))
+	for _, mt := range goal.Changeable {
+		if !mt.IsValid() {
+			return nil, &ValidationError{Field: "changeable", Message: fmt.Sprintf(errUnknownMetadata, int(mt))}
+		}
+		if mt.FactorType() != metadata.FactorInternal {
+			return nil, &ValidationError{Field: mt.String(),
+				Message: fmt.Sprintf("%s는 외부 요인이라 바꿀 수 없습니다", mt.KoreanName())}
+		}
+		if !seen[mt] {
+			seen[mt] = true
+			factors = append(factors, mt)
+		}
+	}
+	sort.Slice(factors, func(i, j int) bool { return factors[i] < factors[j] })
+	return factors, nil
+}
+
+func isCohortStrategy(impl Strategy) bool {
+	_, isCohort := impl.(CohortStrategy)
+	return isCohort
+}
+
+// forEachCombination calls fn with every size-element subset of items in lexicographic order
+// until fn returns false.
+func forEachCombination(items []metadata.MetadataType, size int, fn func([]metadata.MetadataType) bool) {
+	subset := make([]metadata.MetadataType, 0, size)
+	var walk func(start int) bool
+	walk = func(start int) bool {
+		if len(subset) == size {
+			return fn(subset)
+		}
+		for i := start; i <= len(items)-(size-len(subset)); i++ {
+			subset = append(subset, items[i])
+			if !walk(i + 1) {
+				return false
+			}
+			subset = subset[:len(subset)-1]
+		}
+		return true
+	}
+	walk(0)
+}
+
+func copyScores(scores map[metadata.MetadataType]shared.ScoreValue) map[metadata.MetadataType]shared.ScoreValue {
+	copied := make(map[metadata.MetadataType]shared.ScoreValue, len(scores))
+	for mt, score := range scores {
+		copied[mt] = score
+	}
+	return copied
+}
+
+func totalChange(from, to map[metadata.MetadataType]shared.ScoreValue) float64 {
+	total := 0.0
+	for _, change := range changesBetween(from, to) {
+		total += change.Delta
+	}
+	return total
+}
+
+// changesBetween lists the factors whose score differs, ordered by metadata type.
+func changesBetween(from, to map[metadata.MetadataType]shared.ScoreValue) []FactorChange {
+	changes := []FactorChange{}
+	for mt, score := range to {
+		if score != from[mt] {
+			changes = append(changes, FactorChange{Factor: mt, From: from[mt].ToFloat(), To: score.ToFloat(),
+				Delta: score.ToFloat() - from[mt].ToFloat()})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Factor < changes[j].Factor })
+	return changes
+}
+
+// FormatCounterfactual formats the changes needed to reach a goal for display.
+func FormatCounterfactual(c *Counterfactual) string {
+	goal := fmt.Sprintf("목표 %.1f점", c.TargetScore)
+	if c.RivalID != "" {
+		goal = fmt.Sprintf("%s 앞지르기 (현재 %.1f점)", c.RivalID, c.TargetScore)
+	}
+	output := fmt.Sprintf("🛠️ 목표 달성 조건: %s, 현재 %.1f점\n", goal, c.CurrentScore)
+	switch {
+	case !c.Achievable:
+		output += fmt.Sprintf("  • 바꿀 수 있는 요소를 모두 100점으로 올려도 %.1f점으로 달성할 수 없습니다\n", c.NewScore)
+	case len(c.Changes) == 0:
+		output += "  • 이미 목표를 달성했습니다\n"
+	default:
+		for _, change := range c.Changes {
+			output += fmt.Sprintf("  • %s: %.1f → %.1f점 (%+.1f)\n", change.Factor.KoreanName(), change.From, change.To, change.Delta)
+		}
+		output += fmt.Sprintf("  • 변경 후 %.1f점 (요소 %d개, 변화량 합계 %.1f점)\n", c.NewScore, len(c.Changes), c.TotalChange)
+	}
+	return output
+}
@@ -0,0 +1,142 @@
+package scoring
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+)
+
+// heaviestInternal returns the internal factor with the largest weight and that weight (0-1).
+func heaviestInternal(weights map[metadata.MetadataType]shared.Weight) (metadata.MetadataType, float64) {
+	factors := metadata.ByFactorType(metadata.FactorInternal)
+	best := factors[0]
+	for _, mt := range factors[1:] {
+		if weights[mt] > weights[best] {
+			best = mt
+		}
+	}
+	return best, weights[best].ToFloat()
+}
+
+func TestCounterfactualTargetScore(t *testing.T) {
+	apt := ApartmentData{ID: "a", Scores: uniformScores(60)}
+	p := ScoringProfile{}
+	factor, weight := heaviestInternal(p.EffectiveWeights())
+
+	cf, err := ExplainCounterfactual(apt, p, CounterfactualGoal{TargetScore: 62})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cf.Achievable || len(cf.Changes) != 1 || cf.Changes[0].Factor != factor {
+		t.Fatalf("expected a single change of %s, got %+v", factor, cf)
+	}
+	if want := 2 / weight; math.Abs(cf.Changes[0].Delta-want) > 0.01 {
+		t.Errorf("delta = %.3f, want %.3f", cf.Changes[0].Delta, want)
+	}
+	if cf.NewScore < 62-1e-9 || cf.NewScore > 62.01 {
+		t.Errorf("new score = %.4f, want just above 62", cf.NewScore)
+	}
+
+	// 가장 무거운 요소만으로는 부족하면 두 요소가 필요
+	target := 60 + weight*40 + 0.5
+	cf, err = ExplainCounterfactual(apt, p, CounterfactualGoal{TargetScore: target})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cf.Achievable || len(cf.Changes) != 2 {
+		t.Fatalf("expected two changes for %.2f, got %+v", target, cf)
+	}
+	for _, change := range cf.Changes {
+		if change.Factor.FactorType() != metadata.FactorInternal {
+			t.Errorf("changed external factor %s", change.Factor)
+		}
+	}
+
+	// 내부 요인을 모두 100점으로 올려도 닿지 않는 목표
+	cf, err = ExplainCounterfactual(apt, p, CounterfactualGoal{TargetScore: 99})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cf.Achievable || len(cf.Changes) != 0 || cf.NewScore >= 99 || cf.NewScore <= 60 {
+		t.Errorf("unreachable target: %+v", cf)
+	}
+
+	cf, err = ExplainCounterfactual(apt, p, CounterfactualGoal{TargetScore: 50})
+	if err != nil || !cf.Achievable || len(cf.Changes) != 0 {
+		t.Errorf("already met: %+v, %v", cf, err)
+	}
+}
+
+func TestCounterfactualBeatRival(t *testing.T) {
+	apt := ApartmentData{ID: "a", Scores: uniformScores(70)}
+	rival := ApartmentData{ID: "b", Scores: uniformScores(70)}
+	rival.Scores[metadata.SchoolDistrict] = shared.ScoreValueFromFloat(90)
+
+	for _, strategy := range []StrategyType{StrategyWeightedSum, StrategyGeometricMean, StrategyTOPSIS} {
+		p := ScoringProfile{Method: strategy}
+		cf, err := ExplainCounterfactual(apt, p, CounterfactualGoal{Rival: &rival,
+			Cohort: []ApartmentData{apt, rival, {ID: "c", Scores: uniformScores(50)}}})
+		if err != nil {
+			t.Fatalf("%s: %v", strategy, err)
+		}
+		if !cf.Achievable || len(cf.Changes) == 0 || cf.RivalID != "b" {
+			t.Fatalf("%s: %+v", strategy, cf)
+		}
+		// 제안대로 바꾸면 실제 순위에서 앞서야 함
+		changed := ApartmentData{ID: "a", Scores: copyScores(apt.Scores)}
+		for _, change := range cf.Changes {
+			changed.Scores[change.Factor] = shared.ScoreValueFromFloat(change.To)
+		}
+		summary, err := CalculateRankingsWithProfile([]ApartmentData{rival, changed, {ID: "c", Scores: uniformScores(50)}}, p, RankingOptions{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if summary.TopRanked[0].Apartment.ID != "a" {
+			t.Errorf("%s: after the changes %s still ranks first", strategy, summary.TopRanked[0].Apartment.ID)
+		}
+	}
+}
+
+func TestCounterfactualErrors(t *testing.T) {
+	apt := ApartmentData{ID: "a", Scores: uniformScores(60)}
+	var validation *ValidationError
+	_, err := ExplainCounterfactual(apt, ScoringProfile{}, CounterfactualGoal{TargetScore: 80,
+		Changeable: []metadata.MetadataType{metadata.FloorLevel, metadata.SchoolDistrict}})
+	if !errors.As(err, &validation) || validation.Field != metadata.SchoolDistrict.String() {
+		t.Errorf("external factor: %v, want ValidationError on %s", err, metadata.SchoolDistrict)
+	}
+	if _, err := ExplainCounterfactual(apt, ScoringProfile{}, CounterfactualGoal{}); !errors.As(err, &validation) {
+		t.Errorf("missing goal: %v", err)
+	}
+	if _, err := ExplainCounterfactual(apt, ScoringProfile{}, CounterfactualGoal{TargetScore: 120}); !errors.As(err, &validation) {
+		t.Errorf("target above 100: %v", err)
+	}
+	self := apt
+	if _, err := ExplainCounterfactual(apt, ScoringProfile{}, CounterfactualGoal{Rival: &self}); !errors.As(err, &validation) || validation.Field != "rival" {
+		t.Errorf("self as rival: %v", err)
+	}
+}
+
+func TestDashboardCounterfactual(t *testing.T) {
+	scores := uniformScores(60)
+	weights := GetScenarioWeights(ScenarioBalanced)
+	result, err := CalculateWithStrategy(scores, weights, StrategyWeightedSum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dashboard, err := GenerateTransparencyDashboardWithOptions(result, scores, weights, StrategyWeightedSum,
+		DashboardOptions{Goal: &CounterfactualGoal{TargetScore: 65}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dashboard.Counterfactual == nil || !dashboard.Counterfactual.Achievable {
+		t.Fatalf("dashboard counterfactual: %+v", dashboard.Counterfactual)
+	}
+	if text := FormatTransparencyDashboard(dashboard); !strings.Contains(text, "목표 달성 조건") {
+		t.Errorf("formatted dashboard lacks the counterfactual section:\n%s", text)
+	}
+}
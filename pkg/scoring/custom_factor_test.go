@@ -0,0 +1,69 @@
+package scoring
+
+import (
+	"testing"
+
+	"apart_score/internal/metadatatest"
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+)
+
+func TestCustomMetadataFlowsThroughScoring(t *testing.T) {
+	t.Cleanup(metadatatest.Reset)
+	noise, err := metadata.Register(metadata.MetadataDefinition{
+		EnglishName: "Noise Level",
+		KoreanName:  "소음",
+		Description: "조용할수록 높은 점수",
+		FactorType:  metadata.FactorExternal,
+	})
+	if err != nil {
+		t.Fatalf("Register failed: %v", err)
+	}
+
+	scores := getTestScores()
+	scores[noise] = shared.ScoreValueFromFloat(20.0)
+
+	weights := getTestWeights()
+	baseline, err := CalculateWithStrategy(scores, weights, StrategyWeightedSum)
+	if err != nil {
+		t.Fatalf("Calculate without custom weight failed: %v", err)
+	}
+
+	weights[noise] = shared.WeightFromFloat(0.3)
+	weights = shared.NormalizeWeights(weights)
+	withNoise, err := CalculateWithStrategy(scores, weights, StrategyWeightedSum)
+	if err != nil {
+		t.Fatalf("Calculate with custom weight failed: %v", err)
+	}
+
+	if withNoise.RawScores[noise] != scores[noise] || withNoise.Weights[noise] != weights[noise] {
+		t.Error("Custom factor should be recorded in the result arrays")
+	}
+	if withNoise.TotalScore >= baseline.TotalScore {
+		t.Errorf("Low custom factor score should lower the total: %.1f >= %.1f", withNoise.TotalScore, baseline.TotalScore)
+	}
+
+	minMax, err := CalculateWithStrategy(getTestScores(), getTestWeights(), StrategyMinMax)
+	if err != nil {
+		t.Fatalf("MinMax failed: %v", err)
+	}
+	if minMax.TotalScore <= 0 {
+		t.Error("Unweighted custom factor should not drive MinMax to zero")
+	}
+	builtinWeights := getTestWeights()
+	builtinWeights[metadata.FloorLevel] += builtinWeights[metadata.HeatingSystem]
+	builtinWeights[metadata.HeatingSystem] = 0
+	if minMax, err = CalculateWithStrategy(getTestScores(), builtinWeights, StrategyMinMax); err != nil || minMax.TotalScore != 0 {
+		t.Errorf("Unweighted built-in factor should still count in MinMax: %.1f, %v", minMax.TotalScore, err)
+	}
+
+	dashboard := GenerateTransparencyDashboard(withNoise, scores, weights, StrategyWeightedSum)
+	if _, exists := dashboard.ScoreBreakdown.ComponentScores[noise.String()]; !exists {
+		t.Error("Dashboard should include the custom factor")
+	}
+
+	unknown := map[metadata.MetadataType]shared.ScoreValue{metadata.MetadataType(metadata.MaxMetadataTypes - 1): 1}
+	if _, err := CalculateWithStrategy(unknown, weights, StrategyWeightedSum); err == nil {
+		t.Error("Unregistered metadata type should fail")
+	}
+}
@@ -0,0 +1,87 @@
+package scoring
+
+import (
+	"apart_score/pkg/expr"
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"fmt"
+	"math"
+)
+
+// DerivedFactor computes a factor's raw score from other factors with a number expression,
+// such as a registered custom factor scored as 0.6*ApartmentSize + 0.4*MaintenanceFee.
+type DerivedFactor struct {
+	Factor metadata.MetadataType
+	Expr   *expr.Expr
+}
+
+// DerivedFactors are computed in order, so each may read the ones before it. An expression
+// that reads its own factor reads the score it replaces, as in Parking = min(Parking, 60).
+type DerivedFactors []DerivedFactor
+
+// Validate checks that every derived factor is registered, computed once and defined by a
+// number expression that does not read the pipeline total.
+func (d DerivedFactors) Validate() error {
+	seen := make(map[metadata.MetadataType]bool, len(d))
+	for _, derived := range d {
+		if !derived.Factor.IsValid() {
+			return &ValidationError{Field: "derived", Message: fmt.Sprintf(errUnknownMetadata, int(derived.Factor))}
+		}
+		field := derived.Factor.String()
+		switch {
+		case seen[derived.Factor]:
+			return &ValidationError{Field: field, Message: "파생 요소가 중복되었습니다"}
+		case derived.Expr == nil:
+			return &ValidationError{Field: field, Message: "파생 요소의 식이 없습니다"}
+		case derived.Expr.Type() != expr.Number:
+			return &ValidationError{Field: field, Message: fmt.Sprintf("식 %q는 숫자가 아닌 %s 식입니다", derived.Expr, derived.Expr.Type())}
+		case derived.Expr.UsesTotal():
+			return &ValidationError{Field: field, Message: fmt.Sprintf("식 %q: 파생 요소에는 총점(total)을 쓸 수 없습니다", derived.Expr)}
+		}
+		seen[derived.Factor] = true
+	}
+	return nil
+}
+
+// Apply returns a copy of the scores with the derived factors computed and clamped to 0-100.
+// A derived factor is missing when its expression reads a factor without data. The input map
+// is not modified; when there are no derived factors it is returned as is.
+func (d DerivedFactors) Apply(scores map[metadata.MetadataType]shared.ScoreValue) (map[metadata.MetadataType]shared.ScoreValue, error) {
+	if len(d) == 0 {
+		return scores, nil
+	}
+	derived := make(map[metadata.MetadataType]shared.ScoreValue, len(scores)+len(d))
+	for mt, score := range scores {
+		derived[mt] = score
+	}
+	env := expr.MapEnv(derived)
+	for _, f := range d {
+		v, err := f.Expr.Eval(env)
+		if err != nil {
+			return nil, fmt.Errorf("파생 요소 %s 계산 실패: %w", f.Factor.KoreanName(), err)
+		}
+		if math.IsNaN(v) {
+			derived[f.Factor] = shared.MissingScore
+			continue
+		}
+		derived[f.Factor] = shared.ScoreValue(math.Round(math.Min(math.Max(v, 0), 100) * shared.ScoreScale))
+	}
+	return derived, nil
+}
+
+// ApplyAll returns copies of the apartments with the derived factors computed.
+func (d DerivedFactors) ApplyAll(apartments []ApartmentData) ([]ApartmentData, error) {
+	if len(d) == 0 {
+		return apartments, nil
+	}
+	derived := make([]ApartmentData, len(apartments))
+	for i, apt := range apartments {
+		scores, err := d.Apply(apt.Scores)
+		if err != nil {
+			return nil, fmt.Errorf("아파트 %s: %w", apt.ID, err)
+		}
+		apt.Scores = scores
+		derived[i] = apt
+	}
+	return derived, nil
+}
@@ -0,0 +1,61 @@
+package scoring
+
+import (
+	"errors"
+	"testing"
+
+	"apart_score/pkg/expr"
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+)
+
+func TestDerivedFactorsApply(t *testing.T) {
+	derived := DerivedFactors{
+		{Factor: metadata.Parking, Expr: mustParse(t, "min(Parking, 60)")},
+		{Factor: metadata.CrimeRate, Expr: mustParse(t, "0.5*Parking + 학군")},
+		{Factor: metadata.GreenSpaceRatio, Expr: mustParse(t, "ElevatorPresence * 2")},
+	}
+	if err := derived.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	scores := map[metadata.MetadataType]shared.ScoreValue{
+		metadata.Parking:          shared.ScoreValueFromFloat(90),
+		metadata.SchoolDistrict:   shared.ScoreValueFromFloat(75.5),
+		metadata.ElevatorPresence: shared.MissingScore,
+	}
+	got, err := derived.Apply(scores)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 주차장은 60으로 제한된 뒤 읽히고, 30 + 75.5는 100으로 잘림
+	if got[metadata.Parking] != shared.ScoreValueFromFloat(60) || got[metadata.CrimeRate] != shared.ScoreValueFromFloat(100) ||
+		got[metadata.GreenSpaceRatio] != shared.MissingScore {
+		t.Errorf("derived scores: %v", got)
+	}
+	if scores[metadata.Parking] != shared.ScoreValueFromFloat(90) {
+		t.Error("Apply modified its input")
+	}
+
+	failing := DerivedFactors{{Factor: metadata.GreenSpaceRatio, Expr: mustParse(t, "100 / (학군 - 75.5)")}}
+	var exprErr *expr.Error
+	if _, err := failing.ApplyAll([]ApartmentData{{ID: "a", Scores: scores}}); !errors.As(err, &exprErr) {
+		t.Errorf("evaluation error: %v", err)
+	}
+}
+
+func TestDerivedFactorsValidate(t *testing.T) {
+	number := mustParse(t, "학군 * 0.5")
+	tests := map[string]DerivedFactors{
+		"unknown factor":   {{Factor: metadata.MetadataType(999), Expr: number}},
+		"duplicate factor": {{Factor: metadata.GreenSpaceRatio, Expr: number}, {Factor: metadata.GreenSpaceRatio, Expr: number}},
+		"missing expr":     {{Factor: metadata.GreenSpaceRatio}},
+		"condition":        {{Factor: metadata.GreenSpaceRatio, Expr: mustParse(t, "학군 > 50")}},
+		"total":            {{Factor: metadata.GreenSpaceRatio, Expr: mustParse(t, "total / 2")}},
+	}
+	for name, derived := range tests {
+		var validation *ValidationError
+		if err := derived.Validate(); !errors.As(err, &validation) {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package scoring
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"fmt"
+	"math/rand"
+)
+
+const (
+	// DefaultBootstrapSamples is the number of bootstrap resamples used for the percentile interval.
+	DefaultBootstrapSamples = 1000
+	// DefaultDashboardConfidence is the confidence level (%) of dashboard intervals.
+	DefaultDashboardConfidence = 90.0
+)
+
+// DashboardOptions supplies optional context for GenerateTransparencyDashboardWithOptions.
+type DashboardOptions struct {
+	// Cohort is scored with the same weights and strategy to place the result among comparable apartments.
+	Cohort []ApartmentData
+	// CohortResults are precomputed results of the reference cohort and take precedence over Cohort.
+	// Pass results from the same CalculateRankings call when using cohort strategies such as TOPSIS.
+	CohortResults    []ScoreResult
+	BootstrapSamples int     // 부트스트랩 반복 횟수 (기본값: DefaultBootstrapSamples)
+	Confidence       float64 // 신뢰 수준 % (기본값: DefaultDashboardConfidence)
+	Seed             int64   // 부트스트랩 및 시뮬레이션 난수 시드 (같은 시드는 같은 결과)
+	// Simulation configures the Monte Carlo run behind the score confidence interval.
+	// When nil, every factor is given a standard deviation of DefaultScoreStdDev.
+	// Its own Seed takes precedence; when that is 0 the dashboard Seed is used.
+	Simulation *SimulationOptions
+	// Groups is the tree whose groups the breakdown's contributions are summed by; only its
+	// structure is used. When nil, DefaultWeightTree groups internal and external factors.
+	Groups *WeightTree
+	// ValueFunctions are the utility curves the result was calculated with. The breakdown reports
+	// raw scores and utilities separately, and the cohort and analyses use the utilities.
+	ValueFunctions ValueFunctions
+	// Goal, when set, adds the minimal factor changes that reach it to the dashboard. Its Cohort
+	// should not contain the scored apartment.
+	Goal *CounterfactualGoal
+	// Pipeline, when set, is the pipeline the result was calculated with instead of the strategy.
+	// Its run on the utilities is traced on the dashboard and it also scores the Cohort.
+	Pipeline *CalculationPipeline
+}
+
+// confidence returns the configured confidence level or the default.
+func (o DashboardOptions) confidence() float64 {
+	if o.Confidence <= 0 || o.Confidence >= 100 {
+		return DefaultDashboardConfidence
+	}
+	return o.Confidence
+}
+
+// simulatedInterval runs a Monte Carlo simulation of the score and returns its confidence interval.
+// When the inputs cannot be simulated it returns the error with a zero-width interval at the score.
+func simulatedInterval(score float64, scores map[metadata.MetadataType]shared.ScoreValue,
+	weights map[metadata.MetadataType]shared.Weight, strategy StrategyType, opts DashboardOptions) (ConfidenceInterval, error) {
+	simOpts := SimulationOptions{DefaultStdDev: DefaultScoreStdDev}
+	if opts.Simulation != nil {
+		simOpts = *opts.Simulation
+	}
+	if simOpts.Seed == 0 {
+		simOpts.Seed = opts.Seed
+	}
+	simOpts.Confidence = opts.confidence()
+	simulation, err := SimulateScore(scores, weights, strategy, simOpts)
+	if err != nil {
+		return ConfidenceInterval{LowerBound: score, UpperBound: score, Confidence: simOpts.Confidence},
+			fmt.Errorf("신뢰 구간 시뮬레이션 실패: %w", err)
+	}
+	return simulation.ConfidenceInterval, nil
+}
+
+// GenerateTransparencyDashboardWithOptions creates a transparency dashboard whose percentile and
+// score distribution are derived from the reference cohort in opts.
+func GenerateTransparencyDashboardWithOptions(result ScoreResult, scores map[metadata.MetadataType]shared.ScoreValue,
+	weights map[metadata.MetadataType]shared.Weight, strategy StrategyType, opts DashboardOptions) (TransparencyDashboard, error) {
+	if err := opts.ValueFunctions.Validate(); err != nil {
+		return TransparencyDashboard{}, err
+	}
+	cohortScores, err := cohortTotalScores(opts, weights, strategy)
+	if err != nil {
+		return TransparencyDashboard{}, err
+	}
+	dashboard, err := generateDashboard(result, scores, weights, strategy, opts, cohortScores)
+	if err != nil {
+		return TransparencyDashboard{}, err
+	}
+	if opts.Goal != nil {
+		p := ScoringProfile{Method: strategy, Weights: weights, ValueFunctions: opts.ValueFunctions}
+		if dashboard.Counterfactual, err = ExplainCounterfactual(ApartmentData{Scores: scores}, p, *opts.Goal); err != nil {
+			return TransparencyDashboard{}, fmt.Errorf("목표 달성 조건 계산 실패: %w", err)
+		}
+	}
+	if opts.Pipeline != nil {
+		if _, dashboard.PipelineTrace, err = CalculateWithPipelineTrace(opts.ValueFunctions.Apply(scores), weights, *opts.Pipeline); err != nil {
+			return TransparencyDashboard{}, err
+		}
+	}
+	return dashboard, nil
+}
+
+// cohortTotalScores returns the total scores of the reference cohort.
+func cohortTotalScores(opts DashboardOptions, weights map[metadata.MetadataType]shared.Weight, strategy StrategyType) ([]float64, error) {
+	results := opts.CohortResults
+	if len(results) == 0 && len(opts.Cohort) > 0 {
+		var err error
+		if opts.Pipeline != nil {
+			results, err = scorePipelineCohort(opts.ValueFunctions.applyAll(opts.Cohort), weights, *opts.Pipeline)
+		} else {
+			results, err = scoreCohort(opts.ValueFunctions.applyAll(opts.Cohort), weights, strategy, MissingRenormalize)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("비교 대상 점수 계산 실패: %w", err)
+		}
+	}
+	totals := make([]float64, len(results))
+	for i, r := range results {
+		totals[i] = r.TotalScore
+	}
+	return totals, nil
+}
+
+// scorePipelineCohort scores each apartment with the pipeline.
+func scorePipelineCohort(apartments []ApartmentData, weights map[metadata.MetadataType]shared.Weight,
+	pipeline CalculationPipeline) ([]ScoreResult, error) {
+	results := make([]ScoreResult, len(apartments))
+	for i, apt := range apartments {
+		result, err := CalculateWithPipeline(apt.Scores, weights, pipeline)
+		if err != nil {
+			return nil, fmt.Errorf(errCalculationFailed, apt.ID, err)
+		}
+		results[i] = result
+	}
+	return results, nil
+}
+
+// generateCohortDistribution places the score within the cohort and bootstraps the percentile interval.
+func generateCohortDistribution(score float64, cohort []float64, opts DashboardOptions, interval ConfidenceInterval) ScoreDistribution {
+	sorted := sortedCopy(cohort)
+	percentile := percentileRank(sorted, score)
+
+	return ScoreDistribution{
+		ScorePercentile: percentile,
+		ScoreRange: ScoreRange{
+			Minimum: sorted[0],
+			Maximum: sorted[len(sorted)-1],
+			Average: mean(sorted),
+			StdDev:  stdDev(sorted),
+		},
+		ComparativeContext: fmt.Sprintf("%.1f점은 비교 대상 %d개 중 상위 %.0f%%에 해당합니다",
+			score, len(sorted), 100-percentile),
+		ConfidenceInterval: interval,
+		PercentileInterval: bootstrapPercentileInterval(score, cohort, opts.BootstrapSamples, opts.confidence(), opts.Seed),
+		CohortSize:         len(sorted),
+	}
+}
+
+// bootstrapPercentileInterval resamples the cohort with replacement and returns the
+// confidence interval of the score's percentile.
+func bootstrapPercentileInterval(score float64, cohort []float64, samples int, confidence float64, seed int64) ConfidenceInterval {
+	if samples <= 0 {
+		samples = DefaultBootstrapSamples
+	}
+	rng := rand.New(rand.NewSource(seed))
+	resample := make([]float64, len(cohort))
+	percentiles := make([]float64, samples)
+	for s := 0; s < samples; s++ {
+		for i := range resample {
+			resample[i] = cohort[rng.Intn(len(cohort))]
+		}
+		percentiles[s] = percentileRank(resample, score)
+	}
+	percentiles = sortedCopy(percentiles)
+	alpha := (1 - confidence/100) / 2
+	return ConfidenceInterval{
+		LowerBound: quantile(percentiles, alpha),
+		UpperBound: quantile(percentiles, 1-alpha),
+		Confidence: confidence,
+	}
+}
@@ -0,0 +1,70 @@
+package scoring
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGenerateTransparencyDashboardWithCohort(t *testing.T) {
+	var cohort []ApartmentData
+	for _, score := range []float64{50, 60, 70, 80, 90} {
+		cohort = append(cohort, ApartmentData{ID: "c", Name: "비교", Scores: uniformScores(score)})
+	}
+	scores := uniformScores(75)
+	weights := getTestWeights()
+	result, err := CalculateWithStrategy(scores, weights, StrategyWeightedSum)
+	if err != nil {
+		t.Fatalf("CalculateWithStrategy failed: %v", err)
+	}
+
+	opts := DashboardOptions{Cohort: cohort, Seed: 42}
+	dashboard, err := GenerateTransparencyDashboardWithOptions(result, scores, weights, StrategyWeightedSum, opts)
+	if err != nil {
+		t.Fatalf("GenerateTransparencyDashboardWithOptions failed: %v", err)
+	}
+	distribution := dashboard.ScoreDistribution
+	if distribution.CohortSize != 5 {
+		t.Errorf("Expected cohort size 5, got %d", distribution.CohortSize)
+	}
+	if math.Abs(distribution.ScorePercentile-60) > 0.01 {
+		t.Errorf("Expected percentile 60, got %.2f", distribution.ScorePercentile)
+	}
+	if math.Abs(distribution.ScoreRange.Average-70) > 0.1 {
+		t.Errorf("Expected mean 70, got %.2f", distribution.ScoreRange.Average)
+	}
+	if math.Abs(distribution.ScoreRange.StdDev-math.Sqrt(250)) > 0.1 {
+		t.Errorf("Expected std dev %.2f, got %.2f", math.Sqrt(250), distribution.ScoreRange.StdDev)
+	}
+	interval := distribution.PercentileInterval
+	if interval.LowerBound > distribution.ScorePercentile || interval.UpperBound < distribution.ScorePercentile {
+		t.Errorf("Expected percentile %.1f inside interval [%.1f, %.1f]", distribution.ScorePercentile, interval.LowerBound, interval.UpperBound)
+	}
+
+	again, _ := GenerateTransparencyDashboardWithOptions(result, scores, weights, StrategyWeightedSum, opts)
+	if again.ScoreDistribution.PercentileInterval != interval {
+		t.Error("Expected the same seed to give the same bootstrap interval")
+	}
+
+	if !strings.Contains(FormatTransparencyDashboard(dashboard), "비교 대상 5개") {
+		t.Error("Expected formatted dashboard to mention the cohort size")
+	}
+
+	precomputed, _ := scoreCohort(cohort, weights, StrategyWeightedSum, MissingRenormalize)
+	fromResults, err := GenerateTransparencyDashboardWithOptions(result, scores, weights, StrategyWeightedSum,
+		DashboardOptions{CohortResults: precomputed, Seed: 42})
+	if err != nil || fromResults.ScoreDistribution.ScorePercentile != distribution.ScorePercentile {
+		t.Errorf("Expected precomputed results to give the same percentile, got %v (%v)", fromResults.ScoreDistribution.ScorePercentile, err)
+	}
+}
+
+func TestGenerateTransparencyDashboardWithoutCohort(t *testing.T) {
+	result, _ := CalculateWithStrategy(getTestScores(), getTestWeights(), StrategyWeightedSum)
+	dashboard, err := GenerateTransparencyDashboardWithOptions(result, getTestScores(), getTestWeights(), StrategyWeightedSum, DashboardOptions{})
+	if err != nil {
+		t.Fatalf("GenerateTransparencyDashboardWithOptions failed: %v", err)
+	}
+	if dashboard.ScoreDistribution.CohortSize != 0 {
+		t.Error("Expected no cohort without options")
+	}
+}
@@ -23,7 +23,7 @@ func (s *DefaultScorer) Calculate(scores map[metadata.MetadataType]shared.ScoreV
 	}
 	var totalWeightedSum float64
 	var totalWeight shared.Weight
-	for _, mt := range shared.FastAllMetadataTypes() {
+	for _, mt := range metadata.All() {
 		rawScore := scores[mt]
 		weight := weights[mt]
 		weightedScore := shared.ScoreValue(int64(rawScore) * int64(weight) / shared.WeightScale)
@@ -41,7 +41,7 @@ func (s *DefaultScorer) Calculate(scores map[metadata.MetadataType]shared.ScoreV
 
 func (s *DefaultScorer) validateWeights(weights map[metadata.MetadataType]shared.Weight) error {
 	var totalWeight shared.Weight
-	for _, mt := range shared.FastAllMetadataTypes() {
+	for _, mt := range metadata.All() {
 		weight := weights[mt]
 		if weight < 0 || weight > shared.WeightScale {
 			return &ValidationError{
@@ -0,0 +1,224 @@
+package scoring
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"fmt"
+	"sort"
+)
+
+// MissingValuePolicy decides how factors marked with shared.MissingScore are handled.
+type MissingValuePolicy string
+
+const (
+	// MissingRenormalize drops missing factors and rescales the remaining weights to sum to 1000.
+	MissingRenormalize MissingValuePolicy = "renormalize"
+	// MissingImputeMedian replaces missing factors with the cohort median of that factor.
+	MissingImputeMedian MissingValuePolicy = "impute_median"
+	// MissingFail rejects any apartment with a missing factor that carries weight.
+	MissingFail MissingValuePolicy = "fail"
+)
+
+const (
+	errMissingValue         = "%s 데이터가 없습니다"
+	errNoScorableFactors    = "가중치가 있는 요소의 데이터가 모두 없습니다"
+	errImputeNeedsCohort    = "코호트 중앙값 대체는 순위 계산에서만 사용할 수 있습니다"
+	errUnknownMissingPolicy = "알 수 없는 결측값 정책: %s"
+)
+
+// Validate checks that the policy is one of the known values. The empty policy means MissingRenormalize.
+func (p MissingValuePolicy) Validate() error {
+	switch p {
+	case "", MissingRenormalize, MissingImputeMedian, MissingFail:
+		return nil
+	default:
+		return &ValidationError{Field: "missing_policy", Message: fmt.Sprintf(errUnknownMissingPolicy, p)}
+	}
+}
+
+// MissingFactors lists the factors marked with shared.MissingScore in the apartment's scores.
+func (a ApartmentData) MissingFactors() []metadata.MetadataType {
+	var missing []metadata.MetadataType
+	for _, mt := range metadata.All() {
+		if score, exists := a.Scores[mt]; exists && score.IsMissing() {
+			missing = append(missing, mt)
+		}
+	}
+	return missing
+}
+
+// MissingFactors lists the factors that had no data when the result was calculated.
+func (r ScoreResult) MissingFactors() []metadata.MetadataType {
+	var missing []metadata.MetadataType
+	for i, n := 0, metadata.Count(); i < n; i++ {
+		if r.RawScores[i].IsMissing() {
+			missing = append(missing, metadata.MetadataType(i))
+		}
+	}
+	return missing
+}
+
+// CalculateWithMissingPolicy calculates a score, handling factors marked with shared.MissingScore by policy.
+// MissingImputeMedian needs a cohort and is only available through CalculateRankingsWithOptions.
+func CalculateWithMissingPolicy(scores map[metadata.MetadataType]shared.ScoreValue,
+	weights map[metadata.MetadataType]shared.Weight,
+	strategy StrategyType, policy MissingValuePolicy) (ScoreResult, error) {
+	scoreArray, weightArray, err := toArrays(scores, weights)
+	if err != nil {
+		return ScoreResult{}, err
+	}
+	return calculateArray(scoreArray, weightArray, strategy, policy)
+}
+
+// calculateArray validates the inputs, resolves missing factors and runs the strategy.
+func calculateArray(scores shared.ScoreArray, weights shared.WeightArray,
+	strategy StrategyType, policy MissingValuePolicy) (ScoreResult, error) {
+	if err := validateStrategyInputsArray(scores, weights); err != nil {
+		return ScoreResult{}, err
+	}
+	if err := policy.Validate(); err != nil {
+		return ScoreResult{}, err
+	}
+
+	impl, exists := LookupStrategy(strategy)
+	if !exists {
+		return ScoreResult{}, fmt.Errorf(errUnsupportedStrategy, strategy)
+	}
+	resolved, resolvedWeights, missing, err := resolveMissing(scores, weights, policy)
+	if err != nil {
+		return ScoreResult{}, err
+	}
+	result, err := impl.Calculate(resolved, resolvedWeights)
+	if err != nil {
+		return ScoreResult{}, err
+	}
+	if result.Method == "" {
+		result.Method = strategy
+	}
+	markMissing(&result, missing)
+	return result, nil
+}
+
+// resolveMissing prepares scores for a strategy. Missing factors get a score and weight of 0
+// and the remaining weights are rescaled, so strategies never see the MissingScore marker.
+func resolveMissing(scores shared.ScoreArray, weights shared.WeightArray,
+	policy MissingValuePolicy) (shared.ScoreArray, shared.WeightArray, []metadata.MetadataType, error) {
+	var missing []metadata.MetadataType
+	for i, n := 0, metadata.Count(); i < n; i++ {
+		if scores[i].IsMissing() {
+			missing = append(missing, metadata.MetadataType(i))
+		}
+	}
+	if len(missing) == 0 {
+		return scores, weights, nil, nil
+	}
+
+	switch policy {
+	case MissingFail:
+		for _, mt := range missing {
+			if weights[mt] > 0 {
+				return scores, weights, nil, &ValidationError{Field: mt.String(), Message: fmt.Sprintf(errMissingValue, mt.KoreanName())}
+			}
+		}
+	case MissingImputeMedian:
+		return scores, weights, nil, &ValidationError{Field: "missing_policy", Message: errImputeNeedsCohort}
+	}
+
+	for _, mt := range missing {
+		scores[mt] = 0
+		weights[mt] = 0
+	}
+	renormalized, ok := renormalizeWeights(weights)
+	if !ok {
+		return scores, weights, nil, &ValidationError{Field: "scores", Message: errNoScorableFactors}
+	}
+	return scores, renormalized, missing, nil
+}
+
+// markMissing restores the MissingScore marker in a result so callers can tell gaps from zeros.
+func markMissing(result *ScoreResult, missing []metadata.MetadataType) {
+	for _, mt := range missing {
+		result.RawScores[mt] = shared.MissingScore
+		result.WeightedScores[mt] = 0
+	}
+}
+
+// renormalizeWeights rescales weights to sum exactly to WeightScale using the largest remainder method.
+// It returns false when every weight is zero.
+func renormalizeWeights(weights shared.WeightArray) (shared.WeightArray, bool) {
+	return scaleWeights(weights, shared.WeightScale)
+}
+
+// scaleWeights rescales weights proportionally so they sum exactly to total, distributing
+// rounding remainders to the largest fractional parts. It returns false when every weight is zero.
+func scaleWeights(weights shared.WeightArray, total int) (shared.WeightArray, bool) {
+	n := metadata.Count()
+	current := 0
+	for i := 0; i < n; i++ {
+		current += int(weights[i])
+	}
+	if current == 0 {
+		return weights, total == 0
+	}
+	if current == total {
+		return weights, true
+	}
+
+	var scaled shared.WeightArray
+	remainders := make([]int, n)
+	order := make([]int, n)
+	assigned := 0
+	for i := 0; i < n; i++ {
+		exact := int(weights[i]) * total
+		scaled[i] = shared.Weight(exact / current)
+		remainders[i] = exact % current
+		assigned += int(scaled[i])
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return remainders[order[a]] > remainders[order[b]] })
+	for k := 0; assigned < total; k++ {
+		scaled[order[k]]++
+		assigned++
+	}
+	return scaled, true
+}
+
+// cohortMedians returns the median of every factor over the apartments that have data for it.
+func cohortMedians(scores []shared.ScoreArray) ([metadata.MaxMetadataTypes]shared.ScoreValue, [metadata.MaxMetadataTypes]bool) {
+	var medians [metadata.MaxMetadataTypes]shared.ScoreValue
+	var available [metadata.MaxMetadataTypes]bool
+	values := make([]shared.ScoreValue, 0, len(scores))
+	for j, n := 0, metadata.Count(); j < n; j++ {
+		values = values[:0]
+		for _, apt := range scores {
+			if !apt[j].IsMissing() {
+				values = append(values, apt[j])
+			}
+		}
+		if len(values) == 0 {
+			continue
+		}
+		sort.Slice(values, func(a, b int) bool { return values[a] < values[b] })
+		mid := len(values) / 2
+		if len(values)%2 == 1 {
+			medians[j] = values[mid]
+		} else {
+			medians[j] = (values[mid-1] + values[mid]) / 2
+		}
+		available[j] = true
+	}
+	return medians, available
+}
+
+// imputeMissing replaces missing factors that have a cohort median and reports which ones were filled.
+func imputeMissing(scores shared.ScoreArray, medians [metadata.MaxMetadataTypes]shared.ScoreValue,
+	available [metadata.MaxMetadataTypes]bool) (shared.ScoreArray, []metadata.MetadataType) {
+	var imputed []metadata.MetadataType
+	for j, n := 0, metadata.Count(); j < n; j++ {
+		if scores[j].IsMissing() && available[j] {
+			scores[j] = medians[j]
+			imputed = append(imputed, metadata.MetadataType(j))
+		}
+	}
+	return scores, imputed
+}
@@ -0,0 +1,137 @@
+package scoring
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+)
+
+func TestMissingRenormalize(t *testing.T) {
+	scores := getTestScores()
+	scores[metadata.SchoolDistrict] = shared.MissingScore
+	weights := getTestWeights()
+
+	result, err := CalculateWithStrategy(scores, weights, StrategyWeightedSum)
+	if err != nil {
+		t.Fatalf("CalculateWithStrategy failed: %v", err)
+	}
+	if !result.RawScores[metadata.SchoolDistrict].IsMissing() {
+		t.Error("Expected RawScores to mark the missing factor")
+	}
+	if result.Weights[metadata.SchoolDistrict] != 0 {
+		t.Errorf("Expected missing factor weight 0, got %d", result.Weights[metadata.SchoolDistrict])
+	}
+	total := shared.Weight(0)
+	expected, remaining := 0.0, 0.0
+	for _, mt := range metadata.All() {
+		total += result.Weights[mt]
+		if mt != metadata.SchoolDistrict {
+			expected += scores[mt].ToFloat() * weights[mt].ToFloat()
+			remaining += weights[mt].ToFloat()
+		}
+	}
+	if total != shared.WeightScale {
+		t.Errorf("Expected renormalized weights to sum to %d, got %d", shared.WeightScale, total)
+	}
+	if math.Abs(result.TotalScore-expected/remaining) > 0.1 {
+		t.Errorf("Expected score %.2f, got %.2f", expected/remaining, result.TotalScore)
+	}
+	if missing := result.MissingFactors(); len(missing) != 1 || missing[0] != metadata.SchoolDistrict {
+		t.Errorf("Unexpected missing factors: %v", missing)
+	}
+}
+
+func TestMissingFailPolicy(t *testing.T) {
+	scores := getTestScores()
+	scores[metadata.CrimeRate] = shared.MissingScore
+
+	_, err := CalculateWithMissingPolicy(scores, getTestWeights(), StrategyWeightedSum, MissingFail)
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) || validationErr.Field != metadata.CrimeRate.String() {
+		t.Errorf("Expected ValidationError for CrimeRate, got %v", err)
+	}
+	if _, err := CalculateWithMissingPolicy(scores, getTestWeights(), StrategyWeightedSum, MissingImputeMedian); err == nil {
+		t.Error("Expected error for median imputation without a cohort")
+	}
+}
+
+func TestMissingImputeMedianInRankings(t *testing.T) {
+	gap := uniformScores(80)
+	gap[metadata.Parking] = shared.MissingScore
+	low := uniformScores(80)
+	low[metadata.Parking] = shared.ScoreValueFromFloat(40)
+	high := uniformScores(80)
+	high[metadata.Parking] = shared.ScoreValueFromFloat(60)
+
+	apartments := []ApartmentData{
+		{ID: "gap", Name: "결측", Scores: gap},
+		{ID: "low", Name: "낮음", Scores: low},
+		{ID: "high", Name: "높음", Scores: high},
+	}
+	for _, strategy := range []StrategyType{StrategyWeightedSum, StrategyTOPSIS} {
+		summary, err := CalculateRankingsWithOptions(apartments, getTestWeights(), strategy,
+			RankingOptions{MissingPolicy: MissingImputeMedian})
+		if err != nil {
+			t.Fatalf("%s: CalculateRankingsWithOptions failed: %v", strategy, err)
+		}
+		if summary.TopRanked[1].Apartment.ID != "gap" {
+			t.Errorf("%s: expected imputed apartment in the middle, got %s", strategy, summary.TopRanked[1].Apartment.ID)
+		}
+	}
+
+	results, err := scoreCohort(apartments, getTestWeights(), StrategyWeightedSum, MissingImputeMedian)
+	if err != nil {
+		t.Fatalf("scoreCohort failed: %v", err)
+	}
+	if results[0].RawScores[metadata.Parking] != shared.ScoreValueFromFloat(50) {
+		t.Errorf("Expected median 50, got %.1f", results[0].RawScores[metadata.Parking].ToFloat())
+	}
+	if len(results[0].Imputed) != 1 || results[0].Imputed[0] != metadata.Parking {
+		t.Errorf("Expected Parking to be reported as imputed, got %v", results[0].Imputed)
+	}
+
+	if _, err := CalculateRankingsWithOptions(apartments, getTestWeights(), StrategyTOPSIS,
+		RankingOptions{MissingPolicy: MissingFail}); err == nil {
+		t.Error("Expected fail policy to reject the cohort")
+	}
+}
+
+func TestMissingInAnalysis(t *testing.T) {
+	scores := getTestScores()
+	scores[metadata.GreenSpaceRatio] = shared.MissingScore
+	scores[metadata.HeatingSystem] = 0
+	weights := getTestWeights()
+
+	result, err := CalculateWithStrategy(scores, weights, StrategyWeightedSum)
+	if err != nil {
+		t.Fatalf("CalculateWithStrategy failed: %v", err)
+	}
+
+	analysis := AnalyzeScore(result)
+	if len(analysis.Missing) != 1 || analysis.Missing[0] != metadata.GreenSpaceRatio {
+		t.Errorf("Expected GreenSpaceRatio to be missing, got %v", analysis.Missing)
+	}
+	weakZero := false
+	for _, mt := range analysis.Weaknesses {
+		if mt == metadata.HeatingSystem {
+			weakZero = true
+		}
+	}
+	if !weakZero {
+		t.Error("Expected a genuine zero score to be reported as a weakness")
+	}
+
+	formatted := FormatScoreResult(result)
+	if !strings.Contains(formatted, "데이터 없음") || !strings.Contains(formatted, metadata.HeatingSystem.KoreanName()) {
+		t.Errorf("Expected formatted result to show missing and zero factors:\n%s", formatted)
+	}
+
+	quality := assessDataQuality(scores, weights)
+	if math.Abs(quality.Completeness-13.0/14.0*100) > 0.01 {
+		t.Errorf("Expected completeness %.1f%%, got %.1f%%", 13.0/14.0*100, quality.Completeness)
+	}
+}
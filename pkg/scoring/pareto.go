@@ -0,0 +1,165 @@
+package scoring
+
+import (
+	"apart_score/pkg/metadata"
+	"fmt"
+	"strings"
+)
+
+// ConstraintDominated marks an apartment removed by the Pareto prefilter.
+const ConstraintDominated ConstraintKind = "dominated"
+
+// ParetoOptions restricts dominance to a subset of factors.
+type ParetoOptions struct {
+	Factors []metadata.MetadataType // 비교할 요소 (비어 있으면 모든 요소)
+}
+
+// Dominance records that one apartment is at least as good as another on every compared
+// factor and strictly better on at least one.
+type Dominance struct {
+	Dominant  string                  `json:"dominant"`
+	Dominated string                  `json:"dominated"`
+	Better    []metadata.MetadataType `json:"better"` // Dominant가 더 좋은 요소
+}
+
+// ParetoAnalysis is the dominance structure of a set of apartments.
+type ParetoAnalysis struct {
+	Factors     []metadata.MetadataType `json:"factors"`
+	Front       []string                `json:"front"`     // 어떤 아파트에도 지배되지 않는 아파트 (입력 순서)
+	Dominated   []string                `json:"dominated"` // 하나 이상의 아파트에 지배되는 아파트 (입력 순서)
+	Dominances  []Dominance             `json:"dominances"`
+	ids         []string
+	dominatedBy [][]string // 입력 순서별 지배하는 아파트
+}
+
+// DominatedBy returns the IDs of the apartments dominating the apartment with the given ID.
+func (a *ParetoAnalysis) DominatedBy(id string) []string {
+	for i, other := range a.ids {
+		if other == id {
+			return a.dominatedBy[i]
+		}
+	}
+	return nil
+}
+
+// Validate checks that every factor is registered.
+func (o ParetoOptions) Validate() error {
+	for _, mt := range o.Factors {
+		if !mt.IsValid() {
+			return &ValidationError{Field: "factors", Message: fmt.Sprintf(errUnknownMetadata, int(mt))}
+		}
+	}
+	return nil
+}
+
+// AnalyzePareto finds the Pareto front of the apartments over the factors in opts. A factor
+// missing from exactly one of two apartments makes them incomparable, so neither dominates
+// the other; a factor missing from both counts as equal. Apartments with identical scores do
+// not dominate each other.
+func AnalyzePareto(apartments []ApartmentData, opts ParetoOptions) (*ParetoAnalysis, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, err
+	}
+	factors := opts.Factors
+	if len(factors) == 0 {
+		factors = metadata.All()
+	}
+	analysis := &ParetoAnalysis{
+		Factors:     factors,
+		Front:       []string{},
+		Dominated:   []string{},
+		Dominances:  []Dominance{},
+		ids:         make([]string, len(apartments)),
+		dominatedBy: make([][]string, len(apartments)),
+	}
+	for i, a := range apartments {
+		analysis.ids[i] = a.ID
+		for j, b := range apartments {
+			if i == j {
+				continue
+			}
+			better, ok := dominates(a, b, factors)
+			if !ok {
+				continue
+			}
+			analysis.Dominances = append(analysis.Dominances, Dominance{Dominant: a.ID, Dominated: b.ID, Better: better})
+			analysis.dominatedBy[j] = append(analysis.dominatedBy[j], a.ID)
+		}
+	}
+	for i, apt := range apartments {
+		if len(analysis.dominatedBy[i]) > 0 {
+			analysis.Dominated = append(analysis.Dominated, apt.ID)
+		} else {
+			analysis.Front = append(analysis.Front, apt.ID)
+		}
+	}
+	return analysis, nil
+}
+
+// dominates reports whether a dominates b on the factors and on which factors a is better.
+func dominates(a, b ApartmentData, factors []metadata.MetadataType) ([]metadata.MetadataType, bool) {
+	var better []metadata.MetadataType
+	for _, mt := range factors {
+		scoreA, scoreB := a.Scores[mt], b.Scores[mt]
+		if scoreA.IsMissing() != scoreB.IsMissing() || scoreA < scoreB {
+			return nil, false
+		}
+		if scoreA > scoreB {
+			better = append(better, mt)
+		}
+	}
+	return better, len(better) > 0
+}
+
+// FilterDominated splits apartments into the Pareto front and eliminations for the dominated
+// ones, in input order.
+func FilterDominated(apartments []ApartmentData, opts ParetoOptions) ([]ApartmentData, []Elimination, error) {
+	analysis, err := AnalyzePareto(apartments, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	front, eliminated := analysis.split(apartments)
+	return front, eliminated, nil
+}
+
+// split partitions the analyzed apartments, or others in the same order, by the analysis.
+func (a *ParetoAnalysis) split(apartments []ApartmentData) ([]ApartmentData, []Elimination) {
+	front := make([]ApartmentData, 0, len(a.Front))
+	var eliminated []Elimination
+	for i, apt := range apartments {
+		dominators := a.dominatedBy[i]
+		if len(dominators) == 0 {
+			front = append(front, apt)
+			continue
+		}
+		eliminated = append(eliminated, Elimination{Apartment: apt, Violations: []ConstraintViolation{{
+			Kind:        ConstraintDominated,
+			DominatedBy: dominators,
+			Message:     fmt.Sprintf("%s에 지배됨 (비교한 모든 요소에서 같거나 낮음)", strings.Join(dominators, ", ")),
+		}}})
+	}
+	return front, eliminated
+}
+
+// FormatParetoAnalysis formats a Pareto analysis for display.
+func FormatParetoAnalysis(a *ParetoAnalysis) string {
+	output := "🏔️ 파레토 분석\n"
+	output += "━━━━━━━━━━━━━━━━━━━━━━━━━\n"
+	if len(a.Factors) < metadata.Count() {
+		names := make([]string, len(a.Factors))
+		for i, mt := range a.Factors {
+			names[i] = mt.KoreanName()
+		}
+		output += fmt.Sprintf("비교 요소: %s\n", strings.Join(names, ", "))
+	}
+	output += fmt.Sprintf("파레토 최적 %d개: %s\n", len(a.Front), strings.Join(a.Front, ", "))
+	if len(a.Dominated) == 0 {
+		output += "지배되는 아파트가 없습니다\n"
+		return output
+	}
+	output += fmt.Sprintf("\n🔻 지배되는 아파트 %d개:\n", len(a.Dominated))
+	for _, id := range a.Dominated {
+		output += fmt.Sprintf("  • %s ← %s\n", id, strings.Join(a.DominatedBy(id), ", "))
+	}
+	return output
+}
@@ -0,0 +1,107 @@
+package scoring
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/normalization"
+	"apart_score/pkg/shared"
+)
+
+// paretoApartments returns a set where "low" is dominated by "high", "station" trades
+// distance to station against school district with "high", and "partial" lacks parking data.
+func paretoApartments() []ApartmentData {
+	high := ApartmentData{ID: "high", Scores: uniformScores(80)}
+	low := ApartmentData{ID: "low", Scores: uniformScores(70)}
+	low.Scores[metadata.Parking] = shared.ScoreValueFromFloat(80)
+	station := ApartmentData{ID: "station", Scores: uniformScores(80)}
+	station.Scores[metadata.DistanceToStation] = shared.ScoreValueFromFloat(100)
+	station.Scores[metadata.SchoolDistrict] = shared.ScoreValueFromFloat(50)
+	partial := ApartmentData{ID: "partial", Scores: uniformScores(60)}
+	partial.Scores[metadata.Parking] = shared.MissingScore
+	return []ApartmentData{high, low, station, partial}
+}
+
+func TestAnalyzePareto(t *testing.T) {
+	analysis, err := AnalyzePareto(paretoApartments(), ParetoOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"high", "station", "partial"}; !reflect.DeepEqual(analysis.Front, want) {
+		t.Errorf("front = %v, want %v", analysis.Front, want)
+	}
+	if !reflect.DeepEqual(analysis.Dominated, []string{"low"}) || !reflect.DeepEqual(analysis.DominatedBy("low"), []string{"high"}) {
+		t.Fatalf("dominated = %v by %v", analysis.Dominated, analysis.DominatedBy("low"))
+	}
+	if len(analysis.Dominances) != 1 || len(analysis.Dominances[0].Better) != metadata.Count()-1 {
+		t.Errorf("dominances: %+v", analysis.Dominances)
+	}
+
+	// 학군을 빼면 역세권 아파트가 high를 지배
+	factors := []metadata.MetadataType{metadata.DistanceToStation, metadata.FloorLevel, metadata.Parking}
+	analysis, err = AnalyzePareto(paretoApartments(), ParetoOptions{Factors: factors})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"station", "partial"}; !reflect.DeepEqual(analysis.Front, want) {
+		t.Errorf("restricted front = %v, want %v", analysis.Front, want)
+	}
+	if by := analysis.DominatedBy("low"); !reflect.DeepEqual(by, []string{"high", "station"}) {
+		t.Errorf("low dominated by %v", by)
+	}
+	if text := FormatParetoAnalysis(analysis); !strings.Contains(text, "low ← high, station") || !strings.Contains(text, "비교 요소") {
+		t.Errorf("formatted analysis:\n%s", text)
+	}
+
+	// 점수가 같은 아파트끼리는 지배하지 않음
+	twins := []ApartmentData{{ID: "a", Scores: uniformScores(70)}, {ID: "b", Scores: uniformScores(70)}}
+	if analysis, err = AnalyzePareto(twins, ParetoOptions{}); err != nil || len(analysis.Front) != 2 {
+		t.Errorf("twins: %+v, %v", analysis, err)
+	}
+
+	var validation *ValidationError
+	if _, err := AnalyzePareto(twins, ParetoOptions{Factors: []metadata.MetadataType{metadata.MetadataType(999)}}); !errors.As(err, &validation) {
+		t.Errorf("unknown factor: %v", err)
+	}
+}
+
+func TestRankingParetoFilter(t *testing.T) {
+	weights := GetScenarioWeights(ScenarioBalanced)
+	summary, err := CalculateRankingsWithOptions(paretoApartments(), weights, StrategyTOPSIS,
+		RankingOptions{Pareto: &ParetoOptions{}, Constraints: &Constraints{ExcludedLocations: []string{"nowhere"}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.TotalApartments != 3 || len(summary.Eliminated) != 1 {
+		t.Fatalf("total %d, eliminated %+v", summary.TotalApartments, summary.Eliminated)
+	}
+	violation := summary.Eliminated[0].Violations[0]
+	if summary.Eliminated[0].Apartment.ID != "low" || violation.Kind != ConstraintDominated || !reflect.DeepEqual(violation.DominatedBy, []string{"high"}) {
+		t.Errorf("elimination: %+v", summary.Eliminated[0])
+	}
+	for _, ranking := range summary.TopRanked {
+		if ranking.Apartment.ID == "low" {
+			t.Error("dominated apartment was ranked")
+		}
+	}
+	if !strings.Contains(FormatRankings(summary, 0), "high에 지배됨") {
+		t.Error("formatted rankings do not explain the elimination")
+	}
+
+	// 가치 함수로 효용이 같아진 요소는 지배 관계를 만들지 않음
+	apartments := []ApartmentData{{ID: "a", Scores: uniformScores(70)}, {ID: "b", Scores: uniformScores(70)}}
+	apartments[0].Scores[metadata.Parking] = shared.ScoreValueFromFloat(90)
+	apartments[1].Scores[metadata.Parking] = shared.ScoreValueFromFloat(85)
+	opts := RankingOptions{Pareto: &ParetoOptions{Factors: []metadata.MetadataType{metadata.Parking}}}
+	if summary, err = CalculateRankingsWithOptions(apartments, weights, StrategyWeightedSum, opts); err != nil || len(summary.Eliminated) != 1 {
+		t.Fatalf("raw scores: %+v, %v", summary, err)
+	}
+	opts.ValueFunctions = ValueFunctions{metadata.Parking: {Kind: ValuePiecewiseLinear,
+		Points: normalization.Curve{{Input: 0, Score: 0}, {Input: 80, Score: 100}}}}
+	if summary, err = CalculateRankingsWithOptions(apartments, weights, StrategyWeightedSum, opts); err != nil || len(summary.Eliminated) != 0 {
+		t.Errorf("saturated utilities: %+v, %v", summary.Eliminated, err)
+	}
+}
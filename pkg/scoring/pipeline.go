@@ -0,0 +1,407 @@
+package scoring
+
+import (
+	"apart_score/pkg/expr"
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"fmt"
+	"math"
+)
+
+// PipelineStepKind identifies a built-in step of a declarative pipeline.
+type PipelineStepKind string
+
+const (
+	StepWeightedBlock PipelineStepKind = "weighted"   // 요소 묶음의 가중 평균에 비중을 곱해 가산
+	StepBonusIf       PipelineStepKind = "bonus_if"   // 조건을 만족하면 점수 가산
+	StepPenaltyIf     PipelineStepKind = "penalty_if" // 조건을 만족하면 점수 감산
+	StepClamp         PipelineStepKind = "clamp"      // 누적 총점을 최솟값-최댓값 범위로 제한
+	StepCap           PipelineStepKind = "cap"        // 누적 총점을 최댓값 이하로 제한
+	StepStrategy      PipelineStepKind = "strategy"   // 계산 전략의 총점에 비중을 곱해 가산
+	StepFormula       PipelineStepKind = "formula"    // 식의 값을 가산
+)
+
+// PipelineStepKinds lists the supported step kinds.
+var PipelineStepKinds = []PipelineStepKind{StepWeightedBlock, StepBonusIf, StepPenaltyIf, StepClamp, StepCap, StepStrategy, StepFormula}
+
+// ConditionOperator compares a score with a condition's value.
+type ConditionOperator string
+
+const (
+	OpGreater      ConditionOperator = ">"
+	OpGreaterEqual ConditionOperator = ">="
+	OpLess         ConditionOperator = "<"
+	OpLessEqual    ConditionOperator = "<="
+	OpEqual        ConditionOperator = "=="
+	OpNotEqual     ConditionOperator = "!="
+)
+
+// ConditionOperators lists the supported comparison operators.
+var ConditionOperators = []ConditionOperator{OpGreater, OpGreaterEqual, OpLess, OpLessEqual, OpEqual, OpNotEqual}
+
+// PipelineCondition decides whether a pipeline step runs. A comparison compares a factor's
+// score, or the running total when Factor is nil, with Value; a factor without data never
+// satisfies a comparison. All and Any combine nested conditions, and Expr is a condition
+// expression that reads the running total as total; an expression that fails to evaluate,
+// for example by dividing by zero, does not hold.
+type PipelineCondition struct {
+	Factor *metadata.MetadataType `json:"factor,omitempty"` // 비교할 요소 (nil이면 누적 총점)
+	Op     ConditionOperator      `json:"op,omitempty"`
+	Value  float64                `json:"value,omitempty"`
+	All    []PipelineCondition    `json:"all,omitempty"`  // 모두 만족해야 참
+	Any    []PipelineCondition    `json:"any,omitempty"`  // 하나 이상 만족하면 참
+	Expr   *expr.Expr             `json:"expr,omitempty"` // 조건 식 (예: "학군 > 85 and total > 60")
+}
+
+// Validate checks that the condition is exactly one of a comparison, All, Any or Expr.
+func (c PipelineCondition) Validate() error {
+	kinds := 0
+	for _, set := range []bool{c.Op != "", len(c.All) > 0, len(c.Any) > 0, c.Expr != nil} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds != 1 {
+		return fmt.Errorf("조건은 비교(op), all, any, expr 중 정확히 하나여야 합니다")
+	}
+	if c.Expr != nil && c.Expr.Type() != expr.Bool {
+		return fmt.Errorf("조건 식 %q는 참/거짓이 아닌 %s 식입니다", c.Expr, c.Expr.Type())
+	}
+	for _, nested := range append(append([]PipelineCondition{}, c.All...), c.Any...) {
+		if err := nested.Validate(); err != nil {
+			return err
+		}
+	}
+	if c.Op == "" {
+		return nil
+	}
+	if !validOperator(c.Op) {
+		return fmt.Errorf("알 수 없는 비교 연산자: %q (사용 가능: %v)", c.Op, ConditionOperators)
+	}
+	if c.Factor != nil && !c.Factor.IsValid() {
+		return fmt.Errorf(errUnknownMetadata, int(*c.Factor))
+	}
+	if math.IsNaN(c.Value) || math.IsInf(c.Value, 0) {
+		return fmt.Errorf("비교 값은 유한한 수여야 합니다 (%g)", c.Value)
+	}
+	return nil
+}
+
+func validOperator(op ConditionOperator) bool {
+	for _, known := range ConditionOperators {
+		if op == known {
+			return true
+		}
+	}
+	return false
+}
+
+// Holds evaluates the condition against a pipeline's result so far.
+func (c PipelineCondition) Holds(result ScoreResult) bool {
+	switch {
+	case len(c.All) > 0:
+		for _, nested := range c.All {
+			if !nested.Holds(result) {
+				return false
+			}
+		}
+		return true
+	case len(c.Any) > 0:
+		for _, nested := range c.Any {
+			if nested.Holds(result) {
+				return true
+			}
+		}
+		return false
+	case c.Expr != nil:
+		holds, err := c.Expr.Test(expr.ArrayEnv(result.RawScores, result.TotalScore))
+		return err == nil && holds
+	}
+	value := result.TotalScore
+	if c.Factor != nil {
+		score := result.RawScores[*c.Factor]
+		if score.IsMissing() {
+			return false
+		}
+		value = score.ToFloat()
+	}
+	switch c.Op {
+	case OpGreater:
+		return value > c.Value
+	case OpGreaterEqual:
+		return value >= c.Value-comparisonTolerance
+	case OpLess:
+		return value < c.Value
+	case OpLessEqual:
+		return value <= c.Value+comparisonTolerance
+	case OpEqual:
+		return math.Abs(value-c.Value) <= comparisonTolerance
+	case OpNotEqual:
+		return math.Abs(value-c.Value) > comparisonTolerance
+	}
+	return false
+}
+
+// PipelineStepSpec is a serializable pipeline step. Only the fields of its Kind are used.
+type PipelineStepSpec struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Kind        PipelineStepKind   `json:"kind"`
+	When        *PipelineCondition `json:"when,omitempty"`     // 실행 조건 (nil이면 항상 실행, bonus_if와 penalty_if는 필수)
+	Factors     []FactorWeight     `json:"factors,omitempty"`  // weighted: 블록 안 요소별 상대 가중치
+	Weight      float64            `json:"weight,omitempty"`   // weighted, strategy: 블록 점수(0-100)에 곱할 비중 (0-1]
+	Points      float64            `json:"points,omitempty"`   // bonus_if, penalty_if: 가감 점수 (0-100]
+	Min         float64            `json:"min,omitempty"`      // clamp: 하한
+	Max         float64            `json:"max,omitempty"`      // clamp, cap: 상한
+	Strategy    StrategyType       `json:"strategy,omitempty"` // strategy: 호출할 계산 전략
+	Formula     *expr.Expr         `json:"formula,omitempty"`  // formula: 누적 총점(total)에 더할 숫자 식
+}
+
+// PipelineSpec is a declarative calculation pipeline that can be stored and edited as a file.
+// Steps run in order; Compile turns it into a CalculationPipeline.
+type PipelineSpec struct {
+	Name        string             `json:"name"`
+	Description string             `json:"description,omitempty"`
+	Steps       []PipelineStepSpec `json:"steps"`
+}
+
+// Validate checks every step's kind, condition and parameters.
+func (s PipelineSpec) Validate() error {
+	if len(s.Steps) == 0 {
+		return &ValidationError{Field: "steps", Message: "파이프라인에 단계가 없습니다"}
+	}
+	for i, step := range s.Steps {
+		if err := step.validate(); err != nil {
+			name := step.Name
+			if name == "" {
+				name = fmt.Sprintf("#%d", i+1)
+			}
+			return &ValidationError{Field: "steps", Message: fmt.Sprintf("단계 %s: %v", name, err)}
+		}
+	}
+	return nil
+}
+
+func (s PipelineStepSpec) validate() error {
+	if s.When != nil {
+		if err := s.When.Validate(); err != nil {
+			return err
+		}
+	}
+	inRange := func(name string, v, lower, upper float64, openLower bool) error {
+		if math.IsNaN(v) || v < lower || v > upper || (openLower && v == lower) {
+			bracket := "["
+			if openLower {
+				bracket = "("
+			}
+			return fmt.Errorf("%s는 %s%g, %g] 범위여야 합니다 (%g)", name, bracket, lower, upper, v)
+		}
+		return nil
+	}
+	switch s.Kind {
+	case StepWeightedBlock:
+		if len(s.Factors) == 0 {
+			return fmt.Errorf("가중 블록에 요소가 없습니다")
+		}
+		seen := make(map[metadata.MetadataType]bool, len(s.Factors))
+		total := 0.0
+		for _, f := range s.Factors {
+			if !f.Factor.IsValid() {
+				return fmt.Errorf(errUnknownMetadata, int(f.Factor))
+			}
+			if seen[f.Factor] {
+				return fmt.Errorf("요소가 중복되었습니다: %s", f.Factor)
+			}
+			seen[f.Factor] = true
+			if math.IsNaN(f.Weight) || math.IsInf(f.Weight, 0) || f.Weight < 0 {
+				return fmt.Errorf("%s 가중치는 0 이상의 유한한 값이어야 합니다 (%g)", f.Factor, f.Weight)
+			}
+			total += f.Weight
+		}
+		if total == 0 {
+			return fmt.Errorf("가중 블록의 가중치 합이 0입니다")
+		}
+		return inRange("weight", s.Weight, 0, 1, true)
+	case StepBonusIf, StepPenaltyIf:
+		if s.When == nil {
+			return fmt.Errorf("%s 단계에는 조건(when)이 필요합니다", s.Kind)
+		}
+		return inRange("points", s.Points, 0, 100, true)
+	case StepClamp:
+		if err := inRange("min", s.Min, 0, 100, false); err != nil {
+			return err
+		}
+		return inRange("max", s.Max, s.Min, 100, false)
+	case StepCap:
+		return inRange("max", s.Max, 0, 100, false)
+	case StepStrategy:
+		if _, exists := LookupStrategy(s.Strategy); !exists {
+			return fmt.Errorf(errUnsupportedStrategy, s.Strategy)
+		}
+		return inRange("weight", s.Weight, 0, 1, true)
+	case StepFormula:
+		if s.Formula == nil {
+			return fmt.Errorf("formula 단계에는 식(formula)이 필요합니다")
+		}
+		if s.Formula.Type() != expr.Number {
+			return fmt.Errorf("식 %q는 숫자가 아닌 %s 식입니다", s.Formula, s.Formula.Type())
+		}
+		return nil
+	}
+	return fmt.Errorf("알 수 없는 단계 종류: %q (사용 가능: %v)", s.Kind, PipelineStepKinds)
+}
+
+// Compile validates the spec and builds the equivalent CalculationPipeline, with priorities
+// following the step order.
+func (s PipelineSpec) Compile() (CalculationPipeline, error) {
+	if err := s.Validate(); err != nil {
+		return CalculationPipeline{}, err
+	}
+	pipeline := CalculationPipeline{Name: s.Name, Description: s.Description, Steps: make([]CalculationStep, len(s.Steps))}
+	for i, spec := range s.Steps {
+		step := CalculationStep{Name: spec.Name, Description: spec.Description, Priority: i + 1}
+		if spec.When != nil {
+			step.Condition = spec.When.Holds
+		}
+		switch spec.Kind {
+		case StepWeightedBlock:
+			step.Calculator = weightedBlock(spec.Factors, spec.Weight)
+		case StepBonusIf, StepPenaltyIf:
+			points := spec.Points
+			if spec.Kind == StepPenaltyIf {
+				points = -points
+			}
+			step.Calculator = func(map[metadata.MetadataType]shared.ScoreValue, map[metadata.MetadataType]shared.Weight) float64 {
+				return points
+			}
+		case StepClamp, StepCap:
+			lower, upper := spec.Min, spec.Max
+			if spec.Kind == StepCap {
+				lower = math.Inf(-1)
+			}
+			step.Apply = func(result ScoreResult) (float64, error) {
+				return math.Min(math.Max(result.TotalScore, lower), upper), nil
+			}
+		case StepStrategy:
+			strategy, weight := spec.Strategy, spec.Weight
+			step.Apply = func(result ScoreResult) (float64, error) {
+				scored, err := CalculateWithStrategyArray(result.RawScores, result.Weights, strategy)
+				if err != nil {
+					return 0, err
+				}
+				return result.TotalScore + scored.TotalScore*weight, nil
+			}
+		case StepFormula:
+			formula := spec.Formula
+			step.Apply = func(result ScoreResult) (float64, error) {
+				v, err := formula.Eval(expr.ArrayEnv(result.RawScores, result.TotalScore))
+				if err != nil || math.IsNaN(v) {
+					return result.TotalScore, err // 데이터가 없는 요소에 의존하면 0점 가산
+				}
+				return result.TotalScore + v, nil
+			}
+		}
+		pipeline.Steps[i] = step
+	}
+	return pipeline, nil
+}
+
+// weightedBlock returns a calculator adding the weighted mean of the factors times weight.
+// Factors without data are left out and the rest renormalized; a block without data adds 0.
+func weightedBlock(factors []FactorWeight, weight float64) func(map[metadata.MetadataType]shared.ScoreValue, map[metadata.MetadataType]shared.Weight) float64 {
+	return func(scores map[metadata.MetadataType]shared.ScoreValue, _ map[metadata.MetadataType]shared.Weight) float64 {
+		sum, total := 0.0, 0.0
+		for _, f := range factors {
+			score := scores[f.Factor]
+			if score.IsMissing() {
+				continue
+			}
+			sum += score.ToFloat() * f.Weight
+			total += f.Weight
+		}
+		if total == 0 {
+			return 0
+		}
+		return sum / total * weight
+	}
+}
+
+// FamilyPipelineSpec is the declarative form of CreateFamilyPipeline.
+func FamilyPipelineSpec() PipelineSpec {
+	transport := metadata.TransportationAccess
+	return PipelineSpec{
+		Name:        "가족 중심 평가",
+		Description: "학군, 크기/가격 균형, 교통 접근성을 고려한 가족 중심 평가",
+		Steps: []PipelineStepSpec{
+			{
+				Name:        "학군 우선 평가",
+				Description: "학군 점수를 40% 가중치로 평가",
+				Kind:        StepWeightedBlock,
+				Factors:     []FactorWeight{{Factor: metadata.SchoolDistrict, Weight: 1}},
+				Weight:      0.4,
+			},
+			{
+				Name:        "크기/가격 균형",
+				Description: "아파트 크기와 가격의 균형을 40%로 평가",
+				Kind:        StepWeightedBlock,
+				Factors:     []FactorWeight{{Factor: metadata.ApartmentSize, Weight: 0.6}, {Factor: metadata.MaintenanceFee, Weight: 0.4}},
+				Weight:      0.4,
+			},
+			{
+				Name:        "교통 보너스",
+				Description: "기본 점수가 60점을 넘고 교통 접근성이 85점 이상이면 1점 가산",
+				Kind:        StepBonusIf,
+				When: &PipelineCondition{All: []PipelineCondition{
+					{Op: OpGreater, Value: 60},
+					{Factor: &transport, Op: OpGreaterEqual, Value: 85},
+				}},
+				Points: 1,
+			},
+			{
+				Name:        "교통 보너스 (보통)",
+				Description: "기본 점수가 60점을 넘고 교통 접근성이 75점 이상 85점 미만이면 0.4점 가산",
+				Kind:        StepBonusIf,
+				When: &PipelineCondition{All: []PipelineCondition{
+					{Op: OpGreater, Value: 60},
+					{Factor: &transport, Op: OpGreaterEqual, Value: 75},
+					{Factor: &transport, Op: OpLess, Value: 85},
+				}},
+				Points: 0.4,
+			},
+		},
+	}
+}
+
+// PipelineStepTrace records how one step of a pipeline run changed the running total.
+type PipelineStepTrace struct {
+	Name         string  `json:"name"`
+	Description  string  `json:"description,omitempty"`
+	Priority     int     `json:"priority"`
+	Conditional  bool    `json:"conditional"`  // 실행 조건이 있는 단계
+	Fired        bool    `json:"fired"`        // 단계 실행 여부 (조건이 없으면 항상 true)
+	Contribution float64 `json:"contribution"` // 단계 전후 누계 차이 (건너뛰면 0)
+	Total        float64 `json:"total"`        // 단계 후 누계
+}
+
+// PipelineTrace is the step-by-step record of a pipeline run, in execution order.
+type PipelineTrace struct {
+	Pipeline   string              `json:"pipeline"`
+	Steps      []PipelineStepTrace `json:"steps"`
+	TotalScore float64             `json:"total_score"`
+}
+
+// FormatPipelineTrace formats a pipeline run step by step for display.
+func FormatPipelineTrace(t *PipelineTrace) string {
+	output := fmt.Sprintf("🧮 파이프라인 %s: %.1f점\n", t.Pipeline, t.TotalScore)
+	for i, step := range t.Steps {
+		switch {
+		case !step.Fired:
+			output += fmt.Sprintf("  %d. %s: 조건 불충족으로 건너뜀 (누계 %.1f점)\n", i+1, step.Name, step.Total)
+		case step.Conditional:
+			output += fmt.Sprintf("  %d. %s: 조건 충족, %+.1f점 → 누계 %.1f점\n", i+1, step.Name, step.Contribution, step.Total)
+		default:
+			output += fmt.Sprintf("  %d. %s: %+.1f점 → 누계 %.1f점\n", i+1, step.Name, step.Contribution, step.Total)
+		}
+	}
+	return output
+}
@@ -0,0 +1,218 @@
+package scoring
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"apart_score/pkg/expr"
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+)
+
+func TestFamilyPipelineSpec(t *testing.T) {
+	pipeline, err := FamilyPipelineSpec().Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pipeline.Steps) != 4 || pipeline.Steps[2].Priority != 3 {
+		t.Fatalf("steps: %+v", pipeline.Steps)
+	}
+	scores := map[metadata.MetadataType]shared.ScoreValue{
+		metadata.SchoolDistrict:       shared.ScoreValueFromFloat(90),
+		metadata.ApartmentSize:        shared.ScoreValueFromFloat(80),
+		metadata.MaintenanceFee:       shared.ScoreValueFromFloat(70),
+		metadata.TransportationAccess: shared.ScoreValueFromFloat(80),
+	}
+	// 학군 90*0.4 + (80*0.6 + 70*0.4)*0.4 = 66.4, 60점을 넘으므로 교통 점수에 따라 1점 또는 0.4점 가산
+	for name, tt := range map[string]struct {
+		transport float64
+		want      float64
+	}{"good transport": {90, 67.4}, "fair transport": {80, 66.8}, "poor transport": {70, 66.4}} {
+		scores[metadata.TransportationAccess] = shared.ScoreValueFromFloat(tt.transport)
+		result, err := CalculateWithPipeline(scores, GetScenarioWeights(ScenarioBalanced), pipeline)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(result.TotalScore-tt.want) > 1e-9 {
+			t.Errorf("%s: total %.3f, want %.1f", name, result.TotalScore, tt.want)
+		}
+	}
+}
+
+func TestPipelineSpecSteps(t *testing.T) {
+	station, crime := metadata.DistanceToStation, metadata.CrimeRate
+	spec := PipelineSpec{Name: "test", Steps: []PipelineStepSpec{
+		{Name: "block", Kind: StepWeightedBlock, Weight: 0.5,
+			Factors: []FactorWeight{{Factor: metadata.SchoolDistrict, Weight: 3}, {Factor: metadata.Parking, Weight: 1}}},
+		{Name: "strategy", Kind: StepStrategy, Strategy: StrategyWeightedSum, Weight: 0.5},
+		{Name: "station", Kind: StepBonusIf, Points: 15, When: &PipelineCondition{Factor: &station, Op: OpGreaterEqual, Value: 90}},
+		{Name: "crime", Kind: StepPenaltyIf, Points: 5, When: &PipelineCondition{Any: []PipelineCondition{
+			{Factor: &crime, Op: OpLess, Value: 50}, {Op: OpGreater, Value: 95}}}},
+		{Name: "cap", Kind: StepCap, Max: 90},
+		{Name: "clamp", Kind: StepClamp, Min: 60, Max: 100},
+	}}
+	pipeline, err := spec.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	weights := GetScenarioWeights(ScenarioBalanced)
+
+	scores := uniformScores(80)
+	scores[metadata.Parking] = shared.MissingScore
+	result, err := CalculateWithPipeline(scores, weights, pipeline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 주차장 결측은 블록에서 제외: 80*0.5 + 80*0.5 = 80, 역 조건(80 < 90)과 감점 조건 모두 불충족
+	if math.Abs(result.TotalScore-80) > 1e-9 || result.RawScores[metadata.Parking] != shared.MissingScore {
+		t.Errorf("plain: total %.3f", result.TotalScore)
+	}
+
+	scores[metadata.DistanceToStation] = shared.ScoreValueFromFloat(95)
+	if result, err = CalculateWithPipeline(scores, weights, pipeline); err != nil || result.TotalScore != 90 {
+		t.Errorf("bonus is capped: %.3f, %v", result.TotalScore, err)
+	}
+	scores = uniformScores(40)
+	if result, err = CalculateWithPipeline(scores, weights, pipeline); err != nil || result.TotalScore != 60 {
+		t.Errorf("penalty is clamped: %.3f, %v", result.TotalScore, err)
+	}
+
+	// 결측 요소에 대한 비교는 항상 거짓
+	result = ScoreResult{RawScores: shared.ScoreArray{}}
+	result.RawScores[metadata.CrimeRate] = shared.MissingScore
+	for _, op := range ConditionOperators {
+		if (PipelineCondition{Factor: &crime, Op: op, Value: 50}).Holds(result) {
+			t.Errorf("%s holds for missing data", op)
+		}
+	}
+}
+
+func TestPipelineSpecValidate(t *testing.T) {
+	school := metadata.SchoolDistrict
+	unknown := metadata.MetadataType(999)
+	block := []FactorWeight{{Factor: school, Weight: 1}}
+	tests := map[string]PipelineStepSpec{
+		"unknown kind":        {Kind: "bogus"},
+		"empty block":         {Kind: StepWeightedBlock, Weight: 0.5},
+		"zero block weight":   {Kind: StepWeightedBlock, Factors: block},
+		"duplicate factor":    {Kind: StepWeightedBlock, Weight: 1, Factors: append(block, block...)},
+		"unconditional bonus": {Kind: StepBonusIf, Points: 5},
+		"negative penalty":    {Kind: StepPenaltyIf, Points: -5, When: &PipelineCondition{Op: OpLess, Value: 50}},
+		"inverted clamp":      {Kind: StepClamp, Min: 80, Max: 20},
+		"unknown strategy":    {Kind: StepStrategy, Strategy: "bogus", Weight: 1},
+		"unknown operator":    {Kind: StepCap, Max: 90, When: &PipelineCondition{Op: "=>", Value: 50}},
+		"unknown factor":      {Kind: StepCap, Max: 90, When: &PipelineCondition{Factor: &unknown, Op: OpLess, Value: 50}},
+		"mixed condition":     {Kind: StepCap, Max: 90, When: &PipelineCondition{Op: OpLess, Value: 50, All: []PipelineCondition{{Op: OpLess, Value: 1}}}},
+		"missing formula":     {Kind: StepFormula},
+		"condition formula":   {Kind: StepFormula, Formula: mustParse(t, "학군 > 50")},
+		"number condition":    {Kind: StepCap, Max: 90, When: &PipelineCondition{Expr: mustParse(t, "학군 + 1")}},
+	}
+	for name, step := range tests {
+		step.Name = name
+		var validation *ValidationError
+		_, err := PipelineSpec{Steps: []PipelineStepSpec{step}}.Compile()
+		if !errors.As(err, &validation) || !strings.Contains(validation.Message, name) {
+			t.Errorf("%s: %v", name, err)
+		}
+	}
+	if err := (PipelineSpec{}).Validate(); err == nil {
+		t.Error("empty pipeline is valid")
+	}
+}
+
+func TestCalculateWithPipelineTrace(t *testing.T) {
+	pipeline, err := FamilyPipelineSpec().Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	scores := map[metadata.MetadataType]shared.ScoreValue{
+		metadata.SchoolDistrict:       shared.ScoreValueFromFloat(90),
+		metadata.ApartmentSize:        shared.ScoreValueFromFloat(80),
+		metadata.MaintenanceFee:       shared.ScoreValueFromFloat(70),
+		metadata.TransportationAccess: shared.ScoreValueFromFloat(90),
+	}
+	result, trace, err := CalculateWithPipelineTrace(scores, GetScenarioWeights(ScenarioBalanced), pipeline)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if trace.Pipeline != pipeline.Name || trace.TotalScore != result.TotalScore || len(trace.Steps) != 4 {
+		t.Fatalf("trace: %+v", trace)
+	}
+	// 학군 36 + 크기/가격 30.4 = 66.4, 교통 보너스만 실행
+	want := []struct {
+		fired        bool
+		contribution float64
+		total        float64
+	}{{true, 36, 36}, {true, 30.4, 66.4}, {true, 1, 67.4}, {false, 0, 67.4}}
+	for i, step := range trace.Steps {
+		if step.Name != pipeline.Steps[i].Name || step.Conditional != (i >= 2) || step.Fired != want[i].fired ||
+			math.Abs(step.Contribution-want[i].contribution) > 1e-9 || math.Abs(step.Total-want[i].total) > 1e-9 {
+			t.Errorf("step %d: %+v", i+1, step)
+		}
+	}
+	output := FormatPipelineTrace(trace)
+	if !strings.Contains(output, "조건 충족, +1.0점") || !strings.Contains(output, "건너뜀") {
+		t.Errorf("formatted trace:\n%s", output)
+	}
+
+	dashboard, err := GenerateTransparencyDashboardWithOptions(result, scores, GetScenarioWeights(ScenarioBalanced),
+		StrategyWeightedSum, DashboardOptions{Pipeline: &pipeline, Cohort: []ApartmentData{{ID: "a", Scores: scores}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dashboard.PipelineTrace == nil || dashboard.PipelineTrace.TotalScore != result.TotalScore {
+		t.Errorf("dashboard trace: %+v", dashboard.PipelineTrace)
+	}
+	if !strings.Contains(FormatTransparencyDashboard(dashboard), "🧮 파이프라인") {
+		t.Error("dashboard does not render the trace")
+	}
+}
+
+func TestPipelineExpressions(t *testing.T) {
+	spec := PipelineSpec{Name: "expr", Steps: []PipelineStepSpec{
+		{Name: "base", Kind: StepFormula, Formula: mustParse(t, "0.6*ApartmentSize + 0.4*관리비")},
+		{Name: "school", Kind: StepBonusIf, Points: 5, When: &PipelineCondition{Expr: mustParse(t, "학군 >= 85 and total > 70")}},
+		{Name: "parking", Kind: StepFormula, Formula: mustParse(t, "Parking / 10")},
+	}}
+	pipeline, err := spec.Compile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	scores := map[metadata.MetadataType]shared.ScoreValue{
+		metadata.ApartmentSize:  shared.ScoreValueFromFloat(80),
+		metadata.MaintenanceFee: shared.ScoreValueFromFloat(70),
+		metadata.SchoolDistrict: shared.ScoreValueFromFloat(90),
+		metadata.Parking:        shared.MissingScore,
+	}
+	// 80*0.6 + 70*0.4 = 76, 학군 보너스 5점, 결측 주차장 식은 0점
+	result, err := CalculateWithPipeline(scores, GetScenarioWeights(ScenarioBalanced), pipeline)
+	if err != nil || math.Abs(result.TotalScore-81) > 1e-9 {
+		t.Errorf("total %.3f, %v", result.TotalScore, err)
+	}
+
+	scores[metadata.SchoolDistrict] = shared.ScoreValueFromFloat(80)
+	scores[metadata.Parking] = shared.ScoreValueFromFloat(50)
+	if result, err = CalculateWithPipeline(scores, GetScenarioWeights(ScenarioBalanced), pipeline); err != nil || math.Abs(result.TotalScore-81) > 1e-9 {
+		t.Errorf("without bonus: %.3f, %v", result.TotalScore, err)
+	}
+
+	spec.Steps = append(spec.Steps, PipelineStepSpec{Name: "ratio", Kind: StepFormula, Formula: mustParse(t, "1 / (학군 - 80)")})
+	if pipeline, err = spec.Compile(); err != nil {
+		t.Fatal(err)
+	}
+	var exprErr *expr.Error
+	if _, err := CalculateWithPipeline(scores, GetScenarioWeights(ScenarioBalanced), pipeline); !errors.As(err, &exprErr) {
+		t.Errorf("division by zero: %v", err)
+	}
+}
+
+func mustParse(t *testing.T, source string) *expr.Expr {
+	t.Helper()
+	e, err := expr.Parse(source)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return e
+}
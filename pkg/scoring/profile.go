@@ -0,0 +1,87 @@
+package scoring
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"fmt"
+)
+
+const (
+	errUnknownScenario = "알 수 없는 시나리오: %s"
+	errInvalidProfile  = "프로필 %s 검증 실패: %w"
+)
+
+// Strategy returns the profile's calculation strategy, defaulting to StrategyWeightedSum.
+func (p ScoringProfile) Strategy() StrategyType {
+	if p.Method == "" {
+		return StrategyWeightedSum
+	}
+	return p.Method
+}
+
+// BaseScenario returns the profile's scenario, defaulting to ScenarioBalanced.
+func (p ScoringProfile) BaseScenario() ScoringScenario {
+	if p.Scenario == "" {
+		return ScenarioBalanced
+	}
+	return p.Scenario
+}
+
+// EffectiveWeights returns the weights the profile scores with.
+func (p ScoringProfile) EffectiveWeights() map[metadata.MetadataType]shared.Weight {
+	if len(p.Weights) > 0 {
+		return p.Weights
+	}
+	return GetScenarioWeights(p.BaseScenario())
+}
+
+// Validate checks the strategy, scenario, weights, constraints and value functions of the profile.
+func (p ScoringProfile) Validate() error {
+	if _, exists := LookupStrategy(p.Strategy()); !exists {
+		return &ValidationError{Field: "strategy", Message: fmt.Sprintf(errUnsupportedStrategy, p.Method)}
+	}
+	if _, exists := ScenarioDefinitions[p.BaseScenario()]; !exists {
+		return &ValidationError{Field: "scenario", Message: fmt.Sprintf(errUnknownScenario, p.Scenario)}
+	}
+	if len(p.Weights) > 0 {
+		for mt := range p.Weights {
+			if !mt.IsValid() {
+				return &ValidationError{Field: "weights", Message: fmt.Sprintf(errUnknownMetadata, int(mt))}
+			}
+		}
+		if err := (&DefaultScorer{}).validateWeights(p.Weights); err != nil {
+			return err
+		}
+	}
+	if err := p.Constraints.Validate(); err != nil {
+		return err
+	}
+	return p.ValueFunctions.Validate()
+}
+
+// CalculateWithProfile scores an apartment with the profile's value functions, weights and
+// strategy; the result's RawScores hold the utilities that were aggregated. Constraints do not
+// apply to a single score; use CalculateRankingsWithProfile to filter.
+func CalculateWithProfile(scores map[metadata.MetadataType]shared.ScoreValue, p ScoringProfile) (ScoreResult, error) {
+	if err := p.Validate(); err != nil {
+		return ScoreResult{}, fmt.Errorf(errInvalidProfile, p.Name, err)
+	}
+	return CalculateWithStrategy(p.ValueFunctions.Apply(scores), p.EffectiveWeights(), p.Strategy())
+}
+
+// CalculateRankingsWithProfile ranks apartments with the profile's weights, strategy,
+// constraints and value functions. Constraints and value functions set in opts take
+// precedence over the profile's.
+func CalculateRankingsWithProfile(apartments []ApartmentData, p ScoringProfile, opts RankingOptions) (*RankingsSummary, error) {
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf(errInvalidProfile, p.Name, err)
+	}
+	if opts.Constraints == nil && !p.Constraints.IsEmpty() {
+		constraints := p.Constraints
+		opts.Constraints = &constraints
+	}
+	if opts.ValueFunctions == nil {
+		opts.ValueFunctions = p.ValueFunctions
+	}
+	return CalculateRankingsWithOptions(apartments, p.EffectiveWeights(), p.Strategy(), opts)
+}
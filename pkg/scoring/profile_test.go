@@ -0,0 +1,62 @@
+package scoring
+
+import (
+	"errors"
+	"testing"
+
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+)
+
+func TestScoringProfileDefaults(t *testing.T) {
+	var p ScoringProfile
+	if p.Strategy() != StrategyWeightedSum || p.BaseScenario() != ScenarioBalanced {
+		t.Errorf("defaults = %s, %s", p.Strategy(), p.BaseScenario())
+	}
+	p.Scenario = ScenarioTransportation
+	if got := p.EffectiveWeights(); got[metadata.DistanceToStation] != GetScenarioWeights(ScenarioTransportation)[metadata.DistanceToStation] {
+		t.Errorf("EffectiveWeights did not use the scenario: %v", got)
+	}
+	if err := p.Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+
+	var validation *ValidationError
+	p.Scenario = "luxury"
+	if err := p.Validate(); !errors.As(err, &validation) || validation.Field != "scenario" {
+		t.Errorf("unknown scenario: %v", err)
+	}
+}
+
+func TestCalculateRankingsWithProfile(t *testing.T) {
+	noElevator := uniformScores(95)
+	noElevator[metadata.ElevatorPresence] = 0
+	apartments := []ApartmentData{
+		{ID: "a", Scores: uniformScores(70)},
+		{ID: "b", Scores: noElevator},
+	}
+	p := ScoringProfile{
+		Name:        "elevator",
+		Method:      StrategyMinMax,
+		Constraints: Constraints{RequiredFeatures: []metadata.MetadataType{metadata.ElevatorPresence}},
+	}
+	summary, err := CalculateRankingsWithProfile(apartments, p, RankingOptions{})
+	if err != nil {
+		t.Fatalf("ranking failed: %v", err)
+	}
+	if summary.Strategy != StrategyMinMax || len(summary.TopRanked) != 1 || len(summary.Eliminated) != 1 {
+		t.Fatalf("unexpected summary: strategy %s, %d ranked, %d eliminated",
+			summary.Strategy, len(summary.TopRanked), len(summary.Eliminated))
+	}
+
+	// 옵션의 제약 조건이 프로필보다 우선
+	summary, err = CalculateRankingsWithProfile(apartments, p, RankingOptions{Constraints: &Constraints{}})
+	if err != nil || len(summary.TopRanked) != 2 {
+		t.Errorf("override constraints: %v, %v", summary, err)
+	}
+
+	p.Weights = map[metadata.MetadataType]shared.Weight{metadata.FloorLevel: 500}
+	if _, err := CalculateWithProfile(uniformScores(70), p); err == nil {
+		t.Error("expected an error for weights summing to 500")
+	}
+}
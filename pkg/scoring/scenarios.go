@@ -113,6 +113,12 @@ func GetScenarioWeights(scenario ScoringScenario) map[metadata.MetadataType]shar
 			metadata.HeatingSystem:        shared.WeightFromFloat(0.03),
 		}
 	}
+	// 사용자 정의 메타데이터의 기본 가중치를 추가한 뒤 함께 정규화
+	for _, mt := range metadata.All() {
+		if !mt.IsBuiltin() && mt.DefaultWeight() > 0 {
+			weights[mt] = shared.WeightFromFloat(mt.DefaultWeight())
+		}
+	}
 	return shared.NormalizeWeights(weights)
 }
 
@@ -102,4 +102,14 @@ func TestGetScenarioWeights(t *testing.T) {
 	if stationWeight.ToFloat() < 0.2 {
 		t.Errorf("Transportation scenario should have high station weight, got %v", stationWeight.ToFloat())
 	}
+
+	for _, scenario := range GetAllScenarios() {
+		total := shared.Weight(0)
+		for _, w := range GetScenarioWeights(scenario) {
+			total += w
+		}
+		if total != shared.WeightScale {
+			t.Errorf("%s weights sum to %d, want %d", scenario, total, shared.WeightScale)
+		}
+	}
 }
@@ -0,0 +1,342 @@
+package scoring
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+const (
+	// SensitivityPerturbation is the weight change (5%p) applied to each factor in sensitivity analysis.
+	SensitivityPerturbation shared.Weight = 50
+	// rankReversalStep is the scan step (1%p) used before bisecting the exact reversal threshold.
+	rankReversalStep shared.Weight = 10
+	// maxSensitiveFactors is the number of factors reported in MostSensitiveFactors.
+	maxSensitiveFactors = 5
+)
+
+// RankReversal describes the smallest single-factor weight change that swaps two adjacent apartments.
+type RankReversal struct {
+	UpperID       string                `json:"upper_id"`   // 현재 상위 아파트
+	LowerID       string                `json:"lower_id"`   // 현재 하위 아파트
+	UpperRank     int                   `json:"upper_rank"` // 상위 아파트의 현재 순위
+	ScoreGap      float64               `json:"score_gap"`  // 현재 점수 차이
+	Reversible    bool                  `json:"reversible"` // false면 단일 요소 가중치 변경으로는 순위가 바뀌지 않음
+	Factor        metadata.MetadataType `json:"factor"`
+	CurrentWeight shared.Weight         `json:"current_weight"`
+	NewWeight     shared.Weight         `json:"new_weight"`
+	Change        shared.Weight         `json:"change"` // 부호 있는 가중치 변화량
+}
+
+// perturbWeight sets the weight of one factor to target and rescales the other weights
+// proportionally so the total stays WeightScale. It returns false when that is impossible.
+func perturbWeight(weights shared.WeightArray, factor metadata.MetadataType, target shared.Weight) (shared.WeightArray, bool) {
+	if target < 0 || target > shared.WeightScale {
+		return weights, false
+	}
+	others := weights
+	others[factor] = 0
+	perturbed, ok := scaleWeights(others, int(shared.WeightScale-target))
+	if !ok {
+		return weights, false
+	}
+	perturbed[factor] = target
+	return perturbed, true
+}
+
+// performSensitivityAnalysis perturbs each weight by ±SensitivityPerturbation, renormalizing the
+// others, and measures how much the score moves per unit of weight.
+func performSensitivityAnalysis(scores map[metadata.MetadataType]shared.ScoreValue,
+	weights map[metadata.MetadataType]shared.Weight, strategy StrategyType) SensitivityAnalysis {
+	scoreArray, weightArray, err := toArrays(scores, weights)
+	if err != nil {
+		return SensitivityAnalysis{}
+	}
+	base, err := CalculateWithStrategyArray(scoreArray, weightArray, strategy)
+	if err != nil {
+		return SensitivityAnalysis{}
+	}
+
+	sensitiveFactors := []SensitivityFactor{}
+	variations := []float64{base.TotalScore}
+	maxDelta := 0.0
+	for _, mt := range metadata.All() {
+		if scoreArray[mt].IsMissing() {
+			continue
+		}
+		current := weightArray[mt]
+		upper, lower := current+SensitivityPerturbation, current-SensitivityPerturbation
+		if upper > shared.WeightScale {
+			upper = shared.WeightScale
+		}
+		if lower < 0 {
+			lower = 0
+		}
+
+		// 가능한 경우 중심 차분으로 가중치 단위당 점수 변화 계산
+		high, highOK := scoreWithWeight(scoreArray, weightArray, mt, upper, strategy)
+		low, lowOK := scoreWithWeight(scoreArray, weightArray, mt, lower, strategy)
+		if !highOK {
+			high, upper = base.TotalScore, current
+		}
+		if !lowOK {
+			low, lower = base.TotalScore, current
+		}
+		if upper == lower {
+			continue
+		}
+		for _, v := range []float64{high, low} {
+			variations = append(variations, v)
+			maxDelta = math.Max(maxDelta, math.Abs(v-base.TotalScore))
+		}
+
+		sensitivity := (high - low) / (upper - lower).ToFloat()
+		direction := "Positive"
+		if sensitivity < 0 {
+			direction = "Negative" // 가중치를 높이면 점수가 낮아짐
+		}
+		sensitiveFactors = append(sensitiveFactors, SensitivityFactor{
+			FactorName:      mt.String(),
+			CurrentValue:    scoreArray[mt].ToFloat(),
+			CurrentWeight:   current.ToFloat(),
+			Sensitivity:     sensitivity,
+			ImpactDirection: direction,
+		})
+	}
+	sort.SliceStable(sensitiveFactors, func(i, j int) bool {
+		return math.Abs(sensitiveFactors[i].Sensitivity) > math.Abs(sensitiveFactors[j].Sensitivity)
+	})
+	if len(sensitiveFactors) > maxSensitiveFactors {
+		sensitiveFactors = sensitiveFactors[:maxSensitiveFactors]
+	}
+
+	// 안정성 지수: 최대 변동폭이 현재 점수에서 차지하는 비율의 보수
+	stabilityIndex := 100.0
+	if base.TotalScore > 0 {
+		stabilityIndex = math.Max(0, 100*(1-maxDelta/base.TotalScore))
+	}
+	sorted := sortedCopy(variations)
+	variationRange := ScoreRange{
+		Minimum: sorted[0],
+		Maximum: sorted[len(sorted)-1],
+		Average: mean(sorted),
+		StdDev:  stdDev(sorted),
+	}
+
+	robustnessLevel := "High"
+	if maxDelta >= 3 {
+		robustnessLevel = "Low"
+	} else if maxDelta >= 1 {
+		robustnessLevel = "Medium"
+	}
+
+	return SensitivityAnalysis{
+		MostSensitiveFactors: sensitiveFactors,
+		StabilityIndex:       stabilityIndex,
+		VariationRange:       variationRange,
+		RobustnessLevel:      robustnessLevel,
+	}
+}
+
+func scoreWithWeight(scores shared.ScoreArray, weights shared.WeightArray, factor metadata.MetadataType,
+	target shared.Weight, strategy StrategyType) (float64, bool) {
+	perturbed, ok := perturbWeight(weights, factor, target)
+	if !ok {
+		return 0, false
+	}
+	result, err := CalculateWithStrategyArray(scores, perturbed, strategy)
+	if err != nil {
+		return 0, false
+	}
+	return result.TotalScore, true
+}
+
+// AnalyzeRankReversals finds, for every pair of adjacent apartments in the summary, the smallest
+// change to a single factor's weight (others renormalized) that would put the lower apartment ahead.
+// opts are the options the summary was ranked with: the ranked apartments are rescored with its value
+// functions and missing value policy, while constraints and the Pareto filter do not depend on weights
+// and are not reapplied. Cohort strategies and median imputation rescore the whole set for every
+// candidate weight.
+func AnalyzeRankReversals(summary *RankingsSummary, weights map[metadata.MetadataType]shared.Weight, opts RankingOptions) ([]RankReversal, error) {
+	if summary == nil || len(summary.TopRanked) == 0 {
+		return nil, errors.New(errNoApartments)
+	}
+	var scoreArray shared.ScoreArray
+	_, weightArray, err := toArrays(nil, weights)
+	if err == nil {
+		err = validateStrategyInputsArray(scoreArray, weightArray)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("입력 검증 실패: %w", err)
+	}
+	impl, exists := LookupStrategy(summary.Strategy)
+	if !exists {
+		return nil, fmt.Errorf(errUnsupportedStrategy, summary.Strategy)
+	}
+	if err := opts.MissingPolicy.Validate(); err != nil {
+		return nil, err
+	}
+	if err := opts.ValueFunctions.Validate(); err != nil {
+		return nil, err
+	}
+	_, isCohort := impl.(CohortStrategy)
+	// 중앙값 대체는 순위 대상 전체의 중앙값을 쓰므로 쌍만 다시 계산할 수 없음
+	wholeSet := isCohort || opts.MissingPolicy == MissingImputeMedian
+
+	apartments := make([]ApartmentData, len(summary.TopRanked))
+	for i, ranking := range summary.TopRanked {
+		apartments[i] = ranking.Apartment
+	}
+	apartments = opts.ValueFunctions.applyAll(apartments)
+	pairs := len(apartments) - 1
+	if pairs <= 0 {
+		return []RankReversal{}, nil
+	}
+
+	// totals rescores apartments with one factor's weight set to target.
+	totals := func(subset []ApartmentData, factor metadata.MetadataType, target shared.Weight) ([]float64, bool) {
+		perturbed, ok := perturbWeight(weightArray, factor, target)
+		if !ok {
+			return nil, false
+		}
+		results, err := scoreCohort(subset, weightMap(perturbed), summary.Strategy, opts.MissingPolicy)
+		if err != nil {
+			return nil, false
+		}
+		scores := make([]float64, len(results))
+		for i, r := range results {
+			scores[i] = r.TotalScore
+		}
+		return scores, true
+	}
+	// swapped rescores one pair (the whole set when scores depend on it) and reports whether it reversed.
+	swapped := func(pair int, factor metadata.MetadataType, target shared.Weight) bool {
+		subset, upper, lower := apartments[pair:pair+2], 0, 1
+		if wholeSet {
+			subset, upper, lower = apartments, pair, pair+1
+		}
+		scores, ok := totals(subset, factor, target)
+		return ok && scores[lower] > scores[upper]
+	}
+
+	type candidate struct {
+		factor metadata.MetadataType
+		step   int
+		sign   shared.Weight
+	}
+	best := make([][]candidate, pairs)
+	bestStep := make([]int, pairs)
+	for _, mt := range metadata.All() {
+		for _, sign := range []shared.Weight{1, -1} {
+			firstSwap := make([]int, pairs)
+			remaining := pairs
+			span := weightSpan(weightArray[mt], sign)
+			for step := 1; remaining > 0 && shared.Weight(step-1)*rankReversalStep < span; step++ {
+				change := shared.Weight(step) * rankReversalStep
+				if change > span {
+					change = span
+				}
+				scores, ok := totals(apartments, mt, weightArray[mt]+sign*change)
+				if !ok {
+					break
+				}
+				for p := 0; p < pairs; p++ {
+					if firstSwap[p] == 0 && scores[p+1] > scores[p] {
+						firstSwap[p] = step
+						remaining--
+					}
+				}
+			}
+			for p, step := range firstSwap {
+				if step == 0 {
+					continue
+				}
+				if bestStep[p] == 0 || step < bestStep[p] {
+					bestStep[p] = step
+					best[p] = best[p][:0]
+				}
+				if step == bestStep[p] {
+					best[p] = append(best[p], candidate{factor: mt, step: step, sign: sign})
+				}
+			}
+		}
+	}
+
+	reversals := make([]RankReversal, pairs)
+	for p := 0; p < pairs; p++ {
+		reversal := RankReversal{
+			UpperID:   apartments[p].ID,
+			LowerID:   apartments[p+1].ID,
+			UpperRank: summary.TopRanked[p].Rank,
+			ScoreGap:  summary.TopRanked[p].Score - summary.TopRanked[p+1].Score,
+		}
+		for _, c := range best[p] {
+			current := weightArray[c.factor]
+			// 스캔 구간 내에서 이분 탐색으로 최소 변화량 결정
+			lo := shared.Weight(c.step-1) * rankReversalStep
+			hi := shared.Weight(c.step) * rankReversalStep
+			if span := weightSpan(current, c.sign); hi > span {
+				hi = span
+			}
+			for hi-lo > 1 {
+				mid := (lo + hi) / 2
+				if swapped(p, c.factor, current+c.sign*mid) {
+					hi = mid
+				} else {
+					lo = mid
+				}
+			}
+			if !reversal.Reversible || hi < absWeight(reversal.Change) {
+				reversal.Reversible = true
+				reversal.Factor = c.factor
+				reversal.CurrentWeight = current
+				reversal.NewWeight = current + c.sign*hi
+				reversal.Change = c.sign * hi
+			}
+		}
+		reversals[p] = reversal
+	}
+	return reversals, nil
+}
+
+// FormatRankReversals formats rank reversal thresholds as a readable string.
+func FormatRankReversals(reversals []RankReversal) string {
+	output := "🔀 순위 역전 분석 (단일 요소 가중치 변경 기준)\n"
+	output += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
+	for _, r := range reversals {
+		if !r.Reversible {
+			output += fmt.Sprintf("  %d위 %s ↔ %s: 단일 요소 가중치 변경으로는 역전되지 않음\n", r.UpperRank, r.UpperID, r.LowerID)
+			continue
+		}
+		output += fmt.Sprintf("  %d위 %s ↔ %s: %s 가중치 %.1f%% → %.1f%% (%+.1f%%p) 시 역전 (현재 차이 %.1f점)\n",
+			r.UpperRank, r.UpperID, r.LowerID, r.Factor.KoreanName(),
+			r.CurrentWeight.ToFloat()*100, r.NewWeight.ToFloat()*100, r.Change.ToFloat()*100, r.ScoreGap)
+	}
+	return output
+}
+
+func weightMap(weights shared.WeightArray) map[metadata.MetadataType]shared.Weight {
+	result := make(map[metadata.MetadataType]shared.Weight, metadata.Count())
+	for _, mt := range metadata.All() {
+		result[mt] = weights[mt]
+	}
+	return result
+}
+
+// weightSpan returns how far a weight can move in the direction of sign.
+func weightSpan(current, sign shared.Weight) shared.Weight {
+	if sign < 0 {
+		return current
+	}
+	return shared.WeightScale - current
+}
+
+func absWeight(w shared.Weight) shared.Weight {
+	if w < 0 {
+		return -w
+	}
+	return w
+}
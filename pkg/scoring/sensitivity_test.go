@@ -0,0 +1,136 @@
+package scoring
+
+import (
+	"strings"
+	"testing"
+
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/normalization"
+	"apart_score/pkg/shared"
+)
+
+func TestPerturbWeight(t *testing.T) {
+	_, weights, _ := toArrays(nil, getTestWeights())
+	perturbed, ok := perturbWeight(weights, metadata.SchoolDistrict, 300)
+	if !ok {
+		t.Fatal("Expected perturbation to succeed")
+	}
+	total := shared.Weight(0)
+	for _, mt := range metadata.All() {
+		total += perturbed[mt]
+	}
+	if perturbed[metadata.SchoolDistrict] != 300 || total != shared.WeightScale {
+		t.Errorf("Expected SchoolDistrict 300 and total %d, got %d and %d", shared.WeightScale, perturbed[metadata.SchoolDistrict], total)
+	}
+	if perturbed[metadata.DistanceToStation] >= weights[metadata.DistanceToStation] {
+		t.Error("Expected other weights to shrink proportionally")
+	}
+	if _, ok := perturbWeight(weights, metadata.SchoolDistrict, shared.WeightScale+1); ok {
+		t.Error("Expected out-of-range target to fail")
+	}
+}
+
+func TestPerformSensitivityAnalysis(t *testing.T) {
+	scores := uniformScores(70)
+	scores[metadata.SchoolDistrict] = shared.ScoreValueFromFloat(100)
+	scores[metadata.CrimeRate] = shared.ScoreValueFromFloat(20)
+	weights := getTestWeights()
+
+	analysis := performSensitivityAnalysis(scores, weights, StrategyWeightedSum)
+	if len(analysis.MostSensitiveFactors) == 0 {
+		t.Fatal("Expected sensitive factors")
+	}
+	top := analysis.MostSensitiveFactors[0]
+	if top.FactorName != metadata.CrimeRate.String() || top.ImpactDirection != "Negative" {
+		t.Errorf("Expected CrimeRate with negative impact first, got %s (%s)", top.FactorName, top.ImpactDirection)
+	}
+	for _, factor := range analysis.MostSensitiveFactors {
+		if factor.FactorName == metadata.SchoolDistrict.String() && factor.Sensitivity <= 0 {
+			t.Errorf("Expected positive sensitivity for the best factor, got %.2f", factor.Sensitivity)
+		}
+	}
+	if analysis.StabilityIndex <= 0 || analysis.StabilityIndex >= 100 {
+		t.Errorf("Expected stability index between 0 and 100, got %.2f", analysis.StabilityIndex)
+	}
+	if analysis.VariationRange.Minimum >= analysis.VariationRange.Maximum {
+		t.Errorf("Expected a non-empty variation range, got %+v", analysis.VariationRange)
+	}
+
+	flat := performSensitivityAnalysis(uniformScores(70), weights, StrategyWeightedSum)
+	if flat.StabilityIndex < 99 || flat.RobustnessLevel != "High" {
+		t.Errorf("Expected uniform scores to be stable, got %.2f (%s)", flat.StabilityIndex, flat.RobustnessLevel)
+	}
+}
+
+func TestAnalyzeRankReversals(t *testing.T) {
+	school := uniformScores(70)
+	school[metadata.SchoolDistrict] = shared.ScoreValueFromFloat(100)
+	station := uniformScores(70)
+	station[metadata.DistanceToStation] = shared.ScoreValueFromFloat(100)
+	unknownParking := uniformScores(60)
+	unknownParking[metadata.Parking] = shared.MissingScore
+	weights := getTestWeights()
+
+	apartments := []ApartmentData{
+		{ID: "school", Name: "학군", Scores: school},
+		{ID: "station", Name: "역세권", Scores: station},
+	}
+	stationCurve := ValueFunctions{metadata.DistanceToStation: {Kind: ValuePiecewiseLinear,
+		Points: normalization.Curve{{Input: 70, Score: 0}, {Input: 100, Score: 100}}}}
+	tests := []struct {
+		name       string
+		strategy   StrategyType
+		opts       RankingOptions
+		apartments []ApartmentData
+	}{
+		{"weighted sum", StrategyWeightedSum, RankingOptions{}, apartments},
+		{"topsis", StrategyTOPSIS, RankingOptions{}, apartments},
+		{"value functions", StrategyWeightedSum, RankingOptions{ValueFunctions: stationCurve}, apartments},
+		{"median imputation", StrategyWeightedSum, RankingOptions{MissingPolicy: MissingImputeMedian},
+			append(apartments, ApartmentData{ID: "unknown", Name: "주차 미상", Scores: unknownParking})},
+	}
+	changes := make(map[string]shared.Weight)
+	for _, tt := range tests {
+		summary, err := CalculateRankingsWithOptions(tt.apartments, weights, tt.strategy, tt.opts)
+		if err != nil {
+			t.Fatalf("%s: ranking failed: %v", tt.name, err)
+		}
+		reversals, err := AnalyzeRankReversals(summary, weights, tt.opts)
+		if err != nil {
+			t.Fatalf("%s: AnalyzeRankReversals failed: %v", tt.name, err)
+		}
+		if len(reversals) != len(tt.apartments)-1 || !reversals[0].Reversible {
+			t.Fatalf("%s: expected a reversible top pair, got %+v", tt.name, reversals)
+		}
+		r := reversals[0]
+		if !strings.Contains(FormatRankReversals(reversals), r.Factor.KoreanName()) {
+			t.Errorf("%s: expected formatted reversal to name the factor", tt.name)
+		}
+		if r.UpperID != "station" || r.LowerID != "school" {
+			t.Errorf("%s: expected station above school, got %s above %s", tt.name, r.UpperID, r.LowerID)
+		}
+		changes[tt.name] = r.Change
+
+		// 같은 옵션으로 다시 순위를 매겼을 때, 찾은 변경은 역전시키고 1 단위 덜 바꾸면 역전되지 않아야 함
+		_, weightArray, _ := toArrays(nil, weights)
+		for _, change := range []shared.Weight{r.Change, r.Change - sign(r.Change)} {
+			perturbed, _ := perturbWeight(weightArray, r.Factor, r.CurrentWeight+change)
+			after, _ := CalculateRankingsWithOptions(tt.apartments, weightMap(perturbed), tt.strategy, tt.opts)
+			// 동점은 역전이 아님
+			flipped := after.TopRanked[0].Apartment.ID == "school" && after.TopRanked[0].Score > after.TopRanked[1].Score
+			if flipped != (change == r.Change) {
+				t.Errorf("%s: change %d on %s flipped=%v", tt.name, change, r.Factor, flipped)
+			}
+		}
+	}
+	if changes["value functions"] == changes["weighted sum"] {
+		t.Errorf("value functions do not change the reversal threshold (%d)", changes["weighted sum"])
+	}
+}
+
+func sign(w shared.Weight) shared.Weight {
+	if w < 0 {
+		return -1
+	}
+	return 1
+}
@@ -0,0 +1,205 @@
+package scoring
+
+import (
+	"errors"
+	"math"
+)
+
+const (
+	// lpTolerance is the numerical tolerance of the simplex solver.
+	lpTolerance = 1e-9
+	// maxSimplexIterations bounds the pivots of one simplex phase.
+	maxSimplexIterations = 100000
+)
+
+var (
+	errInfeasibleLP     = errors.New("선형 계획 문제에 가능한 해가 없습니다")
+	errUnboundedLP      = errors.New("선형 계획 문제의 목적 함수가 한없이 작아집니다")
+	errSimplexIteration = errors.New("단체법 반복 횟수를 초과했습니다")
+)
+
+// constraintSense is the relation of a linear constraint.
+type constraintSense int
+
+const (
+	lessEqual constraintSense = iota
+	greaterEqual
+	equalTo
+)
+
+// linearConstraint is coef·x (sense) rhs.
+type linearConstraint struct {
+	coef  []float64
+	sense constraintSense
+	rhs   float64
+}
+
+// simplexTableau is a dense simplex tableau; the last column of each row is its right-hand side.
+type simplexTableau struct {
+	rows            [][]float64
+	basis           []int
+	firstArtificial int
+}
+
+// solveLP minimizes cost·x subject to the constraints and x >= 0 with the two-phase simplex
+// method. Bland's rule prevents cycling; the solver is meant for the small dense problems of
+// this package.
+func solveLP(cost []float64, constraints []linearConstraint) ([]float64, error) {
+	n := len(cost)
+	rows := make([]linearConstraint, len(constraints))
+	slacks, artificials := 0, 0
+	for i, c := range constraints {
+		row := linearConstraint{coef: make([]float64, n), sense: c.sense, rhs: c.rhs}
+		copy(row.coef, c.coef)
+		if row.rhs < 0 { // 우변이 음수면 양변에 -1을 곱함
+			for j := range row.coef {
+				row.coef[j] = -row.coef[j]
+			}
+			row.rhs = -row.rhs
+			switch row.sense {
+			case lessEqual:
+				row.sense = greaterEqual
+			case greaterEqual:
+				row.sense = lessEqual
+			}
+		}
+		if row.sense != equalTo {
+			slacks++
+		}
+		if row.sense != lessEqual {
+			artificials++
+		}
+		rows[i] = row
+	}
+
+	// 열 배치: 원래 변수, 여유/잉여 변수, 인공 변수, 우변
+	width := n + slacks + artificials
+	t := &simplexTableau{rows: make([][]float64, len(rows)), basis: make([]int, len(rows)), firstArtificial: n + slacks}
+	slack, artificial := n, n+slacks
+	for i, row := range rows {
+		t.rows[i] = make([]float64, width+1)
+		copy(t.rows[i], row.coef)
+		t.rows[i][width] = row.rhs
+		switch row.sense {
+		case lessEqual:
+			t.rows[i][slack] = 1
+			t.basis[i] = slack
+			slack++
+			continue
+		case greaterEqual:
+			t.rows[i][slack] = -1
+			slack++
+		}
+		t.rows[i][artificial] = 1
+		t.basis[i] = artificial
+		artificial++
+	}
+
+	// 1단계: 인공 변수의 합을 최소화해 가능한 기저 해를 찾음
+	phase1 := make([]float64, width)
+	for j := t.firstArtificial; j < width; j++ {
+		phase1[j] = 1
+	}
+	if err := t.optimize(phase1, width); err != nil {
+		return nil, err
+	}
+	infeasibility := 0.0
+	for i, b := range t.basis {
+		if b >= t.firstArtificial {
+			infeasibility += t.rows[i][width]
+		}
+	}
+	if infeasibility > 1e-7 {
+		return nil, errInfeasibleLP
+	}
+	t.dropArtificials()
+
+	// 2단계: 인공 변수를 제외하고 원래 목적 함수를 최소화
+	phase2 := make([]float64, width)
+	copy(phase2, cost)
+	if err := t.optimize(phase2, t.firstArtificial); err != nil {
+		return nil, err
+	}
+	x := make([]float64, n)
+	for i, b := range t.basis {
+		if b < n {
+			x[b] = t.rows[i][width]
+		}
+	}
+	return x, nil
+}
+
+// optimize pivots until no column below limit has a negative reduced cost.
+func (t *simplexTableau) optimize(cost []float64, limit int) error {
+	width := len(cost)
+	for iteration := 0; iteration < maxSimplexIterations; iteration++ {
+		// Bland 규칙: 축소 비용이 음수인 가장 앞의 열이 기저에 들어감
+		entering := -1
+		for j := 0; j < limit; j++ {
+			if t.reducedCost(cost, j) < -lpTolerance {
+				entering = j
+				break
+			}
+		}
+		if entering < 0 {
+			return nil
+		}
+		leaving, best := -1, math.Inf(1)
+		for i, row := range t.rows {
+			if row[entering] <= lpTolerance {
+				continue
+			}
+			ratio := row[width] / row[entering]
+			if ratio < best-lpTolerance || (ratio <= best+lpTolerance && t.basis[i] < t.basis[leaving]) {
+				leaving, best = i, ratio
+			}
+		}
+		if leaving < 0 {
+			return errUnboundedLP
+		}
+		t.pivot(leaving, entering)
+	}
+	return errSimplexIteration
+}
+
+func (t *simplexTableau) reducedCost(cost []float64, column int) float64 {
+	reduced := cost[column]
+	for i, b := range t.basis {
+		reduced -= cost[b] * t.rows[i][column]
+	}
+	return reduced
+}
+
+func (t *simplexTableau) pivot(row, column int) {
+	pivotRow := t.rows[row]
+	scale := pivotRow[column]
+	for j := range pivotRow {
+		pivotRow[j] /= scale
+	}
+	for i, other := range t.rows {
+		if i == row || other[column] == 0 {
+			continue
+		}
+		factor := other[column]
+		for j := range other {
+			other[j] -= factor * pivotRow[j]
+		}
+	}
+	t.basis[row] = column
+}
+
+// dropArtificials pivots artificial variables left in the basis at zero out of it. A row
+// without any other non-zero entry is redundant and keeps its artificial variable at zero.
+func (t *simplexTableau) dropArtificials() {
+	for i, b := range t.basis {
+		if b < t.firstArtificial {
+			continue
+		}
+		for j := 0; j < t.firstArtificial; j++ {
+			if math.Abs(t.rows[i][j]) > lpTolerance {
+				t.pivot(i, j)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package scoring
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+const (
+	// DefaultSimulationDraws is the number of Monte Carlo draws when SimulationOptions.Draws is 0.
+	DefaultSimulationDraws = 1000
+	// DefaultScoreStdDev is the per-factor standard deviation (points) assumed by the dashboard.
+	DefaultScoreStdDev = 5.0
+	// simulationHistogramBins is the number of equal-width bins over 0-100 in a score histogram.
+	simulationHistogramBins = 10
+)
+
+// Uncertainty describes how uncertain one factor score is.
+// StdDev draws from a normal distribution around the score; otherwise Min/Max draws uniformly
+// from that absolute range. Draws are clamped to 0-100.
+type Uncertainty struct {
+	StdDev float64 `json:"std_dev,omitempty"` // 정규분포 표준편차 (점)
+	Min    float64 `json:"min,omitempty"`     // 균등분포 하한 (점)
+	Max    float64 `json:"max,omitempty"`     // 균등분포 상한 (점)
+}
+
+// SimulationOptions configures a Monte Carlo simulation.
+type SimulationOptions struct {
+	Uncertainty   map[metadata.MetadataType]Uncertainty // 요소별 불확실성
+	DefaultStdDev float64                               // Uncertainty에 없는 요소의 표준편차 (0이면 고정)
+	Draws         int                                   // 시뮬레이션 횟수 (기본값: DefaultSimulationDraws)
+	Seed          int64                                 // 난수 시드 (같은 시드는 같은 결과)
+	Confidence    float64                               // 신뢰 구간 수준 % (기본값: DefaultDashboardConfidence)
+	TopK          int                                   // 순위 시뮬레이션에서 상위 K위 확률 기준 (기본값: 3)
+}
+
+// Quantiles summarizes a simulated distribution.
+type Quantiles struct {
+	P5  float64 `json:"p5"`
+	P25 float64 `json:"p25"`
+	P50 float64 `json:"p50"`
+	P75 float64 `json:"p75"`
+	P95 float64 `json:"p95"`
+}
+
+// HistogramBin counts values in [Lower, Upper).
+type HistogramBin struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+	Count int     `json:"count"`
+}
+
+// ScoreSimulation is the distribution of a total score under input uncertainty.
+type ScoreSimulation struct {
+	Strategy           StrategyType       `json:"strategy"`
+	Draws              int                `json:"draws"`
+	Mean               float64            `json:"mean"`
+	StdDev             float64            `json:"std_dev"`
+	Min                float64            `json:"min"`
+	Max                float64            `json:"max"`
+	Quantiles          Quantiles          `json:"quantiles"`
+	ConfidenceInterval ConfidenceInterval `json:"confidence_interval"`
+	Histogram          []HistogramBin     `json:"histogram"`
+}
+
+// ApartmentSimulation is one apartment's outcome across ranking simulations.
+type ApartmentSimulation struct {
+	Apartment       ApartmentData `json:"apartment"`
+	Score           Quantiles     `json:"score"`
+	MeanScore       float64       `json:"mean_score"`
+	MeanRank        float64       `json:"mean_rank"`
+	BestRank        int           `json:"best_rank"`
+	WorstRank       int           `json:"worst_rank"`
+	TopKProbability float64       `json:"top_k_probability"` // 상위 K위 안에 든 비율 (0-1)
+}
+
+// RankingSimulation summarizes how stable a ranking is under input uncertainty.
+type RankingSimulation struct {
+	Strategy   StrategyType          `json:"strategy"`
+	Draws      int                   `json:"draws"`
+	TopK       int                   `json:"top_k"`
+	Apartments []ApartmentSimulation `json:"apartments"` // 상위 K위 확률 순
+}
+
+// normalized fills in defaults.
+func (o SimulationOptions) normalized() SimulationOptions {
+	if o.Draws <= 0 {
+		o.Draws = DefaultSimulationDraws
+	}
+	if o.Confidence <= 0 || o.Confidence >= 100 {
+		o.Confidence = DefaultDashboardConfidence
+	}
+	if o.TopK <= 0 {
+		o.TopK = 3
+	}
+	return o
+}
+
+// validate rejects negative or inverted uncertainty.
+func (o SimulationOptions) validate() error {
+	if o.DefaultStdDev < 0 {
+		return &ValidationError{Field: "default_std_dev", Message: "표준편차는 음수일 수 없습니다"}
+	}
+	for mt, u := range o.Uncertainty {
+		if !mt.IsValid() {
+			return &ValidationError{Field: "uncertainty", Message: fmt.Sprintf(errUnknownMetadata, int(mt))}
+		}
+		if u.StdDev < 0 || u.Min > u.Max || u.Min < 0 || u.Max > 100 {
+			return &ValidationError{Field: mt.String(), Message: "불확실성은 표준편차 ≥ 0 또는 0 ≤ 최소 ≤ 최대 ≤ 100이어야 합니다"}
+		}
+	}
+	return nil
+}
+
+// draw returns a random score for a factor; missing factors stay missing.
+func (o SimulationOptions) draw(rng *rand.Rand, mt metadata.MetadataType, score shared.ScoreValue) shared.ScoreValue {
+	if score.IsMissing() {
+		return score
+	}
+	u, exists := o.Uncertainty[mt]
+	value := score.ToFloat()
+	switch {
+	case exists && u.StdDev > 0:
+		value += rng.NormFloat64() * u.StdDev
+	case exists && u.Max > u.Min:
+		value = u.Min + rng.Float64()*(u.Max-u.Min)
+	case !exists && o.DefaultStdDev > 0:
+		value += rng.NormFloat64() * o.DefaultStdDev
+	default:
+		return score
+	}
+	return shared.ScoreValueFromFloat(math.Max(0, math.Min(100, value)))
+}
+
+// SimulateScore propagates per-factor uncertainty through the strategy with Monte Carlo draws.
+func SimulateScore(scores map[metadata.MetadataType]shared.ScoreValue,
+	weights map[metadata.MetadataType]shared.Weight,
+	strategy StrategyType, opts SimulationOptions) (*ScoreSimulation, error) {
+	opts = opts.normalized()
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	scoreArray, weightArray, err := toArrays(scores, weights)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := CalculateWithStrategyArray(scoreArray, weightArray, strategy); err != nil {
+		return nil, err
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	totals := make([]float64, opts.Draws)
+	for d := range totals {
+		var drawn shared.ScoreArray
+		for i, n := 0, metadata.Count(); i < n; i++ {
+			drawn[i] = opts.draw(rng, metadata.MetadataType(i), scoreArray[i])
+		}
+		result, err := CalculateWithStrategyArray(drawn, weightArray, strategy)
+		if err != nil {
+			return nil, err
+		}
+		totals[d] = result.TotalScore
+	}
+
+	sorted := sortedCopy(totals)
+	alpha := (1 - opts.Confidence/100) / 2
+	return &ScoreSimulation{
+		Strategy:  strategy,
+		Draws:     opts.Draws,
+		Mean:      mean(sorted),
+		StdDev:    stdDev(sorted),
+		Min:       sorted[0],
+		Max:       sorted[len(sorted)-1],
+		Quantiles: quantilesOf(sorted),
+		ConfidenceInterval: ConfidenceInterval{
+			LowerBound: quantile(sorted, alpha),
+			UpperBound: quantile(sorted, 1-alpha),
+			Confidence: opts.Confidence,
+		},
+		Histogram: scoreHistogram(sorted),
+	}, nil
+}
+
+// SimulateRankings ranks the apartments once per draw and reports each apartment's score quantiles,
+// rank spread and probability of finishing in the top K.
+func SimulateRankings(apartments []ApartmentData, weights map[metadata.MetadataType]shared.Weight,
+	strategy StrategyType, opts SimulationOptions) (*RankingSimulation, error) {
+	if len(apartments) == 0 {
+		return nil, errors.New(errNoApartments)
+	}
+	opts = opts.normalized()
+	if err := opts.validate(); err != nil {
+		return nil, err
+	}
+	if _, err := scoreCohort(apartments, weights, strategy, MissingRenormalize); err != nil {
+		return nil, err
+	}
+
+	rng := rand.New(rand.NewSource(opts.Seed))
+	n := len(apartments)
+	samples := make([][]float64, n)
+	rankSums := make([]int, n)
+	topCounts := make([]int, n)
+	best := make([]int, n)
+	worst := make([]int, n)
+	drawn := make([]ApartmentData, n)
+	order := make([]int, n)
+	for d := 0; d < opts.Draws; d++ {
+		for i, apt := range apartments {
+			scores := make(map[metadata.MetadataType]shared.ScoreValue, len(apt.Scores))
+			for _, mt := range metadata.All() {
+				if score, exists := apt.Scores[mt]; exists {
+					scores[mt] = opts.draw(rng, mt, score)
+				}
+			}
+			drawn[i] = apt
+			drawn[i].Scores = scores
+		}
+		results, err := scoreCohort(drawn, weights, strategy, MissingRenormalize)
+		if err != nil {
+			return nil, err
+		}
+		for i := range order {
+			order[i] = i
+			samples[i] = append(samples[i], results[i].TotalScore)
+		}
+		sort.SliceStable(order, func(a, b int) bool { return results[order[a]].TotalScore > results[order[b]].TotalScore })
+		for position, i := range order {
+			rank := position + 1
+			rankSums[i] += rank
+			if rank <= opts.TopK {
+				topCounts[i]++
+			}
+			if best[i] == 0 || rank < best[i] {
+				best[i] = rank
+			}
+			if rank > worst[i] {
+				worst[i] = rank
+			}
+		}
+	}
+
+	simulation := &RankingSimulation{Strategy: strategy, Draws: opts.Draws, TopK: opts.TopK}
+	for i, apt := range apartments {
+		sorted := sortedCopy(samples[i])
+		simulation.Apartments = append(simulation.Apartments, ApartmentSimulation{
+			Apartment:       apt,
+			Score:           quantilesOf(sorted),
+			MeanScore:       mean(sorted),
+			MeanRank:        float64(rankSums[i]) / float64(opts.Draws),
+			BestRank:        best[i],
+			WorstRank:       worst[i],
+			TopKProbability: float64(topCounts[i]) / float64(opts.Draws),
+		})
+	}
+	sort.SliceStable(simulation.Apartments, func(a, b int) bool {
+		sa, sb := simulation.Apartments[a], simulation.Apartments[b]
+		if sa.TopKProbability != sb.TopKProbability {
+			return sa.TopKProbability > sb.TopKProbability
+		}
+		return sa.MeanRank < sb.MeanRank
+	})
+	return simulation, nil
+}
+
+// FormatRankingSimulation formats a ranking simulation as a readable string.
+func FormatRankingSimulation(simulation *RankingSimulation) string {
+	if simulation == nil {
+		return "시뮬레이션 데이터가 없습니다."
+	}
+	output := fmt.Sprintf("🎲 순위 시뮬레이션 (%s 전략, %d회)\n", GetStrategyDescription(simulation.Strategy), simulation.Draws)
+	output += "━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━\n"
+	for _, apt := range simulation.Apartments {
+		output += fmt.Sprintf("  %s: 상위 %d위 확률 %.0f%%, 평균 %.1f위 (%d-%d위), 점수 %.1f [%.1f - %.1f]\n",
+			apt.Apartment.Name, simulation.TopK, apt.TopKProbability*100, apt.MeanRank, apt.BestRank, apt.WorstRank,
+			apt.Score.P50, apt.Score.P5, apt.Score.P95)
+	}
+	return output
+}
+
+func quantilesOf(sorted []float64) Quantiles {
+	return Quantiles{
+		P5:  quantile(sorted, 0.05),
+		P25: quantile(sorted, 0.25),
+		P50: quantile(sorted, 0.50),
+		P75: quantile(sorted, 0.75),
+		P95: quantile(sorted, 0.95),
+	}
+}
+
+// scoreHistogram counts scores into equal-width bins over 0-100; 100 falls in the last bin.
+func scoreHistogram(values []float64) []HistogramBin {
+	bins := newScoreHistogram()
+	for _, v := range values {
+		countScore(bins, v)
+	}
+	return bins
+}
+
+// newScoreHistogram returns empty equal-width bins over 0-100.
+func newScoreHistogram() []HistogramBin {
+	width := 100.0 / simulationHistogramBins
+	bins := make([]HistogramBin, simulationHistogramBins)
+	for i := range bins {
+		bins[i].Lower = float64(i) * width
+		bins[i].Upper = float64(i+1) * width
+	}
+	return bins
+}
+
+// countScore adds a score to the bin containing it.
+func countScore(bins []HistogramBin, v float64) {
+	i := int(v / (100.0 / float64(len(bins))))
+	if i >= len(bins) {
+		i = len(bins) - 1
+	}
+	if i < 0 {
+		i = 0
+	}
+	bins[i].Count++
+}
@@ -0,0 +1,151 @@
+package scoring
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+
+	"apart_score/pkg/metadata"
+)
+
+func TestSimulateScore(t *testing.T) {
+	scores := getTestScores()
+	weights := getTestWeights()
+	base, _ := CalculateWithStrategy(scores, weights, StrategyWeightedSum)
+
+	fixed, err := SimulateScore(scores, weights, StrategyWeightedSum, SimulationOptions{Draws: 50})
+	if err != nil {
+		t.Fatalf("SimulateScore failed: %v", err)
+	}
+	if fixed.StdDev > 1e-6 || math.Abs(fixed.Mean-base.TotalScore) > 1e-6 {
+		t.Errorf("Expected no spread without uncertainty, got mean %.2f std %.2f", fixed.Mean, fixed.StdDev)
+	}
+
+	opts := SimulationOptions{
+		DefaultStdDev: 5,
+		Uncertainty:   map[metadata.MetadataType]Uncertainty{metadata.SchoolDistrict: {Min: 40, Max: 90}},
+		Draws:         2000,
+		Seed:          7,
+	}
+	simulation, err := SimulateScore(scores, weights, StrategyWeightedSum, opts)
+	if err != nil {
+		t.Fatalf("SimulateScore failed: %v", err)
+	}
+	if simulation.StdDev <= 0 {
+		t.Error("Expected spread with uncertainty")
+	}
+	q := simulation.Quantiles
+	if !(q.P5 <= q.P25 && q.P25 <= q.P50 && q.P50 <= q.P75 && q.P75 <= q.P95) {
+		t.Errorf("Expected ordered quantiles, got %+v", q)
+	}
+	ci := simulation.ConfidenceInterval
+	if ci.LowerBound > simulation.Mean || ci.UpperBound < simulation.Mean || ci.Confidence != DefaultDashboardConfidence {
+		t.Errorf("Expected mean inside the %.0f%% interval, got %+v", DefaultDashboardConfidence, ci)
+	}
+	count := 0
+	for _, bin := range simulation.Histogram {
+		count += bin.Count
+	}
+	if count != opts.Draws {
+		t.Errorf("Expected histogram to count %d draws, got %d", opts.Draws, count)
+	}
+
+	again, _ := SimulateScore(scores, weights, StrategyWeightedSum, opts)
+	if again.Mean != simulation.Mean {
+		t.Error("Expected the same seed to give the same simulation")
+	}
+
+	invalid := SimulationOptions{Uncertainty: map[metadata.MetadataType]Uncertainty{metadata.FloorLevel: {Min: 80, Max: 20}}}
+	if _, err := SimulateScore(scores, weights, StrategyWeightedSum, invalid); err == nil {
+		t.Error("Expected error for inverted range")
+	}
+}
+
+func TestSimulateRankings(t *testing.T) {
+	apartments := []ApartmentData{
+		{ID: "clear", Name: "확실한 1위", Scores: uniformScores(90)},
+		{ID: "close-a", Name: "접전 A", Scores: uniformScores(60)},
+		{ID: "close-b", Name: "접전 B", Scores: uniformScores(60.5)},
+	}
+	opts := SimulationOptions{DefaultStdDev: 5, Draws: 500, Seed: 3, TopK: 1}
+	for _, strategy := range []StrategyType{StrategyWeightedSum, StrategyTOPSIS} {
+		simulation, err := SimulateRankings(apartments, getTestWeights(), strategy, opts)
+		if err != nil {
+			t.Fatalf("%s: SimulateRankings failed: %v", strategy, err)
+		}
+		first := simulation.Apartments[0]
+		if first.Apartment.ID != "clear" || first.TopKProbability != 1 || first.WorstRank != 1 {
+			t.Errorf("%s: expected 'clear' to always win, got %+v", strategy, first)
+		}
+		for _, apt := range simulation.Apartments[1:] {
+			if apt.BestRank != 2 || apt.WorstRank != 3 {
+				t.Errorf("%s: expected close apartments to swap ranks, got %s %d-%d", strategy, apt.Apartment.ID, apt.BestRank, apt.WorstRank)
+			}
+		}
+		if !strings.Contains(FormatRankingSimulation(simulation), "확실한 1위") {
+			t.Errorf("%s: expected formatted simulation to list apartments", strategy)
+		}
+	}
+}
+
+func TestDashboardUsesSimulatedInterval(t *testing.T) {
+	scores := getTestScores()
+	weights := getTestWeights()
+	result, _ := CalculateWithStrategy(scores, weights, StrategyWeightedSum)
+
+	dashboard := GenerateTransparencyDashboard(result, scores, weights, StrategyWeightedSum)
+	ci := dashboard.ScoreDistribution.ConfidenceInterval
+	width := ci.UpperBound - ci.LowerBound
+	// 요소별 σ=5가 가중 평균되면 ±5보다 좁은 구간이 되어야 함
+	if width <= 0 || width >= 10 {
+		t.Errorf("Expected simulated interval narrower than ±5, got %+v", ci)
+	}
+
+	exact := SimulationOptions{}
+	fixed, _ := GenerateTransparencyDashboardWithOptions(result, scores, weights, StrategyWeightedSum,
+		DashboardOptions{Simulation: &exact})
+	if fixed.ScoreDistribution.ConfidenceInterval.UpperBound-fixed.ScoreDistribution.ConfidenceInterval.LowerBound > 1e-9 {
+		t.Error("Expected a zero-width interval without uncertainty")
+	}
+
+	var validation *ValidationError
+	if _, err := GenerateTransparencyDashboardWithOptions(result, scores, weights, StrategyWeightedSum,
+		DashboardOptions{Simulation: &SimulationOptions{DefaultStdDev: -1}}); !errors.As(err, &validation) {
+		t.Errorf("Expected the simulation error to be returned, got %v", err)
+	}
+
+	// Simulation.Seed가 0이면 대시보드 Seed를 사용
+	interval := func(seed int64) ConfidenceInterval {
+		dashboard, err := GenerateTransparencyDashboardWithOptions(result, scores, weights, StrategyWeightedSum,
+			DashboardOptions{Seed: seed, Simulation: &SimulationOptions{DefaultStdDev: 5, Draws: 200}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return dashboard.ScoreDistribution.ConfidenceInterval
+	}
+	if interval(1) != interval(1) || interval(1) == interval(2) {
+		t.Error("Expected the dashboard seed to drive a simulation without its own seed")
+	}
+}
+
+func TestRunningStats(t *testing.T) {
+	values := []float64{42, 87.5, 100, 0, 63.2, 63.2}
+	stats := NewRunningStats()
+	for _, v := range values {
+		stats.Add(v)
+	}
+	if stats.Count != len(values) || stats.Min != 0 || stats.Max != 100 {
+		t.Errorf("count %d, min %.1f, max %.1f", stats.Count, stats.Min, stats.Max)
+	}
+	if math.Abs(stats.Mean-mean(values)) > 1e-9 || math.Abs(stats.StdDev-stdDev(values)) > 1e-9 {
+		t.Errorf("mean %.4f, std dev %.4f, want %.4f, %.4f", stats.Mean, stats.StdDev, mean(values), stdDev(values))
+	}
+	if !reflect.DeepEqual(stats.Histogram, scoreHistogram(values)) {
+		t.Errorf("histogram = %+v", stats.Histogram)
+	}
+	if text := FormatRunningStats(stats); !strings.Contains(text, "점수 통계 (6개)") || !strings.Contains(text, " 60- 70") {
+		t.Errorf("formatted stats:\n%s", text)
+	}
+}
@@ -0,0 +1,130 @@
+package scoring
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+// RunningStats summarizes total scores added one at a time without keeping them, for inputs
+// too large to hold in memory.
+type RunningStats struct {
+	Count     int            `json:"count"`
+	Min       float64        `json:"min"`
+	Max       float64        `json:"max"`
+	Mean      float64        `json:"mean"`
+	StdDev    float64        `json:"std_dev"` // 표본 표준편차
+	Histogram []HistogramBin `json:"histogram"`
+	m2        float64        // 평균과의 편차 제곱합 (Welford)
+}
+
+// NewRunningStats returns empty statistics.
+func NewRunningStats() *RunningStats {
+	return &RunningStats{Histogram: newScoreHistogram()}
+}
+
+// Add records a total score.
+func (s *RunningStats) Add(score float64) {
+	s.Count++
+	if s.Count == 1 || score < s.Min {
+		s.Min = score
+	}
+	if s.Count == 1 || score > s.Max {
+		s.Max = score
+	}
+	delta := score - s.Mean
+	s.Mean += delta / float64(s.Count)
+	s.m2 += delta * (score - s.Mean)
+	if s.Count > 1 {
+		s.StdDev = math.Sqrt(s.m2 / float64(s.Count-1))
+	}
+	countScore(s.Histogram, score)
+}
+
+// FormatRunningStats formats running statistics with a text histogram.
+func FormatRunningStats(s *RunningStats) string {
+	if s == nil || s.Count == 0 {
+		return "점수를 계산한 아파트가 없습니다."
+	}
+	output := fmt.Sprintf("📈 점수 통계 (%d개)\n", s.Count)
+	output += "━━━━━━━━━━━━━━━━━━━━━━━━━\n"
+	output += fmt.Sprintf("평균 %.1f점 (표준편차 %.1f), 최저 %.1f점, 최고 %.1f점\n", s.Mean, s.StdDev, s.Min, s.Max)
+	largest := 0
+	for _, bin := range s.Histogram {
+		if bin.Count > largest {
+			largest = bin.Count
+		}
+	}
+	for _, bin := range s.Histogram {
+		bar := 0
+		if largest > 0 {
+			bar = int(math.Round(float64(bin.Count) / float64(largest) * 30))
+		}
+		output += fmt.Sprintf("  %3.0f-%3.0f: %-30s %d\n", bin.Lower, bin.Upper, strings.Repeat("█", bar), bin.Count)
+	}
+	return output
+}
+
+// mean returns the arithmetic mean of values, or 0 for an empty slice.
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+// stdDev returns the sample standard deviation of values.
+func stdDev(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+	m := mean(values)
+	sum := 0.0
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+	return math.Sqrt(sum / float64(len(values)-1))
+}
+
+// quantile returns the q-th quantile (0-1) of sorted values using linear interpolation.
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+	position := q * float64(len(sorted)-1)
+	lower := int(math.Floor(position))
+	upper := int(math.Ceil(position))
+	if lower == upper {
+		return sorted[lower]
+	}
+	return sorted[lower] + (sorted[upper]-sorted[lower])*(position-float64(lower))
+}
+
+// percentileRank returns the share of values below score, counting ties as half, in percent.
+func percentileRank(values []float64, score float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	below, equal := 0, 0
+	for _, v := range values {
+		if v < score {
+			below++
+		} else if v == score {
+			equal++
+		}
+	}
+	return (float64(below) + 0.5*float64(equal)) / float64(len(values)) * 100
+}
+
+// sortedCopy returns values sorted in ascending order without modifying the input.
+func sortedCopy(values []float64) []float64 {
+	sorted := make([]float64, len(values))
+	copy(sorted, values)
+	sort.Float64s(sorted)
+	return sorted
+}
@@ -5,17 +5,19 @@ import (
 	"apart_score/pkg/shared"
 	"errors"
 	"fmt"
-	"math"
 	"sort"
+	"strings"
 )
 
 // Error messages for validation
 const (
-	errInvalidScoreRange  = "잘못된 점수 범위 (%s: %.1f)"
-	errInvalidWeightRange = "잘못된 가중치 범위 (%s: %.3f)"
-	errWeightSumMismatch  = "가중치 합계가 1000이 아닙니다 (현재: %d)"
-	errNoApartments       = "순위를 매길 아파트가 없습니다"
-	errCalculationFailed  = "아파트 %s 점수 계산 실패: %w"
+	errInvalidScoreRange   = "잘못된 점수 범위 (%s: %.1f)"
+	errInvalidWeightRange  = "잘못된 가중치 범위 (%s: %.3f)"
+	errWeightSumMismatch   = "가중치 합계가 1000이 아닙니다 (현재: %d)"
+	errNoApartments        = "순위를 매길 아파트가 없습니다"
+	errCalculationFailed   = "아파트 %s 점수 계산 실패: %w"
+	errUnknownMetadata     = "등록되지 않은 메타데이터 타입: %d"
+	errUnsupportedStrategy = "지원하지 않는 전략: %s"
 )
 
 type StrategyType string
@@ -25,6 +27,7 @@ const (
 	StrategyGeometricMean StrategyType = "geometric_mean"
 	StrategyMinMax        StrategyType = "min_max"
 	StrategyHarmonicMean  StrategyType = "harmonic_mean"
+	StrategyTOPSIS        StrategyType = "topsis"
 )
 
 type ApartmentData struct {
@@ -34,17 +37,19 @@ type ApartmentData struct {
 	Location string                                      `json:"location"`
 }
 type RankingResult struct {
-	Apartment  ApartmentData                             `json:"apartment"`
-	Score      float64                                   `json:"score"`
-	Rank       int                                       `json:"rank"`
-	Percentile float64                                   `json:"percentile"`
-	Method     StrategyType                              `json:"method"`
-	Weights    [metadata.MetadataTypeCount]shared.Weight `json:"weights"`
+	Apartment  ApartmentData      `json:"apartment"`
+	Score      float64            `json:"score"`
+	Rank       int                `json:"rank"`
+	Percentile float64            `json:"percentile"`
+	Method     StrategyType       `json:"method"`
+	Weights    shared.WeightArray `json:"weights"`
+	Closeness  *float64           `json:"closeness,omitempty"` // 이상해 근접도 (코호트 전략 전용, 0-1, 그 외 nil)
 }
 type RankingsSummary struct {
-	TotalApartments int             `json:"total_apartments"`
+	TotalApartments int             `json:"total_apartments"` // 제약 조건과 파레토 필터 후 순위를 매긴 아파트 수 (모두 제외되면 0)
 	Strategy        StrategyType    `json:"strategy"`
 	TopRanked       []RankingResult `json:"top_ranked"`
+	Eliminated      []Elimination   `json:"eliminated,omitempty"` // 제약 조건이나 파레토 필터로 제외된 아파트
 	ScoreRange      struct {
 		Min float64 `json:"min"`
 		Max float64 `json:"max"`
@@ -57,161 +62,89 @@ func CalculateWithStrategy(scores map[metadata.MetadataType]shared.ScoreValue,
 	weights map[metadata.MetadataType]shared.Weight,
 	strategy StrategyType) (ScoreResult, error) {
 	// Convert maps to arrays for better performance
-	scoreArray := shared.ScoreArray{}
-	weightArray := shared.WeightArray{}
-
-	for mt, score := range scores {
-		scoreArray[int(mt)] = score
-	}
-	for mt, weight := range weights {
-		weightArray[int(mt)] = weight
+	scoreArray, weightArray, err := toArrays(scores, weights)
+	if err != nil {
+		return ScoreResult{}, err
 	}
 
 	return CalculateWithStrategyArray(scoreArray, weightArray, strategy)
 }
 
 // CalculateWithStrategyArray calculates scores using arrays (recommended for performance).
-func CalculateWithStrategyArray(scores [14]shared.ScoreValue,
-	weights [14]shared.Weight,
+// Factors marked with shared.MissingScore are dropped and the remaining weights renormalized.
+func CalculateWithStrategyArray(scores shared.ScoreArray,
+	weights shared.WeightArray,
 	strategy StrategyType) (ScoreResult, error) {
-	if err := validateStrategyInputsArray(scores, weights); err != nil {
-		return ScoreResult{}, err
-	}
-
-	result := ScoreResult{Method: StrategyWeightedSum}
-
-	switch strategy {
-	case StrategyWeightedSum:
-		totalWeightedSum := 0.0
-		totalWeight := shared.Weight(0)
-		for i := range scores {
-			rawScore := scores[i]
-			weight := weights[i]
-			weightedScore := shared.MulDivWeight(rawScore, weight)
-			result.RawScores[i] = rawScore
-			result.Weights[i] = weight
-			result.WeightedScores[i] = weightedScore.ToFloat()
-			totalWeightedSum += weightedScore.ToFloat()
-			totalWeight += weight
-		}
-		if totalWeight > 0 {
-			result.TotalScore = totalWeightedSum / (float64(totalWeight) / float64(shared.WeightScale))
-		}
-		result.Method = StrategyWeightedSum
-
-	case StrategyGeometricMean:
-		minScore := shared.ScoreValueFromFloat(0.1)
-		logSum := 0.0
-		totalWeight := shared.Weight(0)
-		for i := range scores {
-			rawScore := scores[i]
-			weight := weights[i]
-			if rawScore < minScore {
-				rawScore = minScore
-			}
-			logVal := math.Log(rawScore.ToFloat()) * weight.ToFloat()
-			result.RawScores[i] = scores[i]
-			result.Weights[i] = weight
-			result.WeightedScores[i] = rawScore.ToFloat()
-			logSum += logVal
-			totalWeight += weight
-		}
-		if totalWeight > 0 {
-			result.TotalScore = math.Exp(logSum / totalWeight.ToFloat())
-		}
-		result.Method = StrategyGeometricMean
-
-	case StrategyMinMax:
-		minScore := shared.ScoreValueFromFloat(100.0)
-		for i := range scores {
-			rawScore := scores[i]
-			weight := weights[i]
-			result.RawScores[i] = rawScore
-			result.Weights[i] = weight
-			weightedScore := shared.MulDivWeight(rawScore, weight)
-			result.WeightedScores[i] = weightedScore.ToFloat()
-			if weightedScore < minScore {
-				minScore = weightedScore
-			}
-		}
-		result.TotalScore = minScore.ToFloat()
-		result.Method = StrategyMinMax
-
-	case StrategyHarmonicMean:
-		minScore := shared.ScoreValueFromFloat(0.1)
-		weightedHarmonicSum := 0.0
-		totalWeight := shared.Weight(0)
-		for i := range scores {
-			rawScore := scores[i]
-			weight := weights[i]
-			if rawScore < minScore {
-				rawScore = minScore
-			}
-			weightedHarmonicSum += weight.ToFloat() / rawScore.ToFloat()
-			result.RawScores[i] = scores[i]
-			result.Weights[i] = weight
-			result.WeightedScores[i] = rawScore.ToFloat()
-			totalWeight += weight
-		}
-		if weightedHarmonicSum > 0 && totalWeight > 0 {
-			result.TotalScore = totalWeight.ToFloat() / weightedHarmonicSum
-		}
-		result.Method = StrategyHarmonicMean
-
-	default:
-		return ScoreResult{}, fmt.Errorf("지원하지 않는 전략: %s", strategy)
-	}
-
-	return result, nil
+	return calculateArray(scores, weights, strategy, MissingRenormalize)
 }
-func validateStrategyInputsArray(scores [14]shared.ScoreValue,
-	weights [14]shared.Weight) error {
-	for i, score := range scores {
+func validateStrategyInputsArray(scores shared.ScoreArray,
+	weights shared.WeightArray) error {
+	n := metadata.Count()
+	for i := 0; i < n; i++ {
+		score := scores[i]
+		if score.IsMissing() {
+			continue
+		}
 		if score < 0 || score > 100*shared.ScoreScale {
 			mt := metadata.MetadataType(i)
-			return fmt.Errorf(errInvalidScoreRange, mt.String(), score.ToFloat())
+			return &ValidationError{Field: mt.String(), Message: fmt.Sprintf(errInvalidScoreRange, mt.String(), score.ToFloat())}
 		}
 	}
 	totalWeight := shared.Weight(0)
-	for i, weight := range weights {
+	for i := 0; i < n; i++ {
+		weight := weights[i]
 		if weight < 0 || weight > shared.WeightScale {
 			mt := metadata.MetadataType(i)
-			return fmt.Errorf(errInvalidWeightRange, mt.String(), weight.ToFloat())
+			return &ValidationError{Field: mt.String(), Message: fmt.Sprintf(errInvalidWeightRange, mt.String(), weight.ToFloat())}
 		}
 		totalWeight += weight
 	}
 	if totalWeight < shared.WeightScale-1 || totalWeight > shared.WeightScale+1 {
-		return fmt.Errorf(errWeightSumMismatch, totalWeight)
+		return &ValidationError{Field: "weights", Message: fmt.Sprintf(errWeightSumMismatch, totalWeight)}
 	}
 	return nil
 }
 
 func validateStrategyInputs(scores map[metadata.MetadataType]shared.ScoreValue,
 	weights map[metadata.MetadataType]shared.Weight) error {
-	// Convert MetadataType maps to int maps for shared functions
-	intScores := make(map[int]shared.ScoreValue)
-	intWeights := make(map[int]shared.Weight)
+	scoreArray, weightArray, err := toArrays(scores, weights)
+	if err != nil {
+		return err
+	}
+	return validateStrategyInputsArray(scoreArray, weightArray)
+}
+
+// toArrays converts score and weight maps into arrays, rejecting unregistered metadata types.
+func toArrays(scores map[metadata.MetadataType]shared.ScoreValue,
+	weights map[metadata.MetadataType]shared.Weight) (shared.ScoreArray, shared.WeightArray, error) {
+	var scoreArray shared.ScoreArray
+	var weightArray shared.WeightArray
 	for mt, score := range scores {
-		intScores[int(mt)] = score
+		if !mt.IsValid() {
+			return scoreArray, weightArray, fmt.Errorf(errUnknownMetadata, int(mt))
+		}
+		scoreArray[mt] = score
 	}
 	for mt, weight := range weights {
-		intWeights[int(mt)] = weight
+		if !mt.IsValid() {
+			return scoreArray, weightArray, fmt.Errorf(errUnknownMetadata, int(mt))
+		}
+		weightArray[mt] = weight
 	}
-
-	scoreArray := shared.NewScoreArrayFromMap(intScores)
-	weightArray := shared.NewWeightArrayFromMap(intWeights)
-	return validateStrategyInputsArray(scoreArray, weightArray)
+	return scoreArray, weightArray, nil
 }
+
+// GetAvailableStrategies returns the built-in strategies followed by registered custom strategies.
 func GetAvailableStrategies() []StrategyType {
-	return []StrategyType{
-		StrategyWeightedSum,
-		StrategyGeometricMean,
-		StrategyMinMax,
-		StrategyHarmonicMean,
-	}
+	strategyMu.RLock()
+	defer strategyMu.RUnlock()
+	available := make([]StrategyType, len(strategyOrder))
+	copy(available, strategyOrder)
+	return available
 }
 
 // StrategyGuidelines defines when and how to use each calculation strategy.
+// Custom strategies add their guide through RegisterStrategy; use GetStrategyGuide for concurrent reads.
 var StrategyGuidelines = map[StrategyType]StrategyGuide{
 	StrategyWeightedSum: {
 		UseCase:     "일반적인 선형 평가",
@@ -234,7 +167,7 @@ var StrategyGuidelines = map[StrategyType]StrategyGuide{
 	StrategyMinMax: {
 		UseCase:     "최소 요구사항 평가",
 		BestFor:     "필수 조건이 엄격한 상황",
-		WhenToUse:   "안전 기준, 법적 요구사항, 하드 커트라인 존재",
+		WhenToUse:   "안전 기준, 법적 요구사항, 하드 커트라인 존재 (커트라인 미달 후보는 Constraints로 사전 제외)",
 		Limitations: "다른 요소의 장점 무시, 너무 엄격할 수 있음",
 		Example:     "안전 기준 평가, 최소 주거 조건, 자격 요건 확인",
 		Strengths:   []string{"안전성 보장", "명확한 기준", "패스/페일 명확"},
@@ -249,6 +182,15 @@ var StrategyGuidelines = map[StrategyType]StrategyGuide{
 		Strengths:   []string{"효율성 강조", "비용 고려", "균형적 역수 평가"},
 		Weaknesses:  []string{"복잡성", "직관성 부족", "제한적 사용성"},
 	},
+	StrategyTOPSIS: {
+		UseCase:     "후보군 상대 평가",
+		BestFor:     "여러 아파트 후보를 서로 비교해 순위를 매기는 상황",
+		WhenToUse:   "후보 목록이 확정되었을 때, 최선/최악 후보와의 거리로 비교하고 싶을 때",
+		Limitations: "점수가 후보군 구성에 따라 달라짐, 후보 추가 시 순위 역전 가능",
+		Example:     "매물 후보 비교, 최종 후보 압축, 상대 순위 산정",
+		Strengths:   []string{"후보군 맥락 반영", "이상해 기준 비교", "직관적 근접도"},
+		Weaknesses:  []string{"단일 평가 불가", "후보군 의존성", "순위 역전 현상"},
+	},
 }
 
 // StrategyGuide provides detailed guidance for using a calculation strategy.
@@ -260,6 +202,7 @@ type StrategyGuide struct {
 	Example     string   // 실제 적용 예시
 	Strengths   []string // 장점들
 	Weaknesses  []string // 단점들
+	Description string   // 전략 설명 (사용자 정의 전략용)
 }
 
 func GetStrategyDescription(strategy StrategyType) string {
@@ -272,114 +215,165 @@ func GetStrategyDescription(strategy StrategyType) string {
 		return "모든 요소가 일정 수준 이상이어야 하는 경우에 적합합니다. 가장 낮은 점수가 전체 점수를 결정합니다."
 	case StrategyHarmonicMean:
 		return "낮은 점수에 매우 민감하게 반응하는 전략입니다. 모든 요소가 고르게 중요할 때 사용합니다."
+	case StrategyTOPSIS:
+		return "후보군의 이상해와 최악해까지의 거리로 근접도를 계산하는 상대 평가 전략입니다."
 	default:
+		if guide, exists := GetStrategyGuide(strategy); exists && guide.Description != "" {
+			return guide.Description
+		}
 		return "알 수 없는 전략입니다."
 	}
 }
 
-// CalculateWithPipeline performs scoring using a custom calculation pipeline.
+// CalculateWithPipeline performs scoring using a custom calculation pipeline. Conditions and
+// Apply functions see the running total along with the scores and weights as arrays.
 func CalculateWithPipeline(scores map[metadata.MetadataType]shared.ScoreValue,
 	weights map[metadata.MetadataType]shared.Weight,
 	pipeline CalculationPipeline) (ScoreResult, error) {
+	result, _, err := CalculateWithPipelineTrace(scores, weights, pipeline)
+	return result, err
+}
+
+// CalculateWithPipelineTrace performs scoring like CalculateWithPipeline and also returns the
+// trace of the run: every step in execution order, whether it ran and what it contributed.
+func CalculateWithPipelineTrace(scores map[metadata.MetadataType]shared.ScoreValue,
+	weights map[metadata.MetadataType]shared.Weight,
+	pipeline CalculationPipeline) (ScoreResult, *PipelineTrace, error) {
 
 	result := ScoreResult{
 		Method:   StrategyWeightedSum, // 기본값
 		Scenario: ScenarioBalanced,    // 기본값
 	}
+	scoreArray, weightArray, err := toArrays(scores, weights)
+	if err != nil {
+		return ScoreResult{}, nil, err
+	}
+	result.RawScores = scoreArray
+	result.Weights = weightArray
 
 	// 우선순위에 따라 스텝 정렬 (낮은 우선순위가 먼저 실행)
 	sortedSteps := make([]CalculationStep, len(pipeline.Steps))
 	copy(sortedSteps, pipeline.Steps)
-	sort.Slice(sortedSteps, func(i, j int) bool {
+	sort.SliceStable(sortedSteps, func(i, j int) bool {
 		return sortedSteps[i].Priority < sortedSteps[j].Priority
 	})
 
 	// 각 스텝 실행
+	trace := &PipelineTrace{Pipeline: pipeline.Name, Steps: make([]PipelineStepTrace, 0, len(sortedSteps))}
 	totalScore := 0.0
 	for _, step := range sortedSteps {
 		// 현재까지의 결과로 조건 확인
 		tempResult := result
 		tempResult.TotalScore = totalScore
 
-		if step.Condition == nil || step.Condition(tempResult) {
-			stepScore := step.Calculator(scores, weights)
-			totalScore += stepScore
+		stepTrace := PipelineStepTrace{
+			Name:        step.Name,
+			Description: step.Description,
+			Priority:    step.Priority,
+			Conditional: step.Condition != nil,
+			Fired:       step.Condition == nil || step.Condition(tempResult),
+		}
+		if stepTrace.Fired {
+			if step.Apply == nil {
+				totalScore += step.Calculator(scores, weights)
+			} else if totalScore, err = step.Apply(tempResult); err != nil {
+				return ScoreResult{}, nil, fmt.Errorf("파이프라인 단계 %s 실패: %w", step.Name, err)
+			}
 		}
+		stepTrace.Contribution = totalScore - tempResult.TotalScore
+		stepTrace.Total = totalScore
+		trace.Steps = append(trace.Steps, stepTrace)
 	}
 
 	result.TotalScore = totalScore
-	return result, nil
+	trace.TotalScore = totalScore
+	return result, trace, nil
 }
 
-// CreateFamilyPipeline creates a family-oriented calculation pipeline.
+// CreateFamilyPipeline creates a family-oriented calculation pipeline from FamilyPipelineSpec.
 func CreateFamilyPipeline() CalculationPipeline {
-	return CalculationPipeline{
-		Name:        "가족 중심 평가",
-		Description: "학군, 크기/가격 균형, 교통 접근성을 고려한 가족 중심 평가",
-		Steps: []CalculationStep{
-			{
-				Name:        "학군 우선 평가",
-				Description: "학군 점수를 40% 가중치로 평가",
-				Priority:    1,
-				Condition:   nil, // 항상 실행
-				Calculator: func(scores map[metadata.MetadataType]shared.ScoreValue, weights map[metadata.MetadataType]shared.Weight) float64 {
-					schoolScore := scores[metadata.SchoolDistrict].ToFloat()
-					return schoolScore * 0.4
-				},
-			},
-			{
-				Name:        "크기/가격 균형",
-				Description: "아파트 크기와 가격의 균형을 40%로 평가",
-				Priority:    2,
-				Condition:   nil,
-				Calculator: func(scores map[metadata.MetadataType]shared.ScoreValue, weights map[metadata.MetadataType]shared.Weight) float64 {
-					sizeScore := scores[metadata.ApartmentSize].ToFloat() * 0.6
-					priceScore := scores[metadata.MaintenanceFee].ToFloat() * 0.4
-					return (sizeScore + priceScore) * 0.4
-				},
-			},
-			{
-				Name:        "교통 보너스",
-				Description: "교통 접근성이 좋으면 보너스 점수",
-				Priority:    3,
-				Condition: func(result ScoreResult) bool {
-					return result.TotalScore > 60 // 기본 점수가 60점 이상일 때만 보너스
-				},
-				Calculator: func(scores map[metadata.MetadataType]shared.ScoreValue, weights map[metadata.MetadataType]shared.Weight) float64 {
-					transportScore := scores[metadata.TransportationAccess].ToFloat()
-					if transportScore >= 85 {
-						return 5 * 0.2
-					} else if transportScore >= 75 {
-						return 2 * 0.2
-					}
-					return 0
-				},
-			},
-		},
-	}
+	pipeline, _ := FamilyPipelineSpec().Compile() // 내장 명세는 항상 유효함 (테스트에서 검증)
+	return pipeline
 }
+
 func CalculateRankings(apartments []ApartmentData, weights map[metadata.MetadataType]shared.Weight, strategy StrategyType) (*RankingsSummary, error) {
+	return CalculateRankingsWithOptions(apartments, weights, strategy, RankingOptions{})
+}
+
+// RankingOptions configures optional steps applied by CalculateRankingsWithOptions.
+type RankingOptions struct {
+	Constraints   *Constraints       // 순위 계산 전 적용할 필수 조건 (nil이면 적용 안 함)
+	MissingPolicy MissingValuePolicy // 결측값 처리 정책 (기본값: MissingRenormalize)
+	// ValueFunctions convert raw scores to utilities after constraints are applied, so minimum
+	// scores refer to raw scores. Ranked apartments keep their raw scores.
+	ValueFunctions ValueFunctions
+	// Pareto removes apartments dominated by another one after constraints, comparing the
+	// utilities of the factors it lists (nil이면 적용 안 함).
+	Pareto *ParetoOptions
+}
+
+// CalculateRankingsWithOptions ranks apartments after applying the optional filters in opts.
+// Apartments removed by constraints or the Pareto filter are reported in RankingsSummary.Eliminated.
+func CalculateRankingsWithOptions(apartments []ApartmentData, weights map[metadata.MetadataType]shared.Weight,
+	strategy StrategyType, opts RankingOptions) (*RankingsSummary, error) {
 	if len(apartments) == 0 {
 		return nil, errors.New(errNoApartments)
 	}
+	// 제약 조건이 모두를 제외하더라도 잘못된 입력은 오류로 알림
+	if _, exists := LookupStrategy(strategy); !exists {
+		return nil, fmt.Errorf(errUnsupportedStrategy, strategy)
+	}
 	if err := validateStrategyInputs(apartments[0].Scores, weights); err != nil {
 		return nil, fmt.Errorf("입력 검증 실패: %w", err)
 	}
+	if err := opts.MissingPolicy.Validate(); err != nil {
+		return nil, err
+	}
+	if err := opts.ValueFunctions.Validate(); err != nil {
+		return nil, err
+	}
+	var eliminated []Elimination
+	if opts.Constraints != nil {
+		if err := opts.Constraints.Validate(); err != nil {
+			return nil, fmt.Errorf("제약 조건 검증 실패: %w", err)
+		}
+		apartments, eliminated = ApplyConstraints(apartments, *opts.Constraints)
+		if len(apartments) == 0 {
+			return &RankingsSummary{TotalApartments: 0, Strategy: strategy, Eliminated: eliminated}, nil
+		}
+	}
+	utilities := opts.ValueFunctions.applyAll(apartments)
+	if opts.Pareto != nil {
+		analysis, err := AnalyzePareto(utilities, *opts.Pareto)
+		if err != nil {
+			return nil, fmt.Errorf("파레토 필터 검증 실패: %w", err)
+		}
+		var dominated []Elimination
+		apartments, dominated = analysis.split(apartments)
+		utilities, _ = analysis.split(utilities)
+		eliminated = append(eliminated, dominated...)
+	}
+	results, err := scoreCohort(utilities, weights, strategy, opts.MissingPolicy)
+	if err != nil {
+		return nil, err
+	}
 	var rankings []RankingResult
 	var totalScore float64
 	minScore := 100.0
 	maxScore := 0.0
-	for _, apt := range apartments {
-		result, err := CalculateWithStrategy(apt.Scores, weights, strategy)
-		if err != nil {
-			return nil, fmt.Errorf(errCalculationFailed, apt.ID, err)
-		}
+	isCohort := IsCohortStrategy(strategy)
+	for i, apt := range apartments {
+		result := results[i]
 		ranking := RankingResult{
 			Apartment: apt,
 			Score:     result.TotalScore,
 			Method:    result.Method,
 			Weights:   result.Weights,
 		}
+		if isCohort {
+			closeness := result.Closeness
+			ranking.Closeness = &closeness
+		}
 		rankings = append(rankings, ranking)
 		totalScore += result.TotalScore
 		if result.TotalScore < minScore {
@@ -404,12 +398,91 @@ func CalculateRankings(apartments []ApartmentData, weights map[metadata.Metadata
 		TotalApartments: len(apartments),
 		Strategy:        strategy,
 		TopRanked:       rankings,
+		Eliminated:      eliminated,
 	}
 	summary.ScoreRange.Min = minScore
 	summary.ScoreRange.Max = maxScore
 	summary.ScoreRange.Avg = totalScore / float64(len(apartments))
 	return summary, nil
 }
+
+// scoreCohort scores every apartment, letting cohort strategies see the whole set at once.
+// Cohort strategies share one weight vector, so missing factors are always imputed with the
+// cohort median for them unless the policy is MissingFail.
+func scoreCohort(apartments []ApartmentData, weights map[metadata.MetadataType]shared.Weight,
+	strategy StrategyType, policy MissingValuePolicy) ([]ScoreResult, error) {
+	impl, exists := LookupStrategy(strategy)
+	if !exists {
+		return nil, fmt.Errorf(errUnsupportedStrategy, strategy)
+	}
+	cohort, isCohort := impl.(CohortStrategy)
+
+	scoreArrays := make([]shared.ScoreArray, len(apartments))
+	var weightArray shared.WeightArray
+	for i, apt := range apartments {
+		scoreArray, aptWeights, err := toArrays(apt.Scores, weights)
+		if err == nil {
+			err = validateStrategyInputsArray(scoreArray, aptWeights)
+		}
+		if err != nil {
+			return nil, fmt.Errorf(errCalculationFailed, apt.ID, err)
+		}
+		scoreArrays[i] = scoreArray
+		weightArray = aptWeights
+	}
+
+	imputed := make([][]metadata.MetadataType, len(apartments))
+	if policy == MissingImputeMedian || (isCohort && policy != MissingFail) {
+		medians, available := cohortMedians(scoreArrays)
+		for i := range scoreArrays {
+			scoreArrays[i], imputed[i] = imputeMissing(scoreArrays[i], medians, available)
+		}
+	}
+
+	if !isCohort {
+		// 중앙값으로도 채울 수 없는 요소는 가중치 재정규화로 처리
+		remaining := policy
+		if remaining == MissingImputeMedian {
+			remaining = MissingRenormalize
+		}
+		results := make([]ScoreResult, len(apartments))
+		for i, apt := range apartments {
+			result, err := calculateArray(scoreArrays[i], weightArray, strategy, remaining)
+			if err != nil {
+				return nil, fmt.Errorf(errCalculationFailed, apt.ID, err)
+			}
+			result.Imputed = imputed[i]
+			results[i] = result
+		}
+		return results, nil
+	}
+
+	for i, apt := range apartments {
+		for j, n := 0, metadata.Count(); j < n; j++ {
+			if !scoreArrays[i][j].IsMissing() {
+				continue
+			}
+			if policy == MissingFail && weightArray[j] > 0 {
+				mt := metadata.MetadataType(j)
+				return nil, fmt.Errorf(errCalculationFailed, apt.ID,
+					&ValidationError{Field: mt.String(), Message: fmt.Sprintf(errMissingValue, mt.KoreanName())})
+			}
+			// 코호트 전체에 데이터가 없는 요소는 모든 아파트에 동일하게 0점을 적용
+			scoreArrays[i][j] = 0
+		}
+	}
+	results, err := cohort.CalculateCohort(scoreArrays, weightArray)
+	if err != nil {
+		return nil, err
+	}
+	for i := range results {
+		if results[i].Method == "" {
+			results[i].Method = strategy
+		}
+		results[i].Imputed = imputed[i]
+	}
+	return results, nil
+}
 func FormatRankings(summary *RankingsSummary, limit int) string {
 	if summary == nil {
 		return "순위 데이터가 없습니다."
@@ -436,6 +509,16 @@ func FormatRankings(summary *RankingsSummary, limit int) string {
 	if displayCount < len(summary.TopRanked) {
 		output += fmt.Sprintf("\n... 외 %d개 아파트", len(summary.TopRanked)-displayCount)
 	}
+	if len(summary.Eliminated) > 0 {
+		output += fmt.Sprintf("\n\n🚫 순위 계산 전 제외된 아파트: %d개\n", len(summary.Eliminated))
+		for _, elimination := range summary.Eliminated {
+			reasons := make([]string, len(elimination.Violations))
+			for i, violation := range elimination.Violations {
+				reasons[i] = violation.Message
+			}
+			output += fmt.Sprintf("  • %s: %s\n", elimination.Apartment.Name, strings.Join(reasons, ", "))
+		}
+	}
 	return output
 }
 func getRankEmoji(rank int) string {
@@ -150,6 +150,7 @@ func TestGetAvailableStrategies(t *testing.T) {
 		StrategyGeometricMean,
 		StrategyMinMax,
 		StrategyHarmonicMean,
+		StrategyTOPSIS,
 	}
 
 	if len(strategies) != len(expected) {
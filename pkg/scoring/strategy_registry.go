@@ -0,0 +1,179 @@
+package scoring
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"fmt"
+	"math"
+	"sync"
+)
+
+// Strategy aggregates per-factor scores into a total score.
+// Implementations only need to read indices below metadata.Count(); inputs are validated beforehand.
+type Strategy interface {
+	Type() StrategyType
+	Calculate(scores shared.ScoreArray, weights shared.WeightArray) (ScoreResult, error)
+}
+
+var (
+	strategyMu       sync.RWMutex
+	strategyRegistry = map[StrategyType]Strategy{
+		StrategyWeightedSum:   weightedSumStrategy{},
+		StrategyGeometricMean: geometricMeanStrategy{},
+		StrategyMinMax:        minMaxStrategy{},
+		StrategyHarmonicMean:  harmonicMeanStrategy{},
+		StrategyTOPSIS:        topsisStrategy{},
+	}
+	strategyOrder = []StrategyType{
+		StrategyWeightedSum,
+		StrategyGeometricMean,
+		StrategyMinMax,
+		StrategyHarmonicMean,
+		StrategyTOPSIS,
+	}
+)
+
+// RegisterStrategy makes a custom strategy available to CalculateWithStrategy, CalculateRankings,
+// GetAvailableStrategies, StrategyGuidelines and the transparency dashboard.
+// It is meant to be called during initialization, before scoring starts.
+func RegisterStrategy(strategy Strategy, guide StrategyGuide) error {
+	if strategy == nil || strategy.Type() == "" {
+		return fmt.Errorf("전략 타입은 필수입니다")
+	}
+	strategyType := strategy.Type()
+
+	strategyMu.Lock()
+	defer strategyMu.Unlock()
+	if _, exists := strategyRegistry[strategyType]; exists {
+		return fmt.Errorf("이미 등록된 전략: %s", strategyType)
+	}
+	strategyRegistry[strategyType] = strategy
+	strategyOrder = append(strategyOrder, strategyType)
+	StrategyGuidelines[strategyType] = guide
+	return nil
+}
+
+// LookupStrategy returns the registered implementation of the strategy type.
+func LookupStrategy(strategyType StrategyType) (Strategy, bool) {
+	strategyMu.RLock()
+	defer strategyMu.RUnlock()
+	strategy, exists := strategyRegistry[strategyType]
+	return strategy, exists
+}
+
+// IsCohortStrategy reports whether the strategy is registered and scores apartments relative to
+// their cohort, as TOPSIS does. Only cohort strategies report a closeness.
+func IsCohortStrategy(strategyType StrategyType) bool {
+	impl, exists := LookupStrategy(strategyType)
+	return exists && isCohortStrategy(impl)
+}
+
+// GetStrategyGuide returns the usage guide of a built-in or registered strategy.
+func GetStrategyGuide(strategyType StrategyType) (StrategyGuide, bool) {
+	strategyMu.RLock()
+	defer strategyMu.RUnlock()
+	guide, exists := StrategyGuidelines[strategyType]
+	return guide, exists
+}
+
+type weightedSumStrategy struct{}
+
+func (weightedSumStrategy) Type() StrategyType { return StrategyWeightedSum }
+
+func (weightedSumStrategy) Calculate(scores shared.ScoreArray, weights shared.WeightArray) (ScoreResult, error) {
+	result := ScoreResult{Method: StrategyWeightedSum}
+	totalWeightedSum := 0.0
+	totalWeight := shared.Weight(0)
+	for i, n := 0, metadata.Count(); i < n; i++ {
+		rawScore := scores[i]
+		weight := weights[i]
+		weightedScore := shared.MulDivWeight(rawScore, weight)
+		result.RawScores[i] = rawScore
+		result.Weights[i] = weight
+		result.WeightedScores[i] = weightedScore.ToFloat()
+		totalWeightedSum += weightedScore.ToFloat()
+		totalWeight += weight
+	}
+	if totalWeight > 0 {
+		result.TotalScore = totalWeightedSum / (float64(totalWeight) / float64(shared.WeightScale))
+	}
+	return result, nil
+}
+
+type geometricMeanStrategy struct{}
+
+func (geometricMeanStrategy) Type() StrategyType { return StrategyGeometricMean }
+
+func (geometricMeanStrategy) Calculate(scores shared.ScoreArray, weights shared.WeightArray) (ScoreResult, error) {
+	result := ScoreResult{Method: StrategyGeometricMean}
+	minScore := shared.ScoreValueFromFloat(0.1)
+	logSum := 0.0
+	totalWeight := shared.Weight(0)
+	for i, n := 0, metadata.Count(); i < n; i++ {
+		rawScore := scores[i]
+		weight := weights[i]
+		if rawScore < minScore {
+			rawScore = minScore
+		}
+		logVal := math.Log(rawScore.ToFloat()) * weight.ToFloat()
+		result.RawScores[i] = scores[i]
+		result.Weights[i] = weight
+		result.WeightedScores[i] = rawScore.ToFloat()
+		logSum += logVal
+		totalWeight += weight
+	}
+	if totalWeight > 0 {
+		result.TotalScore = math.Exp(logSum / totalWeight.ToFloat())
+	}
+	return result, nil
+}
+
+type minMaxStrategy struct{}
+
+func (minMaxStrategy) Type() StrategyType { return StrategyMinMax }
+
+func (minMaxStrategy) Calculate(scores shared.ScoreArray, weights shared.WeightArray) (ScoreResult, error) {
+	result := ScoreResult{Method: StrategyMinMax}
+	minScore := shared.ScoreValueFromFloat(100.0)
+	for i, n := 0, metadata.Count(); i < n; i++ {
+		rawScore := scores[i]
+		weight := weights[i]
+		result.RawScores[i] = rawScore
+		result.Weights[i] = weight
+		weightedScore := shared.MulDivWeight(rawScore, weight)
+		result.WeightedScores[i] = weightedScore.ToFloat()
+		// 가중치 없는 등록 요소는 평가 대상이 아니므로 제외 (기본 요소는 기존대로 비교)
+		if (weight > 0 || metadata.MetadataType(i).IsBuiltin()) && weightedScore < minScore {
+			minScore = weightedScore
+		}
+	}
+	result.TotalScore = minScore.ToFloat()
+	return result, nil
+}
+
+type harmonicMeanStrategy struct{}
+
+func (harmonicMeanStrategy) Type() StrategyType { return StrategyHarmonicMean }
+
+func (harmonicMeanStrategy) Calculate(scores shared.ScoreArray, weights shared.WeightArray) (ScoreResult, error) {
+	result := ScoreResult{Method: StrategyHarmonicMean}
+	minScore := shared.ScoreValueFromFloat(0.1)
+	weightedHarmonicSum := 0.0
+	totalWeight := shared.Weight(0)
+	for i, n := 0, metadata.Count(); i < n; i++ {
+		rawScore := scores[i]
+		weight := weights[i]
+		if rawScore < minScore {
+			rawScore = minScore
+		}
+		weightedHarmonicSum += weight.ToFloat() / rawScore.ToFloat()
+		result.RawScores[i] = scores[i]
+		result.Weights[i] = weight
+		result.WeightedScores[i] = rawScore.ToFloat()
+		totalWeight += weight
+	}
+	if weightedHarmonicSum > 0 && totalWeight > 0 {
+		result.TotalScore = totalWeight.ToFloat() / weightedHarmonicSum
+	}
+	return result, nil
+}
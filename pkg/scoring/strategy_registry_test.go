@@ -0,0 +1,104 @@
+package scoring
+
+import (
+	"testing"
+
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+)
+
+// unregisterStrategy removes a custom strategy registered by a test.
+func unregisterStrategy(strategyType StrategyType) {
+	strategyMu.Lock()
+	defer strategyMu.Unlock()
+	delete(strategyRegistry, strategyType)
+	delete(StrategyGuidelines, strategyType)
+	for i, registered := range strategyOrder {
+		if registered == strategyType {
+			strategyOrder = append(strategyOrder[:i], strategyOrder[i+1:]...)
+			break
+		}
+	}
+}
+
+// maxStrategy scores an apartment by its best weighted factor.
+type maxStrategy struct{}
+
+func (maxStrategy) Type() StrategyType { return "max_factor" }
+
+func (maxStrategy) Calculate(scores shared.ScoreArray, weights shared.WeightArray) (ScoreResult, error) {
+	result := ScoreResult{}
+	for i, n := 0, metadata.Count(); i < n; i++ {
+		result.RawScores[i] = scores[i]
+		result.Weights[i] = weights[i]
+		if weights[i] > 0 && scores[i].ToFloat() > result.TotalScore {
+			result.TotalScore = scores[i].ToFloat()
+		}
+	}
+	return result, nil
+}
+
+func TestRegisterStrategy(t *testing.T) {
+	custom := maxStrategy{}
+	guide := StrategyGuide{
+		UseCase:     "최고 장점 평가",
+		BestFor:     "한 가지 요소만 뛰어나면 되는 경우",
+		Description: "가장 높은 요소 점수로 평가하는 전략입니다.",
+	}
+	if err := RegisterStrategy(custom, guide); err != nil {
+		t.Fatalf("RegisterStrategy failed: %v", err)
+	}
+	t.Cleanup(func() { unregisterStrategy(custom.Type()) })
+
+	if err := RegisterStrategy(custom, guide); err == nil {
+		t.Error("Duplicate registration should fail")
+	}
+	if err := RegisterStrategy(weightedSumStrategy{}, guide); err == nil {
+		t.Error("Registering a built-in strategy type should fail")
+	}
+
+	available := GetAvailableStrategies()
+	if available[len(available)-1] != custom.Type() {
+		t.Errorf("Custom strategy should be listed last, got %v", available)
+	}
+	if GetStrategyDescription(custom.Type()) != guide.Description {
+		t.Errorf("Unexpected description: %s", GetStrategyDescription(custom.Type()))
+	}
+	if got, ok := GetStrategyGuide(custom.Type()); !ok || got.Description != guide.Description {
+		t.Errorf("Expected registered guide, got %+v", got)
+	}
+	if got, ok := StrategyGuidelines[custom.Type()]; !ok || got.UseCase != guide.UseCase {
+		t.Errorf("Registered guide should be listed in StrategyGuidelines, got %+v", got)
+	}
+
+	result, err := CalculateWithStrategy(getTestScores(), getTestWeights(), custom.Type())
+	if err != nil {
+		t.Fatalf("CalculateWithStrategy failed: %v", err)
+	}
+	if result.TotalScore != 100 || result.Method != custom.Type() {
+		t.Errorf("Expected 100 with method %s, got %.1f with %s", custom.Type(), result.TotalScore, result.Method)
+	}
+
+	apartments := []ApartmentData{
+		{ID: "a", Name: "A", Scores: getTestScores()},
+		{ID: "b", Name: "B", Scores: getTestScores()},
+	}
+	summary, err := CalculateRankings(apartments, getTestWeights(), custom.Type())
+	if err != nil {
+		t.Fatalf("CalculateRankings failed: %v", err)
+	}
+	if summary.TopRanked[0].Method != custom.Type() {
+		t.Errorf("Rankings should use custom strategy, got %s", summary.TopRanked[0].Method)
+	}
+
+	dashboard := GenerateTransparencyDashboard(result, getTestScores(), getTestWeights(), StrategyWeightedSum)
+	if _, exists := dashboard.ScoreBreakdown.StrategyImpact.AlternativeResults[custom.Type()]; !exists {
+		t.Error("Dashboard strategy comparison should include custom strategy")
+	}
+}
+
+func TestCalculateWithStrategy_UnknownStrategy(t *testing.T) {
+	if _, err := CalculateWithStrategy(getTestScores(), getTestWeights(), "unknown"); err == nil {
+		t.Error("Unknown strategy should fail")
+	}
+}
@@ -0,0 +1,95 @@
+package scoring
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"errors"
+	"math"
+)
+
+// CohortStrategy is a Strategy whose scores depend on the whole set of apartments being ranked.
+// CalculateRankings passes every apartment to CalculateCohort at once.
+type CohortStrategy interface {
+	Strategy
+	CalculateCohort(scores []shared.ScoreArray, weights shared.WeightArray) ([]ScoreResult, error)
+}
+
+// topsisStrategy ranks apartments by their closeness to the ideal and anti-ideal solutions of the cohort.
+type topsisStrategy struct{}
+
+func (topsisStrategy) Type() StrategyType { return StrategyTOPSIS }
+
+// Calculate scores a single apartment against the absolute ideal (100) and anti-ideal (0) on every factor.
+func (topsisStrategy) Calculate(scores shared.ScoreArray, weights shared.WeightArray) (ScoreResult, error) {
+	result := ScoreResult{Method: StrategyTOPSIS}
+	positive, negative := 0.0, 0.0
+	for i, n := 0, metadata.Count(); i < n; i++ {
+		result.RawScores[i] = scores[i]
+		result.Weights[i] = weights[i]
+		w := weights[i].ToFloat()
+		v := w * scores[i].ToFloat() / 100
+		result.WeightedScores[i] = v
+		positive += (w - v) * (w - v)
+		negative += v * v
+	}
+	result.Closeness = closeness(math.Sqrt(positive), math.Sqrt(negative))
+	result.TotalScore = result.Closeness * 100
+	return result, nil
+}
+
+// CalculateCohort applies vector normalization across the cohort, then measures each apartment's
+// distance to the best and worst weighted value of every factor.
+func (topsisStrategy) CalculateCohort(scores []shared.ScoreArray, weights shared.WeightArray) ([]ScoreResult, error) {
+	if len(scores) == 0 {
+		return nil, errors.New(errNoApartments)
+	}
+	n := metadata.Count()
+
+	// 요소별 벡터 정규화 후 가중치 적용
+	var norms [metadata.MaxMetadataTypes]float64
+	for _, apt := range scores {
+		for j := 0; j < n; j++ {
+			norms[j] += apt[j].ToFloat() * apt[j].ToFloat()
+		}
+	}
+	weighted := make([][metadata.MaxMetadataTypes]float64, len(scores))
+	var ideal, antiIdeal [metadata.MaxMetadataTypes]float64
+	for i, apt := range scores {
+		for j := 0; j < n; j++ {
+			if norms[j] > 0 {
+				weighted[i][j] = weights[j].ToFloat() * apt[j].ToFloat() / math.Sqrt(norms[j])
+			}
+			if i == 0 || weighted[i][j] > ideal[j] {
+				ideal[j] = weighted[i][j]
+			}
+			if i == 0 || weighted[i][j] < antiIdeal[j] {
+				antiIdeal[j] = weighted[i][j]
+			}
+		}
+	}
+
+	results := make([]ScoreResult, len(scores))
+	for i, apt := range scores {
+		result := ScoreResult{Method: StrategyTOPSIS}
+		positive, negative := 0.0, 0.0
+		for j := 0; j < n; j++ {
+			result.RawScores[j] = apt[j]
+			result.Weights[j] = weights[j]
+			result.WeightedScores[j] = weighted[i][j]
+			positive += (weighted[i][j] - ideal[j]) * (weighted[i][j] - ideal[j])
+			negative += (weighted[i][j] - antiIdeal[j]) * (weighted[i][j] - antiIdeal[j])
+		}
+		result.Closeness = closeness(math.Sqrt(positive), math.Sqrt(negative))
+		result.TotalScore = result.Closeness * 100
+		results[i] = result
+	}
+	return results, nil
+}
+
+// closeness returns D-/(D+ + D-), or 0.5 when the apartment is both ideal and anti-ideal.
+func closeness(positive, negative float64) float64 {
+	if positive+negative == 0 {
+		return 0.5
+	}
+	return negative / (positive + negative)
+}
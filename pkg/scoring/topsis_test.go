@@ -0,0 +1,76 @@
+package scoring
+
+import (
+	"testing"
+
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+)
+
+func uniformScores(score float64) map[metadata.MetadataType]shared.ScoreValue {
+	scores := make(map[metadata.MetadataType]shared.ScoreValue)
+	for _, mt := range metadata.All() {
+		scores[mt] = shared.ScoreValueFromFloat(score)
+	}
+	return scores
+}
+
+func TestTOPSISRankings(t *testing.T) {
+	best := uniformScores(90)
+	worst := uniformScores(50)
+	mixed := uniformScores(70)
+	mixed[metadata.DistanceToStation] = shared.ScoreValueFromFloat(85)
+
+	apartments := []ApartmentData{
+		{ID: "mixed", Name: "혼합", Scores: mixed},
+		{ID: "worst", Name: "최저", Scores: worst},
+		{ID: "best", Name: "최고", Scores: best},
+	}
+
+	summary, err := CalculateRankings(apartments, getTestWeights(), StrategyTOPSIS)
+	if err != nil {
+		t.Fatalf("CalculateRankings failed: %v", err)
+	}
+
+	order := []string{"best", "mixed", "worst"}
+	for i, id := range order {
+		if summary.TopRanked[i].Apartment.ID != id {
+			t.Errorf("Rank %d: expected %s, got %s", i+1, id, summary.TopRanked[i].Apartment.ID)
+		}
+	}
+
+	// 모든 요소에서 최고인 아파트는 이상해, 최저인 아파트는 최악해와 일치
+	if got := summary.TopRanked[0].Closeness; got == nil || *got != 1 {
+		t.Errorf("Ideal apartment closeness should be 1, got %v", got)
+	}
+	if got := summary.TopRanked[2].Closeness; got == nil || *got != 0 {
+		t.Errorf("Anti-ideal apartment closeness should be 0, got %v", got)
+	}
+	for _, ranking := range summary.TopRanked {
+		if ranking.Method != StrategyTOPSIS {
+			t.Errorf("Expected method %s, got %s", StrategyTOPSIS, ranking.Method)
+		}
+		if ranking.Closeness == nil || ranking.Score != *ranking.Closeness*100 {
+			t.Errorf("Score should equal closeness × 100, got %v vs %v", ranking.Score, ranking.Closeness)
+		}
+	}
+
+	weighted, err := CalculateRankings(apartments, getTestWeights(), StrategyWeightedSum)
+	if err != nil {
+		t.Fatalf("CalculateRankings failed: %v", err)
+	}
+	if weighted.TopRanked[0].Closeness != nil {
+		t.Errorf("Non-cohort strategies should not report a closeness, got %v", *weighted.TopRanked[0].Closeness)
+	}
+}
+
+func TestTOPSISSingleApartment(t *testing.T) {
+	result, err := CalculateWithStrategy(uniformScores(80), getTestWeights(), StrategyTOPSIS)
+	if err != nil {
+		t.Fatalf("CalculateWithStrategy failed: %v", err)
+	}
+	// 절대 이상해(100)와 최악해(0) 기준이면 균일 점수는 그 비율과 같다
+	if result.TotalScore < 79.9 || result.TotalScore > 80.1 {
+		t.Errorf("Uniform 80 should score about 80, got %.2f", result.TotalScore)
+	}
+}
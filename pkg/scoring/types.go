@@ -8,11 +8,13 @@ import (
 
 type ScoreResult struct {
 	TotalScore     float64
-	WeightedScores [14]float64
-	RawScores      [14]shared.ScoreValue
-	Weights        [14]shared.Weight
+	WeightedScores [metadata.MaxMetadataTypes]float64
+	RawScores      shared.ScoreArray
+	Weights        shared.WeightArray
 	Method         StrategyType
 	Scenario       ScoringScenario
+	Closeness      float64                 // 이상해 근접도 (TOPSIS 등 코호트 전략, 0-1)
+	Imputed        []metadata.MetadataType // 코호트 중앙값으로 대체된 요소
 }
 
 type ScoringScenario string
@@ -26,12 +28,17 @@ const (
 	ScenarioInvestment     ScoringScenario = "investment"
 )
 
+// ScoringProfile is a named, reusable scoring setup. Weights, when set, are used as is;
+// otherwise the weights of Scenario apply. Constraints are applied when ranking, and
+// ValueFunctions convert raw factor scores to utilities before aggregation.
 type ScoringProfile struct {
-	Name        string
-	Description string
-	Method      StrategyType
-	Weights     map[metadata.MetadataType]shared.Weight
-	Scenario    ScoringScenario
+	Name           string
+	Description    string
+	Method         StrategyType                            // 비어 있으면 StrategyWeightedSum
+	Weights        map[metadata.MetadataType]shared.Weight // 비어 있으면 Scenario 가중치 사용
+	Scenario       ScoringScenario                         // 기준 시나리오 (비어 있으면 ScenarioBalanced)
+	Constraints    Constraints
+	ValueFunctions ValueFunctions // 요소별 효용 곡선 (없는 요소는 선형)
 }
 
 // CalculationStep represents a step in the calculation pipeline
@@ -41,6 +48,9 @@ type CalculationStep struct {
 	Priority    int                    // 실행 우선순위 (낮을수록 먼저 실행)
 	Condition   func(ScoreResult) bool // 실행 조건
 	Calculator  func(map[metadata.MetadataType]shared.ScoreValue, map[metadata.MetadataType]shared.Weight) float64
+	// Apply, when set, replaces Calculator: it receives the result so far, whose TotalScore is
+	// the running total, and returns the new running total.
+	Apply func(ScoreResult) (float64, error)
 }
 
 // CalculationPipeline represents a customizable calculation pipeline
@@ -103,6 +113,8 @@ type TransparencyDashboard struct {
 	// 사용자 가이드 섹션
 	InterpretationGuide InterpretationGuide // 결과 해석 가이드
 	RecommendedActions  []RecommendedAction // 권장 조치사항
+	Counterfactual      *Counterfactual     // 목표 달성에 필요한 최소 변경 (DashboardOptions.Goal이 있을 때)
+	PipelineTrace       *PipelineTrace      // 파이프라인 단계별 실행 기록 (DashboardOptions.Pipeline이 있을 때)
 }
 
 // ScoreBreakdown provides detailed breakdown of how the score was calculated.
@@ -110,13 +122,14 @@ type ScoreBreakdown struct {
 	TotalScore          float64                   // 최종 점수
 	ComponentScores     map[string]ComponentScore // 각 구성 요소의 점수
 	WeightContributions map[string]float64        // 각 요소의 가중치 기여도
+	GroupContributions  []GroupContribution       // 요소 그룹별 기여도 (DashboardOptions.Groups 기준)
 	StrategyImpact      StrategyImpact            // 전략 선택이 결과에 미친 영향
 }
 
 // ComponentScore represents the score contribution of a single component.
 type ComponentScore struct {
-	RawValue        float64 // 원본 값
-	NormalizedValue float64 // 정규화된 값 (0-100)
+	RawValue        float64 // 원점수 (0-100)
+	NormalizedValue float64 // 가치 함수를 적용한 효용 점수 (0-100, 가치 함수가 없으면 원점수)
 	Weight          float64 // 적용된 가중치
 	Contribution    float64 // 최종 점수에 대한 기여도
 	ImpactLevel     string  // 영향도 레벨 (High/Medium/Low)
@@ -136,6 +149,8 @@ type ScoreDistribution struct {
 	ScoreRange         ScoreRange         // 점수 범위
 	ComparativeContext string             // 비교 맥락 설명
 	ConfidenceInterval ConfidenceInterval // 신뢰 구간
+	PercentileInterval ConfidenceInterval // 백분위수의 부트스트랩 신뢰 구간 (비교 대상이 있을 때)
+	CohortSize         int                // 비교 대상 아파트 수 (0이면 추정값)
 }
 
 // ScoreRange represents the possible range of scores.
@@ -215,8 +230,8 @@ type AlternativeScenario struct {
 // SensitivityAnalysis shows how sensitive the score is to changes in inputs.
 type SensitivityAnalysis struct {
 	MostSensitiveFactors []SensitivityFactor // 가장 민감한 요소들
-	StabilityIndex       float64             // 안정성 지수 (0-100%)
-	VariationRange       ScoreRange          // 변동 범위
+	StabilityIndex       float64             // 안정성 지수 (0-100%, ±5%p 가중치 변경 시 최대 변동폭 기준)
+	VariationRange       ScoreRange          // 가중치 변경 시 점수 변동 범위
 	RobustnessLevel      string              // 견고성 레벨
 }
 
@@ -224,8 +239,9 @@ type SensitivityAnalysis struct {
 type SensitivityFactor struct {
 	FactorName      string  // 요소 이름
 	CurrentValue    float64 // 현재 값
-	Sensitivity     float64 // 민감도 (%)
-	ImpactDirection string  // 영향 방향 (Positive/Negative)
+	CurrentWeight   float64 // 현재 가중치 (0-1)
+	Sensitivity     float64 // 민감도 (가중치 1.0 변화당 점수 변화, 다른 가중치는 비례 재조정)
+	ImpactDirection string  // 영향 방향 (Positive: 가중치를 높이면 점수 상승, Negative: 하락)
 }
 
 // DataQualityMetrics provides metrics on the quality of input data.
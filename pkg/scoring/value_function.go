@@ -0,0 +1,158 @@
+package scoring
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/normalization"
+	"apart_score/pkg/shared"
+	"fmt"
+	"math"
+)
+
+// ValueFunctionKind identifies the shape of a per-factor utility curve.
+type ValueFunctionKind string
+
+const (
+	ValuePiecewiseLinear ValueFunctionKind = "piecewise_linear" // 제어점 사이 선형 보간
+	ValueSigmoid         ValueFunctionKind = "sigmoid"          // 중간점 부근에서 급격히 변하는 S자 곡선
+	ValueThreshold       ValueFunctionKind = "threshold"        // 기준 점수 이상이면 보너스 가산
+	ValueDiminishing     ValueFunctionKind = "diminishing"      // 점수가 높을수록 증가폭이 줄어드는 곡선
+)
+
+// ValueFunctionKinds lists the supported value function kinds.
+var ValueFunctionKinds = []ValueFunctionKind{ValuePiecewiseLinear, ValueSigmoid, ValueThreshold, ValueDiminishing}
+
+// ValueFunction maps a factor's raw score (0-100) to the utility (0-100) the strategies
+// aggregate. Only the parameters of its Kind are used.
+type ValueFunction struct {
+	Kind ValueFunctionKind `json:"kind"`
+	// Points are the control points of a piecewise-linear curve: Input is the raw score and
+	// Score the utility. Scores outside the first and last point are clamped to the end utilities.
+	Points    normalization.Curve `json:"points,omitempty"`
+	Midpoint  float64             `json:"midpoint,omitempty"`  // sigmoid: 효용이 절반이 되는 원점수
+	Steepness float64             `json:"steepness,omitempty"` // sigmoid: 중간점에서의 기울기 (> 0)
+	Threshold float64             `json:"threshold,omitempty"` // threshold: 보너스가 적용되는 최소 원점수
+	Bonus     float64             `json:"bonus,omitempty"`     // threshold: 가산 점수 (0-100, 100에서 절단)
+	Rate      float64             `json:"rate,omitempty"`      // diminishing: 체감 정도 (> 0, 클수록 빨리 포화)
+}
+
+// Validate checks the kind and its parameters.
+func (f ValueFunction) Validate() error {
+	inRange := func(name string, v float64) error {
+		if v < 0 || v > 100 || math.IsNaN(v) {
+			return fmt.Errorf("%s는 0-100 범위여야 합니다 (%g)", name, v)
+		}
+		return nil
+	}
+	switch f.Kind {
+	case ValuePiecewiseLinear:
+		if err := f.Points.Validate(); err != nil {
+			return err
+		}
+		for _, p := range f.Points {
+			if err := inRange("제어점 입력값", p.Input); err != nil {
+				return err
+			}
+		}
+	case ValueSigmoid:
+		if err := inRange("midpoint", f.Midpoint); err != nil {
+			return err
+		}
+		if !(f.Steepness > 0) || math.IsInf(f.Steepness, 0) {
+			return fmt.Errorf("steepness는 0보다 큰 유한한 값이어야 합니다 (%g)", f.Steepness)
+		}
+	case ValueThreshold:
+		if err := inRange("threshold", f.Threshold); err != nil {
+			return err
+		}
+		if err := inRange("bonus", f.Bonus); err != nil {
+			return err
+		}
+	case ValueDiminishing:
+		if !(f.Rate > 0) || math.IsInf(f.Rate, 0) {
+			return fmt.Errorf("rate는 0보다 큰 유한한 값이어야 합니다 (%g)", f.Rate)
+		}
+	default:
+		return fmt.Errorf("알 수 없는 가치 함수: %q (사용 가능: %v)", f.Kind, ValueFunctionKinds)
+	}
+	return nil
+}
+
+// Evaluate returns the utility of a raw score in points, clamped to 0-100.
+func (f ValueFunction) Evaluate(x float64) float64 {
+	var u float64
+	switch f.Kind {
+	case ValuePiecewiseLinear:
+		u = f.Points.Evaluate(x)
+	case ValueSigmoid:
+		// 로지스틱 곡선을 0점 → 0, 100점 → 100이 되도록 재조정
+		logistic := func(v float64) float64 { return 1 / (1 + math.Exp(-f.Steepness*(v-f.Midpoint))) }
+		lo, hi := logistic(0), logistic(100)
+		if hi <= lo {
+			return x
+		}
+		u = (logistic(x) - lo) / (hi - lo) * 100
+	case ValueThreshold:
+		u = x
+		if x >= f.Threshold {
+			u += f.Bonus
+		}
+	case ValueDiminishing:
+		u = (1 - math.Exp(-f.Rate*x/100)) / (1 - math.Exp(-f.Rate)) * 100
+	default:
+		u = x
+	}
+	return math.Max(0, math.Min(100, u))
+}
+
+// Apply returns the utility of a raw score. Missing scores stay missing.
+func (f ValueFunction) Apply(score shared.ScoreValue) shared.ScoreValue {
+	if score.IsMissing() {
+		return score
+	}
+	return shared.ScoreValueFromFloat(f.Evaluate(score.ToFloat()))
+}
+
+// ValueFunctions assigns a utility curve to factors. Factors without one are scored linearly.
+type ValueFunctions map[metadata.MetadataType]ValueFunction
+
+// Validate checks that every factor is registered and every function is valid.
+func (v ValueFunctions) Validate() error {
+	for mt, f := range v {
+		if !mt.IsValid() {
+			return &ValidationError{Field: "value_functions", Message: fmt.Sprintf(errUnknownMetadata, int(mt))}
+		}
+		if err := f.Validate(); err != nil {
+			return &ValidationError{Field: mt.String(), Message: err.Error()}
+		}
+	}
+	return nil
+}
+
+// Apply returns the utilities of the raw scores. The input map is not modified; when there are
+// no value functions it is returned as is.
+func (v ValueFunctions) Apply(scores map[metadata.MetadataType]shared.ScoreValue) map[metadata.MetadataType]shared.ScoreValue {
+	if len(v) == 0 {
+		return scores
+	}
+	utilities := make(map[metadata.MetadataType]shared.ScoreValue, len(scores))
+	for mt, score := range scores {
+		if f, exists := v[mt]; exists {
+			score = f.Apply(score)
+		}
+		utilities[mt] = score
+	}
+	return utilities
+}
+
+// applyAll returns copies of the apartments with utilities in place of raw scores.
+func (v ValueFunctions) applyAll(apartments []ApartmentData) []ApartmentData {
+	if len(v) == 0 {
+		return apartments
+	}
+	transformed := make([]ApartmentData, len(apartments))
+	for i, apt := range apartments {
+		apt.Scores = v.Apply(apt.Scores)
+		transformed[i] = apt
+	}
+	return transformed
+}
@@ -0,0 +1,166 @@
+package scoring
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/normalization"
+	"apart_score/pkg/shared"
+)
+
+func TestValueFunctionEvaluate(t *testing.T) {
+	tests := []struct {
+		name string
+		f    ValueFunction
+		in   float64
+		want float64
+	}{
+		{"piecewise between points", ValueFunction{Kind: ValuePiecewiseLinear,
+			Points: normalization.Curve{{Input: 0, Score: 20}, {Input: 50, Score: 80}, {Input: 100, Score: 100}}}, 25, 50},
+		{"piecewise clamped", ValueFunction{Kind: ValuePiecewiseLinear,
+			Points: normalization.Curve{{Input: 40, Score: 0}, {Input: 60, Score: 100}}}, 90, 100},
+		{"sigmoid midpoint", ValueFunction{Kind: ValueSigmoid, Midpoint: 50, Steepness: 0.2}, 50, 50},
+		{"sigmoid bottom", ValueFunction{Kind: ValueSigmoid, Midpoint: 70, Steepness: 0.1}, 0, 0},
+		{"sigmoid top", ValueFunction{Kind: ValueSigmoid, Midpoint: 70, Steepness: 0.1}, 100, 100},
+		{"threshold below", ValueFunction{Kind: ValueThreshold, Threshold: 80, Bonus: 10}, 79, 79},
+		{"threshold at", ValueFunction{Kind: ValueThreshold, Threshold: 80, Bonus: 10}, 80, 90},
+		{"threshold capped", ValueFunction{Kind: ValueThreshold, Threshold: 80, Bonus: 10}, 95, 100},
+		{"diminishing top", ValueFunction{Kind: ValueDiminishing, Rate: 3}, 100, 100},
+		{"diminishing mid", ValueFunction{Kind: ValueDiminishing, Rate: 3}, 50, 100 * (1 - math.Exp(-1.5)) / (1 - math.Exp(-3))},
+	}
+	for _, tt := range tests {
+		if err := tt.f.Validate(); err != nil {
+			t.Errorf("%s: Validate() = %v", tt.name, err)
+		}
+		if got := tt.f.Evaluate(tt.in); math.Abs(got-tt.want) > 1e-6 {
+			t.Errorf("%s: Evaluate(%g) = %.4f, want %.4f", tt.name, tt.in, got, tt.want)
+		}
+	}
+
+	// 체감 곡선은 단조 증가하면서 증가폭이 줄어듦
+	f := ValueFunction{Kind: ValueDiminishing, Rate: 3}
+	if low, high := f.Evaluate(20)-f.Evaluate(10), f.Evaluate(90)-f.Evaluate(80); !(low > high && high > 0) {
+		t.Errorf("diminishing increments: %.2f then %.2f", low, high)
+	}
+}
+
+func TestValueFunctionValidate(t *testing.T) {
+	tests := map[string]ValueFunction{
+		"unknown kind":         {Kind: "cubic"},
+		"no points":            {Kind: ValuePiecewiseLinear},
+		"descending points":    {Kind: ValuePiecewiseLinear, Points: normalization.Curve{{Input: 50, Score: 0}, {Input: 10, Score: 100}}},
+		"point out of range":   {Kind: ValuePiecewiseLinear, Points: normalization.Curve{{Input: 0, Score: 0}, {Input: 150, Score: 100}}},
+		"flat sigmoid":         {Kind: ValueSigmoid, Midpoint: 50},
+		"threshold too high":   {Kind: ValueThreshold, Threshold: 120, Bonus: 5},
+		"negative bonus":       {Kind: ValueThreshold, Threshold: 50, Bonus: -5},
+		"non-diminishing":      {Kind: ValueDiminishing},
+		"infinite steepness":   {Kind: ValueSigmoid, Midpoint: 50, Steepness: math.Inf(1)},
+		"negative rate":        {Kind: ValueDiminishing, Rate: -1},
+		"sigmoid bad midpoint": {Kind: ValueSigmoid, Midpoint: -10, Steepness: 0.1},
+	}
+	for name, f := range tests {
+		if err := f.Validate(); err == nil {
+			t.Errorf("%s: Validate() succeeded", name)
+		}
+	}
+
+	var validation *ValidationError
+	functions := ValueFunctions{metadata.CrimeRate: {Kind: ValueDiminishing}}
+	if err := functions.Validate(); !errors.As(err, &validation) || validation.Field != metadata.CrimeRate.String() {
+		t.Errorf("ValueFunctions.Validate() = %v, want ValidationError on %s", err, metadata.CrimeRate)
+	}
+}
+
+func TestProfileValueFunctions(t *testing.T) {
+	scores := uniformScores(70)
+	scores[metadata.SchoolDistrict] = shared.ScoreValueFromFloat(85)
+	scores[metadata.Parking] = shared.MissingScore
+	p := ScoringProfile{
+		Scenario:       ScenarioEducation,
+		ValueFunctions: ValueFunctions{metadata.SchoolDistrict: {Kind: ValueThreshold, Threshold: 80, Bonus: 15}},
+	}
+	utilities := p.ValueFunctions.Apply(scores)
+	if utilities[metadata.SchoolDistrict] != shared.ScoreValueFromFloat(100) || !utilities[metadata.Parking].IsMissing() {
+		t.Errorf("utilities: school %v, parking %v", utilities[metadata.SchoolDistrict], utilities[metadata.Parking])
+	}
+	if scores[metadata.SchoolDistrict] != shared.ScoreValueFromFloat(85) {
+		t.Error("Apply modified the raw scores")
+	}
+
+	linear, err := CalculateWithProfile(scores, ScoringProfile{Scenario: ScenarioEducation})
+	if err != nil {
+		t.Fatal(err)
+	}
+	curved, err := CalculateWithProfile(scores, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	schoolWeight := GetScenarioWeights(ScenarioEducation)[metadata.SchoolDistrict].ToFloat()
+	if curved.TotalScore <= linear.TotalScore {
+		t.Errorf("threshold bonus did not raise the score: %.2f <= %.2f", curved.TotalScore, linear.TotalScore)
+	}
+
+	dashboard, err := GenerateTransparencyDashboardWithOptions(curved, scores, p.EffectiveWeights(), p.Strategy(),
+		DashboardOptions{ValueFunctions: p.ValueFunctions})
+	if err != nil {
+		t.Fatal(err)
+	}
+	school := dashboard.ScoreBreakdown.ComponentScores[metadata.SchoolDistrict.String()]
+	if school.RawValue != 85 || school.NormalizedValue != 100 {
+		t.Errorf("school component raw %.1f utility %.1f, want 85 and 100", school.RawValue, school.NormalizedValue)
+	}
+	if math.Abs(school.Contribution-100*schoolWeight) > 1e-9 {
+		t.Errorf("school contribution %.3f uses the raw score", school.Contribution)
+	}
+	crime := dashboard.ScoreBreakdown.ComponentScores[metadata.CrimeRate.String()]
+	if crime.RawValue != crime.NormalizedValue {
+		t.Errorf("factor without a value function: raw %.1f utility %.1f", crime.RawValue, crime.NormalizedValue)
+	}
+}
+
+func TestRankingValueFunctions(t *testing.T) {
+	// 역세권 선호가 포화되면 교통이 조금 나쁘더라도 학군이 좋은 아파트가 앞섬
+	near := uniformScores(60)
+	near[metadata.DistanceToStation] = shared.ScoreValueFromFloat(100)
+	school := uniformScores(60)
+	school[metadata.DistanceToStation] = shared.ScoreValueFromFloat(70)
+	school[metadata.SchoolDistrict] = shared.ScoreValueFromFloat(75)
+	apartments := []ApartmentData{{ID: "near", Scores: near}, {ID: "school", Scores: school}}
+	saturating := ValueFunctions{metadata.DistanceToStation: {Kind: ValuePiecewiseLinear,
+		Points: normalization.Curve{{Input: 0, Score: 0}, {Input: 70, Score: 100}}}}
+
+	p := ScoringProfile{Scenario: ScenarioTransportation}
+	summary, err := CalculateRankingsWithProfile(apartments, p, RankingOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.TopRanked[0].Apartment.ID != "near" {
+		t.Fatalf("linear ranking starts with %s", summary.TopRanked[0].Apartment.ID)
+	}
+	p.ValueFunctions = saturating
+	summary, err = CalculateRankingsWithProfile(apartments, p, RankingOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(summary.TopRanked) != 2 || summary.TopRanked[0].Apartment.ID != "school" {
+		t.Fatalf("curved ranking: %+v", summary.TopRanked)
+	}
+	if summary.TopRanked[0].Apartment.Scores[metadata.DistanceToStation] != shared.ScoreValueFromFloat(70) {
+		t.Error("ranked apartment does not keep its raw scores")
+	}
+
+	// 최소 점수는 원점수 기준: 효용이 100이어도 원점수 70은 80 미만
+	p.Constraints = Constraints{MinScores: map[metadata.MetadataType]shared.ScoreValue{
+		metadata.DistanceToStation: shared.ScoreValueFromFloat(80)}}
+	summary, err = CalculateRankingsWithProfile(apartments, p, RankingOptions{})
+	if err != nil || len(summary.Eliminated) != 1 || summary.Eliminated[0].Apartment.ID != "school" {
+		t.Fatalf("constraints on raw scores: %+v, %v", summary, err)
+	}
+
+	p.ValueFunctions = ValueFunctions{metadata.DistanceToStation: {Kind: ValueSigmoid}}
+	if _, err := CalculateRankingsWithProfile(apartments, p, RankingOptions{}); err == nil {
+		t.Error("expected an error for a sigmoid without steepness")
+	}
+}
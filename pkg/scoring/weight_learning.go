@@ -0,0 +1,232 @@
+package scoring
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// DefaultPreferenceMargin is the score gap, in points, learned weights aim to put between
+// a preferred apartment and the other one.
+const DefaultPreferenceMargin = 1.0
+
+// preferenceShortfallCost is the cost of one point of preference shortfall relative to moving
+// the weights by 1 in total, so satisfying preferences outweighs staying near the prior.
+const preferenceShortfallCost = 100
+
+// Preference states that apartment Preferred should score higher than apartment Other.
+type Preference struct {
+	Preferred string `json:"preferred"`
+	Other     string `json:"other"`
+}
+
+// PreferencesFromRanking turns an ordering from best to worst into preferences between
+// neighbours. Several rankings together describe a partial order.
+func PreferencesFromRanking(ids ...string) []Preference {
+	preferences := make([]Preference, 0, len(ids))
+	for i := 1; i < len(ids); i++ {
+		preferences = append(preferences, Preference{Preferred: ids[i-1], Other: ids[i]})
+	}
+	return preferences
+}
+
+// PreferenceLearningOptions tunes LearnWeights.
+type PreferenceLearningOptions struct {
+	Margin float64 // 선호를 만족시킬 최소 점수 차이 (0이면 DefaultPreferenceMargin)
+}
+
+// PreferenceCheck reports how a stated preference fares under the prior and learned weights.
+type PreferenceCheck struct {
+	Preference
+	PriorDifference float64 `json:"prior_difference"` // 기준 가중치에서 Preferred - Other (점)
+	Difference      float64 `json:"difference"`       // 학습된 가중치에서 Preferred - Other (점)
+	Satisfied       bool    `json:"satisfied"`        // 학습된 가중치에서 Preferred가 더 높은지 여부
+}
+
+// LearnedWeights holds weights fitted to stated preferences.
+type LearnedWeights struct {
+	Profile      ScoringProfile                          // 기준 프로필에 학습된 가중치를 넣은 프로필
+	Prior        map[metadata.MetadataType]shared.Weight // 기준 가중치
+	Margin       float64                                 // 목표로 한 최소 점수 차이
+	Preferences  []PreferenceCheck                       // 입력 순서
+	Violated     []PreferenceCheck                       // 학습된 가중치로도 만족시킬 수 없는 선호
+	WeightChange float64                                 // 기준 가중치와의 차이 합계 (0-2)
+}
+
+// LearnWeights finds weighted sum weights under which each preferred apartment outscores the
+// other one by at least the margin. Among such weights it picks the ones closest to the
+// profile's weights; when no weights satisfy every preference it minimizes the total
+// shortfall and reports the preferences left unsatisfied in Violated. The profile's value
+// functions apply before weighting, and a factor missing from either apartment of a pair
+// does not count for that preference.
+func LearnWeights(apartments []ApartmentData, preferences []Preference, p ScoringProfile, opts PreferenceLearningOptions) (*LearnedWeights, error) {
+	if err := p.Validate(); err != nil {
+		return nil, fmt.Errorf(errInvalidProfile, p.Name, err)
+	}
+	if p.Strategy() != StrategyWeightedSum {
+		return nil, &ValidationError{Field: "strategy",
+			Message: fmt.Sprintf("가중치 학습은 %s 전략만 지원합니다 (%s)", StrategyWeightedSum, p.Method)}
+	}
+	margin := opts.Margin
+	if margin == 0 {
+		margin = DefaultPreferenceMargin
+	}
+	if !(margin > 0 && margin <= 100) {
+		return nil, &ValidationError{Field: "margin", Message: fmt.Sprintf("최소 점수 차이는 0보다 크고 100 이하여야 합니다 (%g)", opts.Margin)}
+	}
+	if len(preferences) == 0 {
+		return nil, &ValidationError{Field: "preferences", Message: "선호가 하나 이상 필요합니다"}
+	}
+	byID := make(map[string]map[metadata.MetadataType]shared.ScoreValue, len(apartments))
+	for _, apt := range apartments {
+		byID[apt.ID] = apt.Scores
+	}
+	for _, pref := range preferences {
+		for _, id := range []string{pref.Preferred, pref.Other} {
+			if _, exists := byID[id]; !exists {
+				return nil, &ValidationError{Field: "preferences", Message: fmt.Sprintf("알 수 없는 아파트 ID: %s", id)}
+			}
+		}
+		if pref.Preferred == pref.Other {
+			return nil, &ValidationError{Field: "preferences", Message: fmt.Sprintf("같은 아파트끼리는 선호를 정할 수 없습니다: %s", pref.Preferred)}
+		}
+	}
+
+	// 변수: 가중치 w (n개), 기준 가중치와의 차이 u (n개), 선호별 부족분 s (선호 수)
+	prior := p.EffectiveWeights()
+	factors := metadata.All()
+	n := len(factors)
+	cost := make([]float64, 2*n+len(preferences))
+	constraints := make([]linearConstraint, 0, 1+2*n+len(preferences))
+	total := linearConstraint{coef: make([]float64, len(cost)), sense: equalTo, rhs: 1}
+	for k, mt := range factors {
+		cost[n+k] = 1
+		total.coef[k] = 1
+		above := linearConstraint{coef: make([]float64, len(cost)), sense: lessEqual, rhs: prior[mt].ToFloat()}
+		above.coef[k], above.coef[n+k] = 1, -1
+		below := linearConstraint{coef: make([]float64, len(cost)), sense: greaterEqual, rhs: prior[mt].ToFloat()}
+		below.coef[k], below.coef[n+k] = 1, 1
+		constraints = append(constraints, above, below)
+	}
+	constraints = append(constraints, total)
+	for i, pref := range preferences {
+		preferred, other := p.ValueFunctions.Apply(byID[pref.Preferred]), p.ValueFunctions.Apply(byID[pref.Other])
+		row := linearConstraint{coef: make([]float64, len(cost)), sense: greaterEqual, rhs: margin}
+		for k, mt := range factors {
+			if !preferred[mt].IsMissing() && !other[mt].IsMissing() {
+				row.coef[k] = preferred[mt].ToFloat() - other[mt].ToFloat()
+			}
+		}
+		cost[2*n+i] = preferenceShortfallCost
+		row.coef[2*n+i] = 1
+		constraints = append(constraints, row)
+	}
+	solution, err := solveLP(cost, constraints)
+	if err != nil {
+		return nil, fmt.Errorf("가중치 학습 실패: %w", err)
+	}
+
+	learned := p
+	learned.Weights = learnedWeights(factors, solution[:n])
+	result := &LearnedWeights{Profile: learned, Prior: prior, Margin: margin,
+		Preferences: make([]PreferenceCheck, 0, len(preferences)), Violated: []PreferenceCheck{}}
+	for _, mt := range factors {
+		result.WeightChange += math.Abs(learned.Weights[mt].ToFloat() - prior[mt].ToFloat())
+	}
+
+	// 원래 점수 계산 경로(결측값 재정규화 포함)로 선호를 다시 확인
+	totals := map[bool]map[string]float64{false: {}, true: {}}
+	difference := func(pref Preference, useLearned bool) (float64, error) {
+		profile := p
+		if useLearned {
+			profile = learned
+		}
+		scores := [2]float64{}
+		for i, id := range []string{pref.Preferred, pref.Other} {
+			total, cached := totals[useLearned][id]
+			if !cached {
+				scored, err := CalculateWithProfile(byID[id], profile)
+				if err != nil {
+					return 0, fmt.Errorf("아파트 %s: %w", id, err)
+				}
+				total = scored.TotalScore
+				totals[useLearned][id] = total
+			}
+			scores[i] = total
+		}
+		return scores[0] - scores[1], nil
+	}
+	for _, pref := range preferences {
+		check := PreferenceCheck{Preference: pref}
+		if check.PriorDifference, err = difference(pref, false); err != nil {
+			return nil, err
+		}
+		if check.Difference, err = difference(pref, true); err != nil {
+			return nil, err
+		}
+		check.Satisfied = check.Difference > comparisonTolerance
+		result.Preferences = append(result.Preferences, check)
+		if !check.Satisfied {
+			result.Violated = append(result.Violated, check)
+		}
+	}
+	return result, nil
+}
+
+// learnedWeights rounds an LP weight vector to integer weights summing to shared.WeightScale.
+func learnedWeights(factors []metadata.MetadataType, solution []float64) map[metadata.MetadataType]shared.Weight {
+	priorities := make([]float64, len(solution))
+	sum := 0.0
+	for i, w := range solution {
+		priorities[i] = math.Max(w, 0)
+		sum += priorities[i]
+	}
+	for i := range priorities {
+		priorities[i] /= sum
+	}
+	return priorityWeights(factors, priorities)
+}
+
+// FormatLearnedWeights formats learned weights and the preferences they satisfy for display.
+func FormatLearnedWeights(l *LearnedWeights) string {
+	satisfied := len(l.Preferences) - len(l.Violated)
+	output := "🎓 선호로부터 학습한 가중치\n"
+	output += "━━━━━━━━━━━━━━━━━━━━━━━━━\n"
+	output += fmt.Sprintf("선호 %d개 중 %d개 만족 (목표 점수 차이 %.1f점, 가중치 변경 %.1f%%)\n",
+		len(l.Preferences), satisfied, l.Margin, l.WeightChange*50)
+
+	factors := make([]metadata.MetadataType, 0)
+	for _, mt := range metadata.All() {
+		if l.Prior[mt] != 0 || l.Profile.Weights[mt] != 0 {
+			factors = append(factors, mt)
+		}
+	}
+	sort.SliceStable(factors, func(i, j int) bool { return l.Profile.Weights[factors[i]] > l.Profile.Weights[factors[j]] })
+	output += "\n📊 가중치 (기준 → 학습):\n"
+	for _, mt := range factors {
+		before, after := l.Prior[mt].ToFloat()*100, l.Profile.Weights[mt].ToFloat()*100
+		output += fmt.Sprintf("  %-20s: %5.1f%% → %5.1f%%", mt.KoreanName(), before, after)
+		if math.Abs(after-before) >= 0.05 {
+			output += fmt.Sprintf(" (%+.1f%%p)", after-before)
+		}
+		output += "\n"
+	}
+
+	output += "\n✅ 선호 확인 (기준 → 학습 점수 차이):\n"
+	for _, c := range l.Preferences {
+		mark := "✅"
+		if !c.Satisfied {
+			mark = "❌"
+		}
+		output += fmt.Sprintf("  %s %s > %s: %+.2f점 → %+.2f점\n", mark, c.Preferred, c.Other, c.PriorDifference, c.Difference)
+	}
+	if len(l.Violated) > 0 {
+		output += "\n⚠️ 가중합 가중치로는 만족시킬 수 없는 선호입니다. 선호끼리 모순되거나 점수 데이터로 구분되지 않습니다:\n"
+		for _, c := range l.Violated {
+			output += fmt.Sprintf("  • %s > %s\n", c.Preferred, c.Other)
+		}
+	}
+	return output
+}
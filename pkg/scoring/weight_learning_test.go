@@ -0,0 +1,133 @@
+package scoring
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+)
+
+func TestSolveLP(t *testing.T) {
+	// x + y 최소화, x + y >= 2, x - y = 1, -x >= -3
+	x, err := solveLP([]float64{1, 1}, []linearConstraint{
+		{coef: []float64{1, 1}, sense: greaterEqual, rhs: 2},
+		{coef: []float64{1, -1}, sense: equalTo, rhs: 1},
+		{coef: []float64{-1, 0}, sense: greaterEqual, rhs: -3},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(x[0]-1.5) > 1e-9 || math.Abs(x[1]-0.5) > 1e-9 {
+		t.Errorf("solution = %v, want [1.5 0.5]", x)
+	}
+
+	if _, err := solveLP([]float64{1}, []linearConstraint{
+		{coef: []float64{1}, sense: lessEqual, rhs: 1},
+		{coef: []float64{1}, sense: greaterEqual, rhs: 2},
+	}); !errors.Is(err, errInfeasibleLP) {
+		t.Errorf("infeasible: %v", err)
+	}
+	if _, err := solveLP([]float64{-1}, []linearConstraint{{coef: []float64{1}, sense: greaterEqual, rhs: 1}}); !errors.Is(err, errUnboundedLP) {
+		t.Errorf("unbounded: %v", err)
+	}
+}
+
+func TestLearnWeights(t *testing.T) {
+	station := ApartmentData{ID: "station", Scores: uniformScores(70)}
+	station.Scores[metadata.DistanceToStation] = shared.ScoreValueFromFloat(95)
+	school := ApartmentData{ID: "school", Scores: uniformScores(70)}
+	school.Scores[metadata.SchoolDistrict] = shared.ScoreValueFromFloat(95)
+	quiet := ApartmentData{ID: "quiet", Scores: uniformScores(60)}
+	quiet.Scores[metadata.CrimeRate] = shared.MissingScore
+	apartments := []ApartmentData{station, school, quiet}
+	p := ScoringProfile{Scenario: ScenarioTransportation}
+
+	learned, err := LearnWeights(apartments, PreferencesFromRanking("school", "station", "quiet"), p, PreferenceLearningOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(learned.Violated) != 0 || len(learned.Preferences) != 2 {
+		t.Fatalf("preferences: %+v", learned.Preferences)
+	}
+	first := learned.Preferences[0]
+	if first.PriorDifference >= 0 || first.Difference < DefaultPreferenceMargin-0.1 {
+		t.Errorf("school > station: prior %.2f, learned %.2f", first.PriorDifference, first.Difference)
+	}
+	if err := learned.Profile.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	weights := learned.Profile.Weights
+	if weights[metadata.SchoolDistrict] <= learned.Prior[metadata.SchoolDistrict] || learned.WeightChange <= 0 || learned.WeightChange > 1 {
+		t.Errorf("school weight %d (prior %d), change %.3f", weights[metadata.SchoolDistrict], learned.Prior[metadata.SchoolDistrict], learned.WeightChange)
+	}
+	summary, err := CalculateRankingsWithProfile(apartments, learned.Profile, RankingOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, id := range []string{"school", "station", "quiet"} {
+		if summary.TopRanked[i].Apartment.ID != id {
+			t.Errorf("rank %d = %s, want %s", i+1, summary.TopRanked[i].Apartment.ID, id)
+		}
+	}
+
+	// 이미 만족하는 선호는 기준 가중치를 바꾸지 않음
+	learned, err = LearnWeights(apartments, []Preference{{Preferred: "station", Other: "quiet"}}, p, PreferenceLearningOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if learned.WeightChange != 0 {
+		t.Errorf("satisfied preference changed the weights by %.3f", learned.WeightChange)
+	}
+}
+
+func TestLearnWeightsViolated(t *testing.T) {
+	a := ApartmentData{ID: "a", Scores: uniformScores(70)}
+	twin := ApartmentData{ID: "twin", Scores: uniformScores(70)}
+	b := ApartmentData{ID: "b", Scores: uniformScores(60)}
+	b.Scores[metadata.Parking] = shared.ScoreValueFromFloat(90)
+
+	preferences := []Preference{{Preferred: "b", Other: "a"}, {Preferred: "twin", Other: "a"}}
+	learned, err := LearnWeights([]ApartmentData{a, twin, b}, preferences, ScoringProfile{}, PreferenceLearningOptions{Margin: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(learned.Violated) != 1 || learned.Violated[0].Preferred != "twin" {
+		t.Fatalf("violated: %+v", learned.Violated)
+	}
+	if !learned.Preferences[0].Satisfied || learned.Preferences[0].Difference < 1.9 {
+		t.Errorf("b > a: %+v", learned.Preferences[0])
+	}
+	text := FormatLearnedWeights(learned)
+	for _, want := range []string{"선호 2개 중 1개 만족", "twin > a", metadata.Parking.KoreanName()} {
+		if !strings.Contains(text, want) {
+			t.Errorf("formatted result lacks %q:\n%s", want, text)
+		}
+	}
+}
+
+func TestLearnWeightsErrors(t *testing.T) {
+	apartments := []ApartmentData{{ID: "a", Scores: uniformScores(70)}, {ID: "b", Scores: uniformScores(60)}}
+	valid := []Preference{{Preferred: "a", Other: "b"}}
+	tests := map[string]struct {
+		preferences []Preference
+		profile     ScoringProfile
+		margin      float64
+		field       string
+	}{
+		"no preferences":    {nil, ScoringProfile{}, 0, "preferences"},
+		"unknown apartment": {[]Preference{{Preferred: "a", Other: "c"}}, ScoringProfile{}, 0, "preferences"},
+		"same apartment":    {[]Preference{{Preferred: "a", Other: "a"}}, ScoringProfile{}, 0, "preferences"},
+		"negative margin":   {valid, ScoringProfile{}, -1, "margin"},
+		"non-linear":        {valid, ScoringProfile{Method: StrategyTOPSIS}, 0, "strategy"},
+	}
+	for name, tt := range tests {
+		var validation *ValidationError
+		_, err := LearnWeights(apartments, tt.preferences, tt.profile, PreferenceLearningOptions{Margin: tt.margin})
+		if !errors.As(err, &validation) || validation.Field != tt.field {
+			t.Errorf("%s: %v, want ValidationError on %s", name, err, tt.field)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package scoring
+
+import (
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// FactorWeight is a factor leaf of a weight tree.
+type FactorWeight struct {
+	Factor metadata.MetadataType `json:"factor"`
+	Weight float64               `json:"weight"` // 같은 그룹 안에서의 상대 가중치
+}
+
+// WeightGroup is a named group of factors and subgroups. Weights are relative among the
+// group's direct members, factors and subgroups together, and need not sum to any total.
+type WeightGroup struct {
+	Name    string         `json:"name"`
+	Weight  float64        `json:"weight"` // 상위 그룹(또는 최상위) 안에서의 상대 가중치
+	Factors []FactorWeight `json:"factors,omitempty"`
+	Groups  []WeightGroup  `json:"groups,omitempty"`
+}
+
+// WeightTree weights factors hierarchically: top-level groups are weighted against each other
+// and factors within their group. A factor's flat weight is the product of the shares along
+// its path, so doubling a group's weight scales all of its factors together.
+type WeightTree struct {
+	Groups []WeightGroup `json:"groups"`
+}
+
+// GroupContribution reports how much a weight tree group adds to the total score.
+type GroupContribution struct {
+	Group        string              // 그룹 이름
+	Weight       float64             // 그룹 요소들의 가중치 합 (0-1)
+	Contribution float64             // 총점에 대한 기여 점수
+	Share        float64             // 전체 기여도 중 비율 (%)
+	AverageScore float64             // 그룹 내 가중 평균 점수 (0-100)
+	Groups       []GroupContribution // 하위 그룹
+}
+
+// Validate checks that the tree has groups, that every group is named and non-empty, that
+// weights are finite and non-negative, that groups with weight have members with weight, and
+// that every factor is registered and appears once.
+func (t WeightTree) Validate() error {
+	if len(t.Groups) == 0 {
+		return &ValidationError{Field: "groups", Message: "가중치 트리에 그룹이 없습니다"}
+	}
+	seen := make(map[metadata.MetadataType]string)
+	return validateGroups(t.Groups, "", seen)
+}
+
+func validateGroups(groups []WeightGroup, parent string, seen map[metadata.MetadataType]string) error {
+	names := make(map[string]bool, len(groups))
+	for _, g := range groups {
+		path := g.Name
+		if parent != "" {
+			path = parent + "/" + g.Name
+		}
+		if g.Name == "" {
+			return &ValidationError{Field: parent, Message: "그룹 이름이 비어 있습니다"}
+		}
+		if names[g.Name] {
+			return &ValidationError{Field: path, Message: fmt.Sprintf("그룹 이름이 중복되었습니다: %s", g.Name)}
+		}
+		names[g.Name] = true
+		if err := checkRelativeWeight(g.Weight, path); err != nil {
+			return err
+		}
+		if len(g.Factors) == 0 && len(g.Groups) == 0 {
+			return &ValidationError{Field: path, Message: "그룹에 요소나 하위 그룹이 없습니다"}
+		}
+		total := 0.0
+		for _, f := range g.Factors {
+			if !f.Factor.IsValid() {
+				return &ValidationError{Field: path, Message: fmt.Sprintf(errUnknownMetadata, int(f.Factor))}
+			}
+			if other, duplicate := seen[f.Factor]; duplicate {
+				return &ValidationError{Field: f.Factor.String(),
+					Message: fmt.Sprintf("요소가 여러 그룹에 있습니다 (%s, %s)", other, path)}
+			}
+			seen[f.Factor] = path
+			if err := checkRelativeWeight(f.Weight, f.Factor.String()); err != nil {
+				return err
+			}
+			total += f.Weight
+		}
+		for _, sub := range g.Groups {
+			total += sub.Weight
+		}
+		if total == 0 && g.Weight > 0 {
+			return &ValidationError{Field: path, Message: "그룹 구성원의 가중치 합계가 0입니다"}
+		}
+		if err := validateGroups(g.Groups, path, seen); err != nil {
+			return err
+		}
+	}
+	total := 0.0
+	for _, g := range groups {
+		total += g.Weight
+	}
+	if total == 0 && parent == "" {
+		return &ValidationError{Field: "groups", Message: "최상위 그룹의 가중치 합계가 0입니다"}
+	}
+	return nil
+}
+
+func checkRelativeWeight(w float64, field string) error {
+	if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+		return &ValidationError{Field: field, Message: fmt.Sprintf("가중치는 0 이상의 유한한 값이어야 합니다 (%g)", w)}
+	}
+	return nil
+}
+
+// Shares returns each factor's fraction of the total weight (summing to 1).
+func (t WeightTree) Shares() map[metadata.MetadataType]float64 {
+	shares := make(map[metadata.MetadataType]float64)
+	addShares(t.Groups, nil, 1, shares)
+	return shares
+}
+
+// addShares distributes share among the direct members of a level: groups at this level plus
+// the factors of the enclosing group.
+func addShares(groups []WeightGroup, factors []FactorWeight, share float64, shares map[metadata.MetadataType]float64) {
+	total := 0.0
+	for _, g := range groups {
+		total += g.Weight
+	}
+	for _, f := range factors {
+		total += f.Weight
+	}
+	if total == 0 {
+		return
+	}
+	for _, f := range factors {
+		shares[f.Factor] += share * f.Weight / total
+	}
+	for _, g := range groups {
+		addShares(g.Groups, g.Factors, share*g.Weight/total, shares)
+	}
+}
+
+// Weights flattens the tree to per-factor weights summing to exactly shared.WeightScale.
+func (t WeightTree) Weights() (map[metadata.MetadataType]shared.Weight, error) {
+	if err := t.Validate(); err != nil {
+		return nil, err
+	}
+	return apportion(t.Shares()), nil
+}
+
+// Flatten returns the tree's weights as the array the strategies take.
+func (t WeightTree) Flatten() (shared.WeightArray, error) {
+	weights, err := t.Weights()
+	if err != nil {
+		return shared.WeightArray{}, err
+	}
+	var array shared.WeightArray
+	for mt, w := range weights {
+		array[mt.Index()] = w
+	}
+	return array, nil
+}
+
+// apportion converts fractions summing to 1 into weights summing to exactly shared.WeightScale,
+// distributing rounding by largest remainder with ties going to the lower metadata type.
+func apportion(shares map[metadata.MetadataType]float64) map[metadata.MetadataType]shared.Weight {
+	weights := make(map[metadata.MetadataType]shared.Weight, len(shares))
+	order := make([]metadata.MetadataType, 0, len(shares))
+	remainders := make(map[metadata.MetadataType]float64, len(shares))
+	assigned := shared.Weight(0)
+	for mt, share := range shares {
+		exact := share * shared.WeightScale
+		// 부동소수점 오차로 정수가 x.999…가 되지 않도록 보정
+		floor := math.Floor(exact + 1e-9)
+		weights[mt] = shared.Weight(floor)
+		remainders[mt] = exact - floor
+		assigned += weights[mt]
+		order = append(order, mt)
+	}
+	sort.Slice(order, func(i, j int) bool {
+		// 부동소수점 오차 범위 안의 차이는 동률로 보고 낮은 요소 번호 우선
+		if diff := remainders[order[i]] - remainders[order[j]]; math.Abs(diff) > 1e-9 {
+			return diff > 0
+		}
+		return order[i] < order[j]
+	})
+	for i := 0; assigned < shared.WeightScale && len(order) > 0; i++ {
+		weights[order[i%len(order)]]++
+		assigned++
+	}
+	return weights
+}
+
+// treeFromWeights builds groups whose factor weights are the given flat weights and whose
+// group weights are the sums of their members, so the tree flattens back to the same weights.
+func treeFromWeights(weights map[metadata.MetadataType]shared.Weight, names []string,
+	members map[string][]metadata.MetadataType) WeightTree {
+	var tree WeightTree
+	for _, name := range names {
+		group := WeightGroup{Name: name}
+		for _, mt := range members[name] {
+			w := weights[mt].ToFloat()
+			group.Factors = append(group.Factors, FactorWeight{Factor: mt, Weight: w})
+			group.Weight += w
+		}
+		if len(group.Factors) > 0 {
+			tree.Groups = append(tree.Groups, group)
+		}
+	}
+	return tree
+}
+
+// DefaultWeightTree groups factors by metadata.FactorType (internal building attributes and
+// external surroundings), weighted like the balanced scenario.
+func DefaultWeightTree() WeightTree {
+	members := map[string][]metadata.MetadataType{
+		string(metadata.FactorInternal): metadata.ByFactorType(metadata.FactorInternal),
+		string(metadata.FactorExternal): metadata.ByFactorType(metadata.FactorExternal),
+	}
+	return treeFromWeights(GetScenarioWeights(ScenarioBalanced),
+		[]string{string(metadata.FactorInternal), string(metadata.FactorExternal)}, members)
+}
+
+// Category group names used by CategoryWeightTree.
+const (
+	GroupTransport       = "transport"
+	GroupEducation       = "education"
+	GroupBuildingQuality = "building_quality"
+	GroupLiving          = "living_environment"
+	GroupCost            = "cost"
+	GroupOther           = "other"
+)
+
+// factorCategories assigns the built-in factors to the categories of CategoryWeightTree.
+var factorCategories = map[metadata.MetadataType]string{
+	metadata.DistanceToStation:    GroupTransport,
+	metadata.TransportationAccess: GroupTransport,
+	metadata.SchoolDistrict:       GroupEducation,
+	metadata.FloorLevel:           GroupBuildingQuality,
+	metadata.ElevatorPresence:     GroupBuildingQuality,
+	metadata.ConstructionYear:     GroupBuildingQuality,
+	metadata.ConstructionCompany:  GroupBuildingQuality,
+	metadata.ApartmentSize:        GroupBuildingQuality,
+	metadata.Parking:              GroupBuildingQuality,
+	metadata.HeatingSystem:        GroupBuildingQuality,
+	metadata.NearbyAmenities:      GroupLiving,
+	metadata.CrimeRate:            GroupLiving,
+	metadata.GreenSpaceRatio:      GroupLiving,
+	metadata.MaintenanceFee:       GroupCost,
+}
+
+// CategoryWeightTree groups factors into transport, education, building quality, living
+// environment and cost, weighted like the scenario. Registered custom factors go to "other".
+func CategoryWeightTree(scenario ScoringScenario) WeightTree {
+	members := make(map[string][]metadata.MetadataType)
+	for _, mt := range metadata.All() {
+		category, known := factorCategories[mt]
+		if !known {
+			category = GroupOther
+		}
+		members[category] = append(members[category], mt)
+	}
+	return treeFromWeights(GetScenarioWeights(scenario),
+		[]string{GroupTransport, GroupEducation, GroupBuildingQuality, GroupLiving, GroupCost, GroupOther}, members)
+}
+
+// groupContributions sums the component contributions of each group of the tree.
+func groupContributions(groups []WeightGroup, components map[string]ComponentScore, total float64) []GroupContribution {
+	result := make([]GroupContribution, 0, len(groups))
+	for _, g := range groups {
+		gc := GroupContribution{Group: g.Name, Groups: groupContributions(g.Groups, components, total)}
+		for _, sub := range gc.Groups {
+			gc.Weight += sub.Weight
+			gc.Contribution += sub.Contribution
+		}
+		for _, f := range g.Factors {
+			if component, exists := components[f.Factor.String()]; exists {
+				gc.Weight += component.Weight
+				gc.Contribution += component.Contribution
+			}
+		}
+		if gc.Weight > 0 {
+			gc.AverageScore = gc.Contribution / gc.Weight
+		}
+		if total > 0 {
+			gc.Share = gc.Contribution / total * 100
+		}
+		result = append(result, gc)
+	}
+	return result
+}
+
+// GroupContributions aggregates a score breakdown by the groups of a tree. Only the tree's
+// structure is used; contributions come from the weights the score was calculated with.
+func GroupContributions(tree WeightTree, breakdown ScoreBreakdown) []GroupContribution {
+	total := 0.0
+	for _, component := range breakdown.ComponentScores {
+		total += component.Contribution
+	}
+	return groupContributions(tree.Groups, breakdown.ComponentScores, total)
+}
@@ -0,0 +1,147 @@
+package scoring
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"apart_score/pkg/metadata"
+	"apart_score/pkg/shared"
+)
+
+func TestWeightTreeFlatten(t *testing.T) {
+	tree := WeightTree{Groups: []WeightGroup{
+		{Name: "transport", Weight: 2, Factors: []FactorWeight{
+			{metadata.DistanceToStation, 3},
+			{metadata.TransportationAccess, 1},
+		}},
+		{Name: "building", Weight: 1, Factors: []FactorWeight{{metadata.FloorLevel, 1}}, Groups: []WeightGroup{
+			{Name: "age", Weight: 1, Factors: []FactorWeight{{metadata.ConstructionYear, 1}}},
+		}},
+	}}
+	weights, err := tree.Weights()
+	if err != nil {
+		t.Fatalf("Weights() failed: %v", err)
+	}
+	expected := map[metadata.MetadataType]shared.Weight{
+		metadata.DistanceToStation:    500, // 2/3 × 3/4
+		metadata.TransportationAccess: 166, // 2/3 × 1/4 = 166.67, 동률에서 요소 번호가 가장 큼
+		metadata.FloorLevel:           167, // 1/3 × 1/2 = 166.67
+		metadata.ConstructionYear:     167, // 1/3 × 1/2 × 1/1
+	}
+	total := shared.Weight(0)
+	for mt, w := range weights {
+		total += w
+		if w != expected[mt] {
+			t.Errorf("%s weight = %d, want %d", mt, w, expected[mt])
+		}
+	}
+	if total != shared.WeightScale {
+		t.Errorf("total weight = %d, want %d", total, shared.WeightScale)
+	}
+
+	array, err := tree.Flatten()
+	if err != nil || array[metadata.DistanceToStation.Index()] != 500 {
+		t.Errorf("Flatten() = %v, %v", array, err)
+	}
+
+	// 그룹 가중치를 두 배로 하면 그룹 안의 요소가 함께 늘어남
+	tree.Groups[1].Weight = 2
+	shares := tree.Shares()
+	if math.Abs(shares[metadata.FloorLevel]-0.25) > 1e-9 || math.Abs(shares[metadata.DistanceToStation]-0.375) > 1e-9 {
+		t.Errorf("shares after reweighting groups: %v", shares)
+	}
+}
+
+func TestDefaultWeightTrees(t *testing.T) {
+	tree := DefaultWeightTree()
+	if len(tree.Groups) != 2 || tree.Groups[0].Name != string(metadata.FactorInternal) {
+		t.Fatalf("unexpected default groups: %+v", tree.Groups)
+	}
+	for _, g := range tree.Groups {
+		for _, f := range g.Factors {
+			if string(f.Factor.FactorType()) != g.Name {
+				t.Errorf("%s is %s but in group %s", f.Factor, f.Factor.FactorType(), g.Name)
+			}
+		}
+	}
+	// 트리는 원래 시나리오 가중치로 되돌아가야 함
+	for name, tree := range map[string]WeightTree{"default": DefaultWeightTree(), "category": CategoryWeightTree(ScenarioEducation)} {
+		scenario := ScenarioBalanced
+		if name == "category" {
+			scenario = ScenarioEducation
+		}
+		weights, err := tree.Weights()
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		for mt, w := range GetScenarioWeights(scenario) {
+			if weights[mt] != w {
+				t.Errorf("%s: %s weight = %d, want %d", name, mt, weights[mt], w)
+			}
+		}
+	}
+}
+
+func TestWeightTreeValidate(t *testing.T) {
+	leaf := func(mt metadata.MetadataType) []FactorWeight { return []FactorWeight{{mt, 1}} }
+	tests := map[string]WeightTree{
+		"empty":            {},
+		"unnamed group":    {Groups: []WeightGroup{{Weight: 1, Factors: leaf(metadata.Parking)}}},
+		"empty group":      {Groups: []WeightGroup{{Name: "a", Weight: 1}}},
+		"duplicate factor": {Groups: []WeightGroup{{Name: "a", Weight: 1, Factors: leaf(metadata.Parking)}, {Name: "b", Weight: 1, Factors: leaf(metadata.Parking)}}},
+		"duplicate group":  {Groups: []WeightGroup{{Name: "a", Weight: 1, Factors: leaf(metadata.Parking)}, {Name: "a", Weight: 1, Factors: leaf(metadata.CrimeRate)}}},
+		"negative weight":  {Groups: []WeightGroup{{Name: "a", Weight: -1, Factors: leaf(metadata.Parking)}}},
+		"zero total":       {Groups: []WeightGroup{{Name: "a", Weight: 0, Factors: leaf(metadata.Parking)}}},
+		"weightless group": {Groups: []WeightGroup{{Name: "a", Weight: 1, Factors: []FactorWeight{{metadata.Parking, 0}}}}},
+		"unknown factor":   {Groups: []WeightGroup{{Name: "a", Weight: 1, Factors: leaf(metadata.MetadataType(99))}}},
+	}
+	for name, tree := range tests {
+		var validation *ValidationError
+		if err := tree.Validate(); !errors.As(err, &validation) {
+			t.Errorf("%s: Validate() = %v, want ValidationError", name, err)
+		}
+	}
+}
+
+func TestDashboardGroupContributions(t *testing.T) {
+	scores := uniformScores(80)
+	scores[metadata.SchoolDistrict] = shared.ScoreValueFromFloat(40)
+	weights := GetScenarioWeights(ScenarioBalanced)
+	result, err := CalculateWithStrategy(scores, weights, StrategyWeightedSum)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dashboard := GenerateTransparencyDashboard(result, scores, weights, StrategyWeightedSum)
+	groups := dashboard.ScoreBreakdown.GroupContributions
+	if len(groups) != 2 {
+		t.Fatalf("expected internal/external groups, got %+v", groups)
+	}
+	sum, share := 0.0, 0.0
+	for _, g := range groups {
+		sum += g.Contribution
+		share += g.Share
+	}
+	if math.Abs(sum-result.TotalScore) > 0.01 || math.Abs(share-100) > 1e-6 {
+		t.Errorf("group contributions sum to %.3f (%.1f%%), total %.3f", sum, share, result.TotalScore)
+	}
+
+	tree := CategoryWeightTree(ScenarioBalanced)
+	dashboard, err = GenerateTransparencyDashboardWithOptions(result, scores, weights, StrategyWeightedSum, DashboardOptions{Groups: &tree})
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, g := range dashboard.ScoreBreakdown.GroupContributions {
+		if g.Weight == 0 {
+			continue // 등록된 사용자 정의 요소만 있는 그룹
+		}
+		want := 80.0
+		if g.Group == GroupEducation {
+			want = 40
+		}
+		if math.Abs(g.AverageScore-want) > 0.01 {
+			t.Errorf("%s average = %.2f, want %.0f", g.Group, g.AverageScore, want)
+		}
+	}
+}
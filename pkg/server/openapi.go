@@ -0,0 +1,224 @@
+package server
+
+import (
+	"apart_score/pkg/scoring"
+	"reflect"
+	"strings"
+	"time"
+)
+
+const (
+	// APIVersion is the version reported in the OpenAPI document.
+	APIVersion     = "1.0.0"
+	openAPIVersion = "3.0.3"
+	schemaRefPath  = "#/components/schemas/"
+)
+
+// OpenAPI generates the OpenAPI 3 document for Routes. Schemas are derived from the request and
+// response types by reflection, so the document cannot drift from the handlers.
+func OpenAPI() map[string]interface{} {
+	g := &schemaGenerator{schemas: map[string]interface{}{}, types: map[string]reflect.Type{}}
+	errorResponse := func(description string) map[string]interface{} {
+		return map[string]interface{}{
+			"description": description,
+			"content":     jsonContent(g.schema(reflect.TypeOf(ErrorResponse{}))),
+		}
+	}
+
+	paths := map[string]interface{}{}
+	for _, rt := range Routes() {
+		operation := map[string]interface{}{
+			"operationId": rt.OperationID,
+			"summary":     rt.Summary,
+			"responses": map[string]interface{}{
+				"200": map[string]interface{}{
+					"description": "성공",
+					"content":     jsonContent(g.schema(reflect.TypeOf(rt.Response))),
+				},
+			},
+		}
+		if parameters := pathParameters(rt.Path); len(parameters) > 0 {
+			operation["parameters"] = parameters
+			operation["responses"].(map[string]interface{})["404"] = errorResponse("대상을 찾을 수 없음")
+		}
+		if rt.Request != nil {
+			operation["requestBody"] = map[string]interface{}{
+				"required": true,
+				"content":  jsonContent(g.schema(reflect.TypeOf(rt.Request))),
+			}
+			responses := operation["responses"].(map[string]interface{})
+			responses["400"] = errorResponse("입력 검증 실패 (field에 문제가 된 입력 표시)")
+			responses["413"] = errorResponse("요청 본문이 너무 큼")
+		}
+		item, _ := paths[rt.Path].(map[string]interface{})
+		if item == nil {
+			item = map[string]interface{}{}
+			paths[rt.Path] = item
+		}
+		item[strings.ToLower(rt.Method)] = operation
+	}
+
+	return map[string]interface{}{
+		"openapi": openAPIVersion,
+		"info": map[string]interface{}{
+			"title":       "apart_score API",
+			"version":     APIVersion,
+			"description": "아파트 점수 계산, 순위, 투명성 대시보드 API. 요소는 영문 이름(한글 이름 허용)을 키로 0-100점을 사용하며 null이거나 적지 않은 요소는 데이터 없음입니다.",
+		},
+		"paths":      paths,
+		"components": map[string]interface{}{"schemas": g.schemas},
+	}
+}
+
+// pathParameters documents the {name} wildcards of a route pattern as string path parameters.
+func pathParameters(path string) []interface{} {
+	var parameters []interface{}
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			parameters = append(parameters, map[string]interface{}{
+				"name":     strings.Trim(segment, "{}"),
+				"in":       "path",
+				"required": true,
+				"schema":   map[string]interface{}{"type": "string"},
+			})
+		}
+	}
+	return parameters
+}
+
+func jsonContent(schema map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{"application/json": map[string]interface{}{"schema": schema}}
+}
+
+// enumValues lists the allowed values of string types with a fixed vocabulary.
+func enumValues(t reflect.Type) ([]string, bool) {
+	var values []string
+	switch t {
+	case reflect.TypeOf(scoring.StrategyType("")):
+		for _, s := range scoring.GetAvailableStrategies() {
+			values = append(values, string(s))
+		}
+	case reflect.TypeOf(scoring.ScoringScenario("")):
+		for _, s := range scoring.GetAllScenarios() {
+			values = append(values, string(s))
+		}
+	case reflect.TypeOf(scoring.MissingValuePolicy("")):
+		for _, p := range []scoring.MissingValuePolicy{scoring.MissingRenormalize, scoring.MissingImputeMedian, scoring.MissingFail} {
+			values = append(values, string(p))
+		}
+	case reflect.TypeOf(scoring.ConstraintKind("")):
+		for _, k := range []scoring.ConstraintKind{scoring.ConstraintMinScore, scoring.ConstraintRequiredFeature, scoring.ConstraintExcludedLocation,
+			scoring.ConstraintDominated} {
+			values = append(values, string(k))
+		}
+	case reflect.TypeOf(scoring.ValueFunctionKind("")):
+		for _, k := range scoring.ValueFunctionKinds {
+			values = append(values, string(k))
+		}
+	default:
+		return nil, false
+	}
+	return values, true
+}
+
+// schemaGenerator converts Go types to OpenAPI schemas, registering named structs as components.
+type schemaGenerator struct {
+	schemas map[string]interface{}
+	types   map[string]reflect.Type
+}
+
+func (g *schemaGenerator) schema(t reflect.Type) map[string]interface{} {
+	if t == reflect.TypeOf(time.Time{}) {
+		return map[string]interface{}{"type": "string", "format": "date-time"}
+	}
+	if values, ok := enumValues(t); ok {
+		return map[string]interface{}{"type": "string", "enum": values}
+	}
+	switch t.Kind() {
+	case reflect.Ptr:
+		inner := g.schema(t.Elem())
+		if _, isRef := inner["$ref"]; isRef {
+			return map[string]interface{}{"allOf": []interface{}{inner}, "nullable": true}
+		}
+		inner["nullable"] = true
+		return inner
+	case reflect.Struct:
+		if t.Name() == "" {
+			return g.object(t)
+		}
+		return map[string]interface{}{"$ref": schemaRefPath + g.component(t)}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": g.schema(t.Elem())}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem())}
+	case reflect.Array:
+		return map[string]interface{}{"type": "array", "items": g.schema(t.Elem()), "minItems": t.Len(), "maxItems": t.Len()}
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Int64, reflect.Uint64:
+		return map[string]interface{}{"type": "integer", "format": "int64"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	}
+	return map[string]interface{}{} // interface{} 등 임의의 값
+}
+
+// component registers a named struct and returns its schema name. Types with the same name from
+// different packages are qualified with the package name.
+func (g *schemaGenerator) component(t reflect.Type) string {
+	name := t.Name()
+	if existing, taken := g.types[name]; taken && existing != t {
+		name = t.String()
+		name = strings.ReplaceAll(name, ".", "_")
+	}
+	if _, exists := g.types[name]; !exists {
+		g.types[name] = t
+		g.schemas[name] = map[string]interface{}{} // 재귀 참조 대비 자리 표시
+		g.schemas[name] = g.object(t)
+	}
+	return name
+}
+
+// object builds an object schema from exported fields and their json tags. Embedded structs
+// without a tag are flattened, and fields without omitempty are required.
+func (g *schemaGenerator) object(t reflect.Type) map[string]interface{} {
+	properties := map[string]interface{}{}
+	var required []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		tag := field.Tag.Get("json")
+		if tag == "-" || (field.PkgPath != "" && !field.Anonymous) {
+			continue
+		}
+		name, options, _ := strings.Cut(tag, ",")
+		if field.Anonymous && name == "" && field.Type.Kind() == reflect.Struct {
+			embedded := g.object(field.Type)
+			for key, value := range embedded["properties"].(map[string]interface{}) {
+				properties[key] = value
+			}
+			if names, ok := embedded["required"].([]string); ok {
+				required = append(required, names...)
+			}
+			continue
+		}
+		if field.PkgPath != "" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		properties[name] = g.schema(field.Type)
+		if !strings.Contains(options, "omitempty") {
+			required = append(required, name)
+		}
+	}
+	schema := map[string]interface{}{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+	return schema
+}
@@ -0,0 +1,438 @@
+package server
+
+import (
+	"apart_score/pkg/codec"
+	"apart_score/pkg/profile"
+	"apart_score/pkg/scoring"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// Route describes one endpoint. Request and Response are zero values of the body types and
+// drive the generated OpenAPI document.
+type Route struct {
+	Method      string
+	Path        string
+	OperationID string
+	Summary     string
+	Request     interface{} // nil이면 요청 본문 없음
+	Response    interface{}
+	handle      func(s *Server, r *http.Request) (interface{}, error)
+}
+
+// Routes lists the API endpoints in documentation order.
+func Routes() []Route {
+	return []Route{
+		{http.MethodPost, "/v1/score", "score", "아파트 한 곳의 점수 계산", ScoreRequest{}, codec.ScoreResultRecord{}, (*Server).handleScore},
+		{http.MethodPost, "/v1/rank", "rank", "여러 아파트의 순위 계산", RankRequest{}, codec.RankingsRecord{}, (*Server).handleRank},
+		{http.MethodPost, "/v1/compare", "compare", "두 아파트 비교 설명", CompareRequest{}, codec.ComparisonRecord{}, (*Server).handleCompare},
+		{http.MethodPost, "/v1/dashboard", "dashboard", "투명성 대시보드 생성", DashboardRequest{}, scoring.TransparencyDashboard{}, (*Server).handleDashboard},
+		{http.MethodGet, "/v1/scenarios", "listScenarios", "가중치 시나리오 목록", nil, []ScenarioInfo{}, (*Server).handleScenarios},
+		{http.MethodGet, "/v1/strategies", "listStrategies", "계산 전략 목록", nil, []StrategyInfo{}, (*Server).handleStrategies},
+		{http.MethodGet, "/v1/profiles", "listProfiles", "저장된 프로필 목록", nil, []profile.Document{}, (*Server).handleListProfiles},
+		{http.MethodGet, "/v1/profiles/{name}", "getProfile", "프로필 조회", nil, profile.Document{}, (*Server).handleGetProfile},
+		{http.MethodPut, "/v1/profiles/{name}", "saveProfile", "프로필 저장 (같은 이름이면 덮어씀)", profile.Document{}, profile.Document{}, (*Server).handleSaveProfile},
+		{http.MethodDelete, "/v1/profiles/{name}", "deleteProfile", "프로필 삭제", nil, profile.Document{}, (*Server).handleDeleteProfile},
+		{http.MethodGet, "/openapi.json", "openapi", "OpenAPI 문서", nil, map[string]interface{}{}, (*Server).handleOpenAPI},
+	}
+}
+
+// WeightSelection chooses the weights and strategy of a request. A stored Profile supplies
+// weights, strategy, constraints and value functions and excludes Weights, Scenario and
+// ValueFunctions. Otherwise Weights take precedence over Scenario; with neither, the balanced
+// scenario is used. Weights are relative and normalized. Strategy, when set, overrides the profile's.
+type WeightSelection struct {
+	Profile        string                     `json:"profile,omitempty"`
+	Weights        codec.FactorWeights        `json:"weights,omitempty"`
+	Scenario       scoring.ScoringScenario    `json:"scenario,omitempty"`
+	Strategy       scoring.StrategyType       `json:"strategy,omitempty"` // 기본값: weighted_sum 또는 프로필의 전략
+	ValueFunctions codec.ValueFunctionsRecord `json:"value_functions,omitempty"`
+}
+
+// ScoreRequest is the body of POST /v1/score.
+type ScoreRequest struct {
+	Scores codec.FactorScores `json:"scores"`
+	WeightSelection
+}
+
+// RankRequest is the body of POST /v1/rank.
+type RankRequest struct {
+	Apartments []codec.ApartmentRecord `json:"apartments"`
+	WeightSelection
+	MissingPolicy scoring.MissingValuePolicy `json:"missing_policy,omitempty"`
+	Limit         int                        `json:"limit,omitempty"` // 0이면 전체
+	Pareto        *ParetoFilter              `json:"pareto,omitempty"`
+}
+
+// ParetoFilter removes apartments dominated by another one before ranking.
+type ParetoFilter struct {
+	Factors []string `json:"factors,omitempty"` // 비교할 요소 (비어 있으면 모든 요소)
+}
+
+// CompareRequest is the body of POST /v1/compare. The apartments need distinct IDs; missing
+// IDs default to "a" and "b".
+type CompareRequest struct {
+	A codec.ApartmentRecord `json:"a"`
+	B codec.ApartmentRecord `json:"b"`
+	WeightSelection
+}
+
+// DashboardRequest is the body of POST /v1/dashboard. The cohort, when given, places the
+// score among comparable apartments. The pipeline, when given, scores instead of the strategy
+// and its step-by-step trace is added to the dashboard.
+type DashboardRequest struct {
+	Scores codec.FactorScores `json:"scores"`
+	WeightSelection
+	Cohort   []codec.ApartmentRecord `json:"cohort,omitempty"`
+	Seed     int64                   `json:"seed,omitempty"`
+	Pipeline *codec.PipelineRecord   `json:"pipeline,omitempty"`
+}
+
+// ScenarioInfo describes a weight scenario.
+type ScenarioInfo struct {
+	ID          scoring.ScoringScenario `json:"id"`
+	Name        string                  `json:"name"`
+	TargetUser  string                  `json:"target_user"`
+	Description string                  `json:"description"`
+	UseCase     string                  `json:"use_case"`
+	Weights     codec.FactorWeights     `json:"weights"`
+}
+
+// StrategyInfo describes a calculation strategy.
+type StrategyInfo struct {
+	ID          scoring.StrategyType `json:"id"`
+	Description string               `json:"description"`
+	BestFor     string               `json:"best_for,omitempty"`
+	WhenToUse   string               `json:"when_to_use,omitempty"`
+}
+
+// Scenarios returns the scenario catalog.
+func Scenarios() []ScenarioInfo {
+	var scenarios []ScenarioInfo
+	for _, scenario := range scoring.GetAllScenarios() {
+		definition := scoring.ScenarioDefinitions[scenario]
+		scenarios = append(scenarios, ScenarioInfo{scenario, definition.Name, definition.TargetUser,
+			definition.Description, definition.UseCase, codec.NewFactorWeights(scoring.GetScenarioWeights(scenario))})
+	}
+	return scenarios
+}
+
+// Strategies returns the strategy catalog, including registered custom strategies.
+func Strategies() []StrategyInfo {
+	var strategies []StrategyInfo
+	for _, strategy := range scoring.GetAvailableStrategies() {
+		guide, _ := scoring.GetStrategyGuide(strategy)
+		strategies = append(strategies, StrategyInfo{strategy, scoring.GetStrategyDescription(strategy), guide.BestFor, guide.WhenToUse})
+	}
+	return strategies
+}
+
+// resolve validates the selection and returns the profile to score with; ad hoc selections
+// resolve to an unnamed profile.
+func (s *Server) resolve(sel WeightSelection) (scoring.ScoringProfile, error) {
+	var p scoring.ScoringProfile
+	switch {
+	case sel.Profile != "":
+		if len(sel.Weights) > 0 || sel.Scenario != "" || len(sel.ValueFunctions) > 0 {
+			return p, &scoring.ValidationError{Field: "profile",
+				Message: "profile은 weights, scenario, value_functions와 함께 지정할 수 없습니다"}
+		}
+		loaded, err := s.loadProfile(sel.Profile)
+		if errors.Is(err, profile.ErrNotFound) {
+			return p, &scoring.ValidationError{Field: "profile", Message: err.Error()}
+		}
+		if err != nil {
+			return p, err
+		}
+		p = loaded
+	case len(sel.Weights) > 0:
+		weights, err := sel.Weights.Weights()
+		if err != nil {
+			return p, withField(err, "weights")
+		}
+		p.Weights = weights
+	case sel.Scenario != "":
+		if _, exists := scoring.ScenarioDefinitions[sel.Scenario]; !exists {
+			return p, &scoring.ValidationError{Field: "scenario",
+				Message: fmt.Sprintf("알 수 없는 시나리오: %s (사용 가능: %v)", sel.Scenario, scoring.GetAllScenarios())}
+		}
+		p.Scenario = sel.Scenario
+	}
+	if len(sel.ValueFunctions) > 0 {
+		functions, err := sel.ValueFunctions.ValueFunctions()
+		if err != nil {
+			return p, withField(err, "value_functions")
+		}
+		p.ValueFunctions = functions
+	}
+	if sel.Strategy != "" {
+		p.Method = sel.Strategy
+	}
+	if _, exists := scoring.LookupStrategy(p.Strategy()); !exists {
+		return p, &scoring.ValidationError{Field: "strategy",
+			Message: fmt.Sprintf("알 수 없는 전략: %s (사용 가능: %v)", p.Method, scoring.GetAvailableStrategies())}
+	}
+	return p, nil
+}
+
+// loadProfile reads a profile from the configured store.
+func (s *Server) loadProfile(name string) (scoring.ScoringProfile, error) {
+	if s.opts.Profiles == nil {
+		return scoring.ScoringProfile{}, errNoProfileStore
+	}
+	return s.opts.Profiles.Load(name)
+}
+
+// withField turns an error from parsing request input into a ValidationError for field, keeping
+// typed errors as they are. It is only for input errors: store and other server failures must
+// pass through unchanged so that they are reported as 500.
+func withField(err error, field string) error {
+	var validation *scoring.ValidationError
+	var unknown *codec.UnknownFactorError
+	if errors.As(err, &validation) || errors.As(err, &unknown) {
+		return err
+	}
+	return &scoring.ValidationError{Field: field, Message: err.Error()}
+}
+
+func apartments(records []codec.ApartmentRecord, field string) ([]scoring.ApartmentData, error) {
+	result := make([]scoring.ApartmentData, len(records))
+	for i, record := range records {
+		if record.ID == "" {
+			record.ID = fmt.Sprintf("%d", i+1)
+		}
+		if record.Name == "" {
+			record.Name = record.ID
+		}
+		apt, err := record.Apartment()
+		if err != nil {
+			return nil, withField(err, fmt.Sprintf("%s[%d]", field, i))
+		}
+		result[i] = apt
+	}
+	return result, nil
+}
+
+func (s *Server) handleScore(r *http.Request) (interface{}, error) {
+	var req ScoreRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	p, err := s.resolve(req.WeightSelection)
+	if err != nil {
+		return nil, err
+	}
+	apt, err := codec.ApartmentRecord{Scores: req.Scores}.Apartment()
+	if err != nil {
+		return nil, withField(err, "scores")
+	}
+	result, err := scoring.CalculateWithProfile(apt.Scores, p)
+	if err != nil {
+		return nil, err
+	}
+	return codec.NewScoreResultRecord(result), nil
+}
+
+func (s *Server) handleRank(r *http.Request) (interface{}, error) {
+	var req RankRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	if req.Limit < 0 {
+		return nil, &scoring.ValidationError{Field: "limit", Message: "limit은 0 이상이어야 합니다"}
+	}
+	p, err := s.resolve(req.WeightSelection)
+	if err != nil {
+		return nil, err
+	}
+	apts, err := apartments(req.Apartments, "apartments")
+	if err != nil {
+		return nil, err
+	}
+	if len(apts) == 0 {
+		return nil, &scoring.ValidationError{Field: "apartments", Message: "순위를 매길 아파트가 없습니다"}
+	}
+	opts := scoring.RankingOptions{MissingPolicy: req.MissingPolicy}
+	if req.Pareto != nil {
+		factors, err := codec.ParseFactors(req.Pareto.Factors)
+		if err != nil {
+			return nil, err
+		}
+		opts.Pareto = &scoring.ParetoOptions{Factors: factors}
+	}
+	summary, err := scoring.CalculateRankingsWithProfile(apts, p, opts)
+	if err != nil {
+		return nil, err
+	}
+	if req.Limit > 0 && req.Limit < len(summary.TopRanked) {
+		summary.TopRanked = summary.TopRanked[:req.Limit]
+	}
+	return codec.NewRankingsRecord(summary), nil
+}
+
+func (s *Server) handleCompare(r *http.Request) (interface{}, error) {
+	var req CompareRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	p, err := s.resolve(req.WeightSelection)
+	if err != nil {
+		return nil, err
+	}
+	if req.A.ID == "" {
+		req.A.ID = "a"
+	}
+	if req.B.ID == "" {
+		req.B.ID = "b"
+	}
+	a, err := req.A.Apartment()
+	if err != nil {
+		return nil, withField(err, "a")
+	}
+	b, err := req.B.Apartment()
+	if err != nil {
+		return nil, withField(err, "b")
+	}
+	comparison, err := scoring.CompareApartments(a, b, p)
+	if err != nil {
+		return nil, err
+	}
+	return codec.NewComparisonRecord(comparison), nil
+}
+
+func (s *Server) handleDashboard(r *http.Request) (interface{}, error) {
+	var req DashboardRequest
+	if err := decode(r, &req); err != nil {
+		return nil, err
+	}
+	p, err := s.resolve(req.WeightSelection)
+	if err != nil {
+		return nil, err
+	}
+	apt, err := codec.ApartmentRecord{Scores: req.Scores}.Apartment()
+	if err != nil {
+		return nil, withField(err, "scores")
+	}
+	cohort, err := apartments(req.Cohort, "cohort")
+	if err != nil {
+		return nil, err
+	}
+	opts := scoring.DashboardOptions{Cohort: cohort, Seed: req.Seed, ValueFunctions: p.ValueFunctions}
+	var result scoring.ScoreResult
+	if req.Pipeline != nil {
+		spec, err := req.Pipeline.Spec()
+		if err != nil {
+			return nil, withField(err, "pipeline")
+		}
+		pipeline, err := spec.Compile()
+		if err != nil {
+			return nil, withField(err, "pipeline")
+		}
+		opts.Pipeline = &pipeline
+		// 파이프라인 식 계산 오류(0으로 나누기 등)는 요청의 파이프라인 문제
+		if result, err = scoring.CalculateWithPipeline(p.ValueFunctions.Apply(apt.Scores), p.EffectiveWeights(), pipeline); err != nil {
+			return nil, withField(err, "pipeline")
+		}
+	} else {
+		result, err = scoring.CalculateWithProfile(apt.Scores, p)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return scoring.GenerateTransparencyDashboardWithOptions(result, apt.Scores, p.EffectiveWeights(), p.Strategy(), opts)
+}
+
+func (s *Server) handleScenarios(*http.Request) (interface{}, error) {
+	return Scenarios(), nil
+}
+
+func (s *Server) handleStrategies(*http.Request) (interface{}, error) {
+	return Strategies(), nil
+}
+
+// store returns the profile store, or a 501 error when the server has none.
+func (s *Server) store() (*profile.Store, error) {
+	if s.opts.Profiles == nil {
+		return nil, errNoProfileStore
+	}
+	return s.opts.Profiles, nil
+}
+
+func (s *Server) handleListProfiles(*http.Request) (interface{}, error) {
+	store, err := s.store()
+	if err != nil {
+		return nil, err
+	}
+	names, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	documents := make([]profile.Document, 0, len(names))
+	for _, name := range names {
+		p, err := store.Load(name)
+		if err != nil {
+			return nil, err
+		}
+		documents = append(documents, profile.NewDocument(p))
+	}
+	return documents, nil
+}
+
+func (s *Server) handleGetProfile(r *http.Request) (interface{}, error) {
+	p, err := s.loadProfile(r.PathValue("name"))
+	if err != nil {
+		return nil, err
+	}
+	return profile.NewDocument(p), nil
+}
+
+func (s *Server) handleSaveProfile(r *http.Request) (interface{}, error) {
+	store, err := s.store()
+	if err != nil {
+		return nil, err
+	}
+	var doc profile.Document
+	if err := decode(r, &doc); err != nil {
+		return nil, err
+	}
+	name := r.PathValue("name")
+	if doc.Name == "" {
+		doc.Name = name
+	}
+	if doc.Name != name {
+		return nil, &scoring.ValidationError{Field: "name", Message: fmt.Sprintf("본문의 이름(%s)이 경로의 이름(%s)과 다릅니다", doc.Name, name)}
+	}
+	if doc.SchemaVersion != 0 && doc.SchemaVersion != profile.SchemaVersion {
+		return nil, &scoring.ValidationError{Field: "schema_version",
+			Message: fmt.Sprintf("schema_version은 %d이어야 합니다 (%d)", profile.SchemaVersion, doc.SchemaVersion)}
+	}
+	p, err := doc.Profile()
+	if err != nil {
+		return nil, withField(err, "profile")
+	}
+	if err := store.Save(p); err != nil {
+		return nil, err
+	}
+	return profile.NewDocument(p), nil
+}
+
+func (s *Server) handleDeleteProfile(r *http.Request) (interface{}, error) {
+	store, err := s.store()
+	if err != nil {
+		return nil, err
+	}
+	name := r.PathValue("name")
+	p, err := store.Load(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := store.Delete(name); err != nil {
+		return nil, err
+	}
+	return profile.NewDocument(p), nil
+}
+
+func (s *Server) handleOpenAPI(*http.Request) (interface{}, error) {
+	return OpenAPI(), nil
+}
@@ -0,0 +1,144 @@
+// Package server exposes the scoring engine as an HTTP JSON API.
+package server
+
+import (
+	"apart_score/pkg/codec"
+	"apart_score/pkg/profile"
+	"apart_score/pkg/scoring"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// DefaultMaxBodyBytes is the request body limit when Options.MaxBodyBytes is 0.
+const DefaultMaxBodyBytes = 10 << 20
+
+// Options configures a Server.
+type Options struct {
+	MaxBodyBytes int64          // 요청 본문 최대 크기 (기본값: DefaultMaxBodyBytes)
+	Profiles     *profile.Store // 프로필 저장소 (nil이면 프로필 기능 사용 불가)
+}
+
+// Server is an http.Handler serving the routes listed by Routes.
+type Server struct {
+	opts Options
+	mux  *http.ServeMux
+}
+
+// APIError is the body of every non-2xx response. Field names the offending input when known.
+type APIError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ErrorResponse wraps APIError.
+type ErrorResponse struct {
+	Error APIError `json:"error"`
+}
+
+// statusError carries an HTTP status other than 400 through a handler.
+type statusError struct {
+	status int
+	err    APIError
+}
+
+func (e *statusError) Error() string {
+	return e.err.Message
+}
+
+var errNoProfileStore = &statusError{http.StatusNotImplemented, APIError{Field: "profile", Message: "프로필 저장소가 설정되지 않았습니다"}}
+
+// New creates a server with every route registered.
+func New(opts Options) *Server {
+	if opts.MaxBodyBytes <= 0 {
+		opts.MaxBodyBytes = DefaultMaxBodyBytes
+	}
+	s := &Server{opts: opts, mux: http.NewServeMux()}
+	methods := make(map[string][]string)
+	for _, rt := range Routes() {
+		rt := rt
+		s.mux.HandleFunc(rt.Method+" "+rt.Path, func(w http.ResponseWriter, r *http.Request) {
+			s.serve(w, r, rt)
+		})
+		methods[rt.Path] = append(methods[rt.Path], rt.Method)
+	}
+	// 등록된 경로의 다른 메서드와 알 수 없는 경로도 JSON 오류로 응답
+	for path, allowed := range methods {
+		allow := strings.Join(allowed, ", ")
+		s.mux.HandleFunc(path, func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Allow", allow)
+			writeError(w, &statusError{http.StatusMethodNotAllowed,
+				APIError{Message: fmt.Sprintf("허용되지 않는 메서드: %s (허용: %s)", r.Method, allow)}})
+		})
+	}
+	s.mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, &statusError{http.StatusNotFound, APIError{Message: "알 수 없는 경로: " + r.URL.Path}})
+	})
+	return s
+}
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mux.ServeHTTP(w, r)
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request, rt Route) {
+	if r.Body != nil {
+		r.Body = http.MaxBytesReader(w, r.Body, s.opts.MaxBodyBytes)
+	}
+	response, err := rt.handle(s, r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, response)
+}
+
+// decode reads a JSON request body into v, rejecting unknown fields and trailing data.
+func decode(r *http.Request, v interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(v); err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return &statusError{http.StatusRequestEntityTooLarge,
+				APIError{Field: "body", Message: fmt.Sprintf("요청 본문이 너무 큽니다 (최대 %d바이트)", tooLarge.Limit)}}
+		}
+		return &scoring.ValidationError{Field: "body", Message: "요청 본문을 해석할 수 없습니다: " + err.Error()}
+	}
+	if decoder.More() {
+		return &scoring.ValidationError{Field: "body", Message: "요청 본문에 JSON 값이 여러 개 있습니다"}
+	}
+	return nil
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	_ = encoder.Encode(v)
+}
+
+// writeError maps an error to a status code: ValidationError (which request decoding also
+// returns) and unknown factor names are 400 with the offending field, statusError keeps its
+// status, a missing profile is 404, and anything else, such as a profile store failure, is 500.
+func writeError(w http.ResponseWriter, err error) {
+	var status *statusError
+	var validation *scoring.ValidationError
+	var unknown *codec.UnknownFactorError
+	switch {
+	case errors.As(err, &status):
+		writeJSON(w, status.status, ErrorResponse{status.err})
+	case errors.Is(err, profile.ErrNotFound):
+		writeJSON(w, http.StatusNotFound, ErrorResponse{APIError{Field: "name", Message: err.Error()}})
+	case errors.As(err, &validation):
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{APIError{Field: validation.Field, Message: err.Error()}})
+	case errors.As(err, &unknown):
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{APIError{Field: unknown.Name, Message: err.Error()}})
+	default:
+		writeJSON(w, http.StatusInternalServerError, ErrorResponse{APIError{Message: err.Error()}})
+	}
+}
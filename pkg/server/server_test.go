@@ -0,0 +1,327 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"apart_score/pkg/codec"
+	"apart_score/pkg/profile"
+	"apart_score/pkg/scoring"
+)
+
+var update = flag.Bool("update", false, "api/openapi.json 갱신")
+
+// do sends a request to a fresh server and decodes a JSON response into v when v is non-nil.
+func do(t *testing.T, method, path, body string, v interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	return doWith(t, New(Options{MaxBodyBytes: 4096}), method, path, body, v)
+}
+
+func doWith(t *testing.T, s *Server, method, path, body string, v interface{}) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	s.ServeHTTP(rec, req)
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Fatalf("%s %s: Content-Type = %q", method, path, ct)
+	}
+	if v != nil {
+		if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+			t.Fatalf("%s %s: 응답 해석 실패: %v\n%s", method, path, err, rec.Body.String())
+		}
+	}
+	return rec
+}
+
+const apartmentsBody = `[
+	{"id": "a", "scores": {"Floor Level": 90, "Distance to Station": 90, "Parking": 80}},
+	{"id": "b", "scores": {"Floor Level": 50, "Distance to Station": 40, "Parking": 30}},
+	{"id": "c", "scores": {"층수": 70, "역까지 거리": 70, "Parking": null}}
+]`
+
+func TestScore(t *testing.T) {
+	var result codec.ScoreResultRecord
+	rec := do(t, http.MethodPost, "/v1/score",
+		`{"scores": {"Floor Level": 80, "역까지 거리": 60}, "weights": {"Floor Level": 1, "Distance to Station": 1}}`, &result)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if result.TotalScore != 70 {
+		t.Errorf("TotalScore = %v, want 70", result.TotalScore)
+	}
+}
+
+func TestScoreValidationErrors(t *testing.T) {
+	tests := []struct {
+		name, body, field string
+	}{
+		{"unknown factor", `{"scores": {"Ocean View": 80}}`, "Ocean View"},
+		{"score out of range", `{"scores": {"Floor Level": 120}}`, "scores"},
+		{"unknown strategy", `{"scores": {"Floor Level": 80}, "strategy": "magic"}`, "strategy"},
+		{"unknown scenario", `{"scores": {"Floor Level": 80}, "scenario": "luxury"}`, "scenario"},
+		{"unknown field", `{"scores": {}, "extra": 1}`, "body"},
+		{"malformed", `{"scores":`, "body"},
+		{"trailing data", `{"scores": {}} {}`, "body"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var resp ErrorResponse
+			rec := do(t, http.MethodPost, "/v1/score", tt.body, &resp)
+			if rec.Code != http.StatusBadRequest {
+				t.Fatalf("status = %d, want 400: %s", rec.Code, rec.Body.String())
+			}
+			if resp.Error.Field != tt.field || resp.Error.Message == "" {
+				t.Errorf("error = %+v, want field %q", resp.Error, tt.field)
+			}
+		})
+	}
+}
+
+func TestRank(t *testing.T) {
+	var result codec.RankingsRecord
+	rec := do(t, http.MethodPost, "/v1/rank",
+		`{"apartments": `+apartmentsBody+`, "scenario": "transportation", "limit": 2}`, &result)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if result.TotalApartments != 3 || len(result.Rankings) != 2 {
+		t.Fatalf("total %d, rankings %d; want 3, 2", result.TotalApartments, len(result.Rankings))
+	}
+	if result.Rankings[0].Apartment.ID != "a" {
+		t.Errorf("first = %s, want a", result.Rankings[0].Apartment.ID)
+	}
+
+	// b는 a에 지배되고, c는 주차장 데이터가 없어 a와 비교할 수 없음
+	rec = do(t, http.MethodPost, "/v1/rank", `{"apartments": `+apartmentsBody+`, "pareto": {}}`, &result)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("pareto: status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if result.TotalApartments != 2 || len(result.Eliminated) != 1 || result.Eliminated[0].Apartment.ID != "b" ||
+		result.Eliminated[0].Violations[0].Kind != scoring.ConstraintDominated {
+		t.Errorf("pareto prefilter: %+v", result)
+	}
+
+	var resp ErrorResponse
+	rec = do(t, http.MethodPost, "/v1/rank", `{"apartments": `+apartmentsBody+`, "missing_policy": "fail"}`, &resp)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("missing_policy fail: status = %d, want 400", rec.Code)
+	}
+	rec = do(t, http.MethodPost, "/v1/rank", `{"apartments": `+apartmentsBody+`, "pareto": {"factors": ["Bogus"]}}`, &resp)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("unknown pareto factor: status = %d, want 400", rec.Code)
+	}
+	rec = do(t, http.MethodPost, "/v1/rank", `{"apartments": [], "limit": -1}`, &resp)
+	if rec.Code != http.StatusBadRequest || resp.Error.Field != "limit" {
+		t.Errorf("negative limit: status %d, error %+v", rec.Code, resp.Error)
+	}
+	rec = do(t, http.MethodPost, "/v1/rank", `{"apartments": []}`, &resp)
+	if rec.Code != http.StatusBadRequest || resp.Error.Field != "apartments" {
+		t.Errorf("no apartments: status %d, error %+v", rec.Code, resp.Error)
+	}
+}
+
+func TestDashboard(t *testing.T) {
+	var dashboard map[string]interface{}
+	rec := do(t, http.MethodPost, "/v1/dashboard",
+		`{"scores": {"Floor Level": 80, "Distance to Station": 70}, "cohort": `+apartmentsBody+`, "seed": 1}`, &dashboard)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if _, ok := dashboard["ScoreBreakdown"]; !ok {
+		t.Errorf("dashboard missing ScoreBreakdown: %v", dashboard)
+	}
+
+	var curved scoring.TransparencyDashboard
+	rec = do(t, http.MethodPost, "/v1/dashboard", `{"scores": {"Floor Level": 80, "Distance to Station": 70},
+		"value_functions": {"Floor Level": {"kind": "threshold", "threshold": 75, "bonus": 20}}}`, &curved)
+	floor := curved.ScoreBreakdown.ComponentScores["Floor Level"]
+	if rec.Code != http.StatusOK || floor.RawValue != 80 || floor.NormalizedValue != 100 {
+		t.Errorf("dashboard with value functions: status %d, floor %+v", rec.Code, floor)
+	}
+
+	var traced scoring.TransparencyDashboard
+	rec = do(t, http.MethodPost, "/v1/dashboard", `{"scores": {"Floor Level": 80, "Distance to Station": 70}, "cohort": `+apartmentsBody+`,
+		"pipeline": {"name": "역세권", "steps": [{"name": "기본", "kind": "strategy", "strategy": "weighted_sum", "weight": 1},
+			{"name": "보너스", "kind": "bonus_if", "points": 5, "when": {"factor": "Distance to Station", "op": ">=", "value": 90}}]}}`, &traced)
+	if rec.Code != http.StatusOK || traced.PipelineTrace == nil || len(traced.PipelineTrace.Steps) != 2 || traced.PipelineTrace.Steps[1].Fired {
+		t.Errorf("dashboard with pipeline: status %d, trace %+v", rec.Code, traced.PipelineTrace)
+	}
+	var resp ErrorResponse
+	rec = do(t, http.MethodPost, "/v1/dashboard", `{"scores": {"Floor Level": 80}, "pipeline": {"name": "빈 파이프라인", "steps": []}}`, &resp)
+	if rec.Code != http.StatusBadRequest || resp.Error.Field != "steps" {
+		t.Errorf("empty pipeline: status %d, error %+v", rec.Code, resp.Error)
+	}
+	rec = do(t, http.MethodPost, "/v1/dashboard", `{"scores": {"Floor Level": 80},
+		"pipeline": {"name": "식", "steps": [{"name": "비율", "kind": "formula", "formula": "1 / (층수 - 80)"}]}}`, &resp)
+	if rec.Code != http.StatusBadRequest || resp.Error.Field != "pipeline" {
+		t.Errorf("pipeline evaluation error: status %d, error %+v", rec.Code, resp.Error)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	var comparison codec.ComparisonRecord
+	rec := do(t, http.MethodPost, "/v1/compare", `{"a": {"id": "near", "scores": {"Distance to Station": 95, "School District": 60}},
+		"b": {"scores": {"Distance to Station": 60, "School District": 95}}, "scenario": "education"}`, &comparison)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d: %s", rec.Code, rec.Body.String())
+	}
+	if comparison.Winner != "b" || len(comparison.Decisive) == 0 || comparison.Decisive[0] != "School District" || comparison.Robust {
+		t.Errorf("unexpected comparison: %+v", comparison)
+	}
+
+	var errResp ErrorResponse
+	rec = do(t, http.MethodPost, "/v1/compare", `{"a": {"id": "x", "scores": {}}, "b": {"id": "x", "scores": {}}}`, &errResp)
+	if rec.Code != http.StatusBadRequest || errResp.Error.Field != "b" {
+		t.Errorf("same IDs: status %d, %+v", rec.Code, errResp)
+	}
+}
+
+func TestCatalogs(t *testing.T) {
+	var scenarios []ScenarioInfo
+	if rec := do(t, http.MethodGet, "/v1/scenarios", "", &scenarios); rec.Code != http.StatusOK || len(scenarios) == 0 {
+		t.Errorf("scenarios: status %d, %d entries", rec.Code, len(scenarios))
+	}
+	var strategies []StrategyInfo
+	if rec := do(t, http.MethodGet, "/v1/strategies", "", &strategies); rec.Code != http.StatusOK || len(strategies) == 0 {
+		t.Errorf("strategies: status %d, %d entries", rec.Code, len(strategies))
+	}
+}
+
+func TestProfiles(t *testing.T) {
+	s := New(Options{Profiles: profile.NewStore(t.TempDir())})
+	var doc profile.Document
+	rec := doWith(t, s, http.MethodPut, "/v1/profiles/commuter",
+		`{"strategy": "min_max", "weights": {"Distance to Station": 3, "Floor Level": 1},
+		  "constraints": {"excluded_locations": ["경기도"]}}`, &doc)
+	if rec.Code != http.StatusOK || doc.SchemaVersion != profile.SchemaVersion || doc.Name != "commuter" {
+		t.Fatalf("PUT: status %d, %+v", rec.Code, doc)
+	}
+
+	var result codec.ScoreResultRecord
+	rec = doWith(t, s, http.MethodPost, "/v1/score", `{"scores": {"Floor Level": 80, "Distance to Station": 60}, "profile": "commuter"}`, &result)
+	if rec.Code != http.StatusOK || result.Method != scoring.StrategyMinMax {
+		t.Fatalf("score with profile: status %d, method %s: %s", rec.Code, result.Method, rec.Body.String())
+	}
+
+	var rankings codec.RankingsRecord
+	rec = doWith(t, s, http.MethodPost, "/v1/rank", `{"apartments": [
+		{"id": "seoul", "location": "서울시", "scores": {"Floor Level": 50, "Distance to Station": 50}},
+		{"id": "gyeonggi", "location": "경기도 고양시", "scores": {"Floor Level": 90, "Distance to Station": 90}}
+	], "profile": "commuter"}`, &rankings)
+	if rec.Code != http.StatusOK || len(rankings.Rankings) != 1 || len(rankings.Eliminated) != 1 {
+		t.Fatalf("rank with profile constraints: status %d, %s", rec.Code, rec.Body.String())
+	}
+
+	var list []profile.Document
+	if rec := doWith(t, s, http.MethodGet, "/v1/profiles", "", &list); rec.Code != http.StatusOK || len(list) != 1 {
+		t.Errorf("list: status %d, %d profiles", rec.Code, len(list))
+	}
+
+	var resp ErrorResponse
+	tests := []struct {
+		method, path, body string
+		status             int
+		field              string
+	}{
+		{http.MethodPost, "/v1/score", `{"scores": {}, "profile": "missing"}`, http.StatusBadRequest, "profile"},
+		{http.MethodPost, "/v1/score", `{"scores": {}, "profile": "commuter", "scenario": "balanced"}`, http.StatusBadRequest, "profile"},
+		{http.MethodPut, "/v1/profiles/other", `{"name": "commuter"}`, http.StatusBadRequest, "name"},
+		{http.MethodPut, "/v1/profiles/bad", `{"strategy": "magic"}`, http.StatusBadRequest, "strategy"},
+		{http.MethodPut, "/v1/profiles/old", `{"schema_version": 1}`, http.StatusBadRequest, "schema_version"},
+		{http.MethodGet, "/v1/profiles/missing", "", http.StatusNotFound, "name"},
+		{http.MethodDelete, "/v1/profiles/commuter", "", http.StatusOK, ""},
+		{http.MethodDelete, "/v1/profiles/commuter", "", http.StatusNotFound, "name"},
+	}
+	for _, tt := range tests {
+		resp = ErrorResponse{}
+		rec := doWith(t, s, tt.method, tt.path, tt.body, &resp)
+		if rec.Code != tt.status || resp.Error.Field != tt.field {
+			t.Errorf("%s %s: status %d, error %+v; want %d, field %q", tt.method, tt.path, rec.Code, resp.Error, tt.status, tt.field)
+		}
+	}
+
+	// 저장소가 없는 서버
+	if rec := do(t, http.MethodGet, "/v1/profiles", "", &resp); rec.Code != http.StatusNotImplemented {
+		t.Errorf("without store: status %d, want 501", rec.Code)
+	}
+
+	// 손상된 프로필 파일은 요청 오류가 아닌 서버 오류
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{not json"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	s = New(Options{Profiles: profile.NewStore(dir)})
+	for _, tt := range []struct{ method, path, body string }{
+		{http.MethodGet, "/v1/profiles", ""},
+		{http.MethodGet, "/v1/profiles/broken", ""},
+		{http.MethodDelete, "/v1/profiles/broken", ""},
+		{http.MethodPost, "/v1/score", `{"scores": {}, "profile": "broken"}`},
+	} {
+		resp = ErrorResponse{}
+		if rec := doWith(t, s, tt.method, tt.path, tt.body, &resp); rec.Code != http.StatusInternalServerError || resp.Error.Field != "" {
+			t.Errorf("%s %s with a corrupt profile: status %d, error %+v; want 500", tt.method, tt.path, rec.Code, resp.Error)
+		}
+	}
+}
+
+func TestRoutingErrors(t *testing.T) {
+	var resp ErrorResponse
+	rec := do(t, http.MethodGet, "/v1/score", "", &resp)
+	if rec.Code != http.StatusMethodNotAllowed || rec.Header().Get("Allow") != http.MethodPost {
+		t.Errorf("GET /v1/score: status %d, Allow %q", rec.Code, rec.Header().Get("Allow"))
+	}
+	rec = do(t, http.MethodGet, "/v2/missing", "", &resp)
+	if rec.Code != http.StatusNotFound || resp.Error.Message == "" {
+		t.Errorf("unknown path: status %d, error %+v", rec.Code, resp.Error)
+	}
+}
+
+func TestBodyTooLarge(t *testing.T) {
+	var resp ErrorResponse
+	body := `{"scores": {"Floor Level": 80}, "scenario": "` + strings.Repeat("x", 5000) + `"}`
+	rec := do(t, http.MethodPost, "/v1/score", body, &resp)
+	if rec.Code != http.StatusRequestEntityTooLarge {
+		t.Errorf("status = %d, want 413", rec.Code)
+	}
+}
+
+// TestOpenAPIDocument keeps api/openapi.json in sync with the routes; run with -update to regenerate.
+func TestOpenAPIDocument(t *testing.T) {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(OpenAPI()); err != nil {
+		t.Fatal(err)
+	}
+	const path = "../../api/openapi.json"
+	if *update {
+		if err := os.WriteFile(path, buf.Bytes(), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("%s가 최신이 아닙니다; go test ./pkg/server -update로 갱신하세요", path)
+	}
+
+	var served map[string]interface{}
+	if rec := do(t, http.MethodGet, "/openapi.json", "", &served); rec.Code != http.StatusOK || served["openapi"] != openAPIVersion {
+		t.Errorf("GET /openapi.json: status %d, openapi %v", rec.Code, served["openapi"])
+	}
+	for _, rt := range Routes() {
+		paths := served["paths"].(map[string]interface{})
+		if _, ok := paths[rt.Path].(map[string]interface{})[strings.ToLower(rt.Method)]; !ok {
+			t.Errorf("문서에 %s %s 없음", rt.Method, rt.Path)
+		}
+	}
+}
@@ -1,6 +1,8 @@
 // Package shared provides common types and utilities used across multiple packages.
 package shared
 
+import "apart_score/pkg/metadata"
+
 // ScoreValue represents an apartment score using integer arithmetic for precision.
 type ScoreValue int
 
@@ -14,6 +16,14 @@ const (
 	WeightScale = 1000
 )
 
+// MissingScore marks a factor whose value is unknown, as opposed to a genuine score of 0.
+const MissingScore ScoreValue = -1
+
+// IsMissing reports whether the score is the MissingScore marker.
+func (s ScoreValue) IsMissing() bool {
+	return s == MissingScore
+}
+
 // ToFloat converts ScoreValue to float64 for external use.
 func (s ScoreValue) ToFloat() float64 {
 	return float64(s) / ScoreScale
@@ -29,15 +39,16 @@ func WeightFromFloat(f float64) Weight {
 }
 
 // ScoreArray represents an array of scores indexed by MetadataType.
-type ScoreArray [14]ScoreValue
+// Its capacity covers built-in and runtime-registered metadata types.
+type ScoreArray [metadata.MaxMetadataTypes]ScoreValue
 
 // WeightArray represents an array of weights indexed by MetadataType.
-type WeightArray [14]Weight
+type WeightArray [metadata.MaxMetadataTypes]Weight
 
 // NewScoreArrayFromMap creates a ScoreArray from a map using int as key.
 func NewScoreArrayFromMap(scores map[int]ScoreValue) ScoreArray {
 	var arr ScoreArray
-	for i := 0; i < 14; i++ {
+	for i := range arr {
 		if score, exists := scores[i]; exists {
 			arr[i] = score
 		}
@@ -48,7 +59,7 @@ func NewScoreArrayFromMap(scores map[int]ScoreValue) ScoreArray {
 // NewWeightArrayFromMap creates a WeightArray from a map using int as key.
 func NewWeightArrayFromMap(weights map[int]Weight) WeightArray {
 	var arr WeightArray
-	for i := 0; i < 14; i++ {
+	for i := range arr {
 		if weight, exists := weights[i]; exists {
 			arr[i] = weight
 		}
@@ -1,8 +1,12 @@
 package shared
 
-import "apart_score/pkg/metadata"
+import (
+	"apart_score/pkg/metadata"
+	"sort"
+)
 
-// NormalizeWeights normalizes weights to sum to WeightScale using integer arithmetic.
+// NormalizeWeights normalizes weights to sum to exactly WeightScale using integer arithmetic.
+// Rounding is distributed by largest remainder, ties going to the lower metadata type.
 func NormalizeWeights(weights map[metadata.MetadataType]Weight) map[metadata.MetadataType]Weight {
 	total := Weight(0)
 	for _, w := range weights {
@@ -12,9 +16,24 @@ func NormalizeWeights(weights map[metadata.MetadataType]Weight) map[metadata.Met
 		return weights
 	}
 	normalized := make(map[metadata.MetadataType]Weight)
+	order := make([]metadata.MetadataType, 0, len(weights))
+	assigned := Weight(0)
 	for mt, w := range weights {
-		scaled := (w*WeightScale + total/2) / total
-		normalized[mt] = scaled
+		normalized[mt] = w * WeightScale / total
+		assigned += normalized[mt]
+		order = append(order, mt)
+	}
+	// 나머지가 큰 요소부터 1씩 더해 합계를 정확히 맞춤
+	sort.Slice(order, func(i, j int) bool {
+		ri, rj := weights[order[i]]*WeightScale%total, weights[order[j]]*WeightScale%total
+		if ri != rj {
+			return ri > rj
+		}
+		return order[i] < order[j]
+	})
+	for i := 0; assigned < WeightScale; i++ {
+		normalized[order[i]]++
+		assigned++
 	}
 	return normalized
 }